@@ -70,6 +70,11 @@ func (h *EventHandler) Dispatch(ctx context.Context, e bbb.Event) error {
 	case *bbb.UserLeftMeetingEvent:
 		return h.onUserLeftMeeting(ctx, event)
 
+	case *bbb.UserBroadcastCamStartedEvent:
+		return h.onUserBroadcastCamStarted(ctx, event)
+	case *bbb.UserBroadcastCamStoppedEvent:
+		return h.onUserBroadcastCamStopped(ctx, event)
+
 	default:
 		log.Error().
 			Str("type", fmt.Sprintf("%T", e)).
@@ -180,3 +185,51 @@ func (h *EventHandler) onUserLeftMeeting(
 
 	return nil
 }
+
+// handle event: UserBroadcastCamStarted
+func (h *EventHandler) onUserBroadcastCamStarted(
+	ctx context.Context,
+	e *bbb.UserBroadcastCamStartedEvent,
+) error {
+	log.Info().
+		Str("internalUserID", e.InternalUserID).
+		Str("internalMeetingID", e.InternalMeetingID).
+		Msg("user started webcam")
+
+	_, err := h.api.AgentRPC(
+		ctx, api.RPCMeetingSetVideo(&api.MeetingSetVideoRequest{
+			InternalMeetingID: e.InternalMeetingID,
+			InternalUserID:    e.InternalUserID,
+			Stream:            e.Stream,
+			Broadcasting:      true,
+		}))
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// handle event: UserBroadcastCamStopped
+func (h *EventHandler) onUserBroadcastCamStopped(
+	ctx context.Context,
+	e *bbb.UserBroadcastCamStoppedEvent,
+) error {
+	log.Info().
+		Str("internalUserID", e.InternalUserID).
+		Str("internalMeetingID", e.InternalMeetingID).
+		Msg("user stopped webcam")
+
+	_, err := h.api.AgentRPC(
+		ctx, api.RPCMeetingSetVideo(&api.MeetingSetVideoRequest{
+			InternalMeetingID: e.InternalMeetingID,
+			InternalUserID:    e.InternalUserID,
+			Stream:            e.Stream,
+			Broadcasting:      false,
+		}))
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -199,6 +199,13 @@ func (c *Cli) showBackends(ctx *cli.Context) error {
 			ratio)
 		fmt.Printf("  LoadFactor:\t %v\n", b.LoadFactor)
 		fmt.Printf("  Latency:\t %v\n", b.Latency)
+		if b.Drain != nil {
+			fmt.Printf("  Drain:\t %d/%d ended, %d remaining (since %v)\n",
+				b.Drain.MeetingsEnded,
+				b.Drain.MeetingsTotal,
+				b.Drain.MeetingsRemaining,
+				b.Drain.StartedAt)
+		}
 		if b.NodeState == "error" && b.LastError != nil {
 			fmt.Println("  LastError:", *b.LastError)
 		}
@@ -230,6 +237,18 @@ func (c *Cli) disableBackend(ctx *cli.Context) error {
 	return c.setBackendAdminState(ctx, host, dry, "stopped")
 }
 
+// drain a backend means setting the admin state
+// to draining
+func (c *Cli) drainBackend(ctx *cli.Context) error {
+	dry := ctx.Bool("dry")
+	// Args should be host
+	if ctx.NArg() < 1 {
+		return fmt.Errorf("require: <host>")
+	}
+	host := ctx.Args().Get(0)
+	return c.setBackendAdminState(ctx, host, dry, "draining")
+}
+
 func (c *Cli) setBackendAdminState(
 	ctx *cli.Context,
 	host string,
@@ -10,6 +10,7 @@ import (
 
 	"github.com/urfave/cli/v2"
 
+	"github.com/b3scale/b3scale/pkg/cluster"
 	"github.com/b3scale/b3scale/pkg/config"
 	"github.com/b3scale/b3scale/pkg/http/api"
 	"github.com/b3scale/b3scale/pkg/http/api/client"
@@ -96,6 +97,81 @@ func NewCli() *Cli {
 						Usage:  "show frontend settings",
 						Action: c.showRecording,
 					},
+					{
+						Name:   "routing",
+						Usage:  "show why a backend was selected for a meeting",
+						Action: c.showRouting,
+					},
+					{
+						Name:         "maintenance",
+						Aliases:      []string{"maintenance-windows"},
+						Usage:        "show scheduled maintenance windows, optionally for a backend",
+						Action:       c.showMaintenanceWindows,
+						BashComplete: c.completeBackend,
+						Flags: []cli.Flag{
+							&cli.StringFlag{
+								Name:  "state",
+								Usage: "only show windows in this state (scheduled, active, finished, canceled)",
+							},
+							&cli.BoolFlag{
+								Name:  "all",
+								Usage: "include finished and canceled windows",
+							},
+						},
+					},
+					{
+						Name:   "commands",
+						Usage:  "show the command queue",
+						Action: c.showCommands,
+						Flags: []cli.Flag{
+							&cli.StringFlag{
+								Name:  "state",
+								Usage: "only show commands in this state (requested, success, error, dead)",
+							},
+							&cli.StringFlag{
+								Name:  "action",
+								Usage: "only show commands with this action",
+							},
+						},
+					},
+					{
+						Name:   "webhooks",
+						Usage:  "show webhooks subscribed to cluster events",
+						Action: c.showWebhooks,
+					},
+					{
+						Name:   "schedules",
+						Usage:  "show the schedules of recurring commands",
+						Action: c.showCommandSchedules,
+					},
+					{
+						Name:   "history",
+						Usage:  "show a summary of past meetings",
+						Action: c.showMeetingsHistory,
+						Flags: []cli.Flag{
+							&cli.StringFlag{
+								Name:    "frontend",
+								Aliases: []string{"fe"},
+								Usage:   "only include meetings of the frontend (key)",
+							},
+							&cli.StringFlag{
+								Name:  "backend",
+								Usage: "only include meetings of the backend (host)",
+							},
+							&cli.StringFlag{
+								Name:  "from",
+								Usage: "start of the summary as date or RFC3339 timestamp (default: 7 days ago)",
+							},
+							&cli.StringFlag{
+								Name:  "to",
+								Usage: "end of the summary as date or RFC3339 timestamp (default: now)",
+							},
+							&cli.BoolFlag{
+								Name:  "hourly",
+								Usage: "aggregate by hour instead of by day",
+							},
+						},
+					},
 				},
 			},
 			{
@@ -147,6 +223,82 @@ func NewCli() *Cli {
 						Usage:  "set visibility for a recording",
 						Action: c.setRecordingVisibility,
 					},
+					{
+						Name:         "maintenance",
+						Aliases:      []string{"maintenance-window"},
+						Usage:        "schedule a maintenance window for a backend",
+						Action:       c.addMaintenanceWindow,
+						BashComplete: c.completeBackend,
+						Flags: []cli.Flag{
+							&cli.StringFlag{
+								Name:     "start",
+								Usage:    "start of the maintenance (RFC3339 or 'YYYY-MM-DD hh:mm' local time)",
+								Required: true,
+							},
+							&cli.StringFlag{
+								Name:  "end",
+								Usage: "end of the maintenance (RFC3339 or 'YYYY-MM-DD hh:mm' local time)",
+							},
+							&cli.DurationFlag{
+								Name:  "duration",
+								Usage: "duration of the maintenance, if no end is given",
+								Value: time.Hour,
+							},
+							&cli.BoolFlag{
+								Name:  "stop",
+								Usage: "stop the backend instead of draining it",
+							},
+							&cli.StringFlag{
+								Name:  "comment",
+								Usage: "a note about the maintenance",
+							},
+						},
+					},
+					{
+						Name:   "webhook",
+						Usage:  "post cluster events to a webhook <url>",
+						Action: c.setWebhook,
+						Flags: []cli.Flag{
+							&cli.StringSliceFlag{
+								Name:    "event",
+								Aliases: []string{"e"},
+								Usage:   "subscribe to this event type; all events if not set",
+							},
+							&cli.StringFlag{
+								Name:    "frontend",
+								Aliases: []string{"f"},
+								Usage:   "only post events of the frontend with this key",
+							},
+							&cli.StringFlag{
+								Name:  "secret",
+								Usage: "the secret for signing the requests; generated if empty",
+							},
+							&cli.StringFlag{
+								Name:  "comment",
+								Usage: "a note about the webhook",
+							},
+						},
+					},
+					{
+						Name:   "schedule",
+						Usage:  "queue a command <action> periodically (end_all_meetings, reconcile_meetings, collect_garbage)",
+						Action: c.addCommandSchedule,
+						Flags: []cli.Flag{
+							&cli.StringFlag{
+								Name:     "cron",
+								Usage:    "cron expression in local time of the server, e.g. '0 2 * * *' or '@weekly'",
+								Required: true,
+							},
+							&cli.StringFlag{
+								Name:  "backend",
+								Usage: "the backend host for end_all_meetings or reconcile_meetings",
+							},
+							&cli.StringFlag{
+								Name:  "comment",
+								Usage: "a note about the schedule",
+							},
+						},
+					},
 				},
 			},
 			{
@@ -176,6 +328,11 @@ func NewCli() *Cli {
 						Usage:  "delete frontend",
 						Action: c.deleteFrontend,
 					},
+					{
+						Name:   "webhook",
+						Usage:  "delete webhook by id",
+						Action: c.deleteWebhook,
+					},
 				},
 			},
 			{
@@ -224,6 +381,79 @@ func NewCli() *Cli {
 					},
 				},
 			},
+			{
+				Name:  "cancel",
+				Usage: "cancel maintenance windows, pending commands or schedules",
+				Flags: []cli.Flag{
+					&cli.BoolFlag{
+						Name:  "dry",
+						Usage: "perform a dry run",
+					},
+				},
+				Subcommands: []*cli.Command{
+					{
+						Name:    "maintenance",
+						Aliases: []string{"maintenance-window"},
+						Usage:   "cancel maintenance window by id",
+						Action:  c.cancelMaintenanceWindow,
+					},
+					{
+						Name:   "command",
+						Usage:  "cancel pending command by id",
+						Action: c.cancelCommand,
+					},
+					{
+						Name:   "schedule",
+						Usage:  "remove command schedule by id",
+						Action: c.deleteCommandSchedule,
+					},
+				},
+			},
+			{
+				Name:  "follow",
+				Usage: "follow the progress of a command until it is finished",
+				Subcommands: []*cli.Command{
+					{
+						Name:   "command",
+						Usage:  "follow command by id",
+						Action: c.followCommand,
+					},
+				},
+			},
+			{
+				Name:  "requeue",
+				Usage: "retry a failed or dead command",
+				Flags: []cli.Flag{
+					&cli.BoolFlag{
+						Name:  "dry",
+						Usage: "perform a dry run",
+					},
+				},
+				Subcommands: []*cli.Command{
+					{
+						Name:   "command",
+						Usage:  "requeue command by id",
+						Action: c.requeueCommand,
+					},
+				},
+			},
+			{
+				Name:  "drain",
+				Usage: "stop new meetings on a backend and end meetings once they are empty",
+				Flags: []cli.Flag{
+					&cli.BoolFlag{
+						Name:  "dry",
+						Usage: "perform a dry run",
+					},
+				},
+				Subcommands: []*cli.Command{
+					{
+						Name:   "backend",
+						Usage:  "drain backend",
+						Action: c.drainBackend,
+					},
+				},
+			},
 			{
 				Name:  "end",
 				Usage: "force ending things on a backend",
@@ -232,6 +462,37 @@ func NewCli() *Cli {
 						Name:   "meetings",
 						Usage:  "end all meetings on a given <host>",
 						Action: c.endAllMeetings,
+						Flags: []cli.Flag{
+							&cli.StringFlag{
+								Name:  "at",
+								Usage: "end the meetings later (RFC3339 or 'YYYY-MM-DD hh:mm' local time)",
+							},
+						},
+					},
+				},
+			},
+			{
+				Name:  "reconcile",
+				Usage: "compare the state with the backends",
+				Subcommands: []*cli.Command{
+					{
+						Name:   "meetings",
+						Usage:  "report meetings unknown to b3scale, missing on the backend or with a different frontend",
+						Action: c.reconcileMeetings,
+						Flags: []cli.Flag{
+							&cli.StringFlag{
+								Name:  "backend",
+								Usage: "only check the backend with the host",
+							},
+							&cli.BoolFlag{
+								Name:  "adopt",
+								Usage: "add unknown meetings to the state",
+							},
+							&cli.BoolFlag{
+								Name:  "end",
+								Usage: "end unknown meetings on the backend",
+							},
+						},
 					},
 				},
 			},
@@ -283,6 +544,26 @@ func NewCli() *Cli {
 					},
 				},
 			},
+			{
+				Name:   "simulate-create",
+				Usage:  "show which backend would be selected for a new meeting",
+				Action: c.simulateCreate,
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Required: true,
+						Name:     "frontend",
+						Usage:    "the frontend to create the meeting for",
+					},
+					&cli.StringSliceFlag{
+						Name:  "param",
+						Usage: "key=value pairs passed to the create request, e.g. meta_b3scale-tags=sip",
+					},
+					&cli.BoolFlag{
+						Name:  "json",
+						Usage: "Return json output",
+					},
+				},
+			},
 			{
 				Name:  "db",
 				Usage: "control database operations on the server",
@@ -549,31 +830,30 @@ func (c *Cli) endAllMeetings(ctx *cli.Context) error {
 		return fmt.Errorf("no such backend")
 	}
 
+	if ctx.IsSet("at") {
+		at, err := parseTimeArg(ctx.String("at"))
+		if err != nil {
+			return fmt.Errorf("invalid time: %w", err)
+		}
+		cmd := cluster.EndAllMeetings(&cluster.EndAllMeetingsRequest{
+			BackendID: backend.ID,
+		})
+		cmd.NotBefore = at
+		cmd, err = client.CommandCreate(ctx.Context, cmd)
+		if err != nil {
+			return err
+		}
+		fmt.Println("Scheduled:", cmd.ID, cmd.Action, cmd.NotBefore.Local())
+		return nil
+	}
+
 	cmd, err := client.BackendMeetingsEnd(ctx.Context, backend.ID)
 	if err != nil {
 		return err
 	}
 	fmt.Println("Dispatch:", cmd.Action, cmd.Params)
 
-	// Poll state changes
-	state := cmd.State
-	for {
-		update, err := client.CommandRetrieve(ctx.Context, cmd.ID)
-		if err != nil {
-			return err
-		}
-		if update.State != state {
-			fmt.Println("State:", update.State)
-		}
-		if update.State == "success" || update.State == "error" {
-			fmt.Println("Result:", update.Result)
-			break
-		}
-
-		state = update.State
-		time.Sleep(500 * time.Millisecond)
-	}
-	return nil
+	return followCommand(ctx, client, cmd)
 }
 
 // show the current version
@@ -0,0 +1,314 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/urfave/cli/v2"
+
+	"github.com/b3scale/b3scale/pkg/cluster"
+	"github.com/b3scale/b3scale/pkg/http/api"
+	"github.com/b3scale/b3scale/pkg/store"
+)
+
+// showCommands lists the commands in the queue,
+// optionally filtered by state and action.
+func (c *Cli) showCommands(ctx *cli.Context) error {
+	client, err := apiClient(ctx)
+	if err != nil {
+		return err
+	}
+
+	query := url.Values{}
+	if state := ctx.String("state"); state != "" {
+		query.Set("state", state)
+	}
+	if action := ctx.String("action"); action != "" {
+		query.Set("action", action)
+	}
+
+	cmds, err := client.CommandsList(ctx.Context, query)
+	if err != nil {
+		return err
+	}
+
+	if ctx.Bool("json") {
+		buf, _ := json.MarshalIndent(cmds, "", "   ")
+		fmt.Println(string(buf))
+		return nil
+	}
+
+	for _, cmd := range cmds {
+		fmt.Printf("%s\t%s\t%s\n", cmd.ID, cmd.Action, cmd.State)
+		fmt.Printf("  Attempts:\t %d/%d\n", cmd.Attempts, cmd.MaxAttempts)
+		fmt.Printf("  Created:\t %v\n", cmd.CreatedAt.Local())
+		if cmd.NotBefore.After(time.Now()) {
+			fmt.Printf("  Not Before:\t %v\n", cmd.NotBefore.Local())
+		}
+		if cmd.LastError != nil {
+			fmt.Printf("  Last Error:\t %s\n", *cmd.LastError)
+		}
+		fmt.Println("")
+	}
+	return nil
+}
+
+// requeueCommand puts a failed or dead command
+// back into the queue.
+func (c *Cli) requeueCommand(ctx *cli.Context) error {
+	dry := ctx.Bool("dry")
+	// Args should be the command id
+	if ctx.NArg() < 1 {
+		return fmt.Errorf("require: <id>")
+	}
+	id := ctx.Args().Get(0)
+
+	client, err := apiClient(ctx)
+	if err != nil {
+		return err
+	}
+	if dry {
+		fmt.Println("skipping requeue command (dry run)")
+		return nil
+	}
+	cmd, err := client.CommandRequeue(ctx.Context, id)
+	if err != nil {
+		return err
+	}
+	fmt.Println("requeued command:", cmd.ID, cmd.Action)
+	return nil
+}
+
+// followCommand polls a command and prints the progress
+// until the command is finished.
+func (c *Cli) followCommand(ctx *cli.Context) error {
+	// Args should be the command id
+	if ctx.NArg() < 1 {
+		return fmt.Errorf("require: <id>")
+	}
+	client, err := apiClient(ctx)
+	if err != nil {
+		return err
+	}
+	cmd, err := client.CommandRetrieve(ctx.Context, ctx.Args().Get(0))
+	if err != nil {
+		return err
+	}
+	fmt.Println("Command:", cmd.ID, cmd.Action)
+	fmt.Println("State:", cmd.State)
+	return followCommand(ctx, client, cmd)
+}
+
+// followCommand polls the state and progress of
+// a command until it is finished.
+func followCommand(
+	ctx *cli.Context,
+	client api.Client,
+	cmd *store.Command,
+) error {
+	state := cmd.State
+	progress := ""
+	for {
+		update, err := client.CommandRetrieve(ctx.Context, cmd.ID)
+		if err != nil {
+			return err
+		}
+		if update.State != state {
+			fmt.Println("State:", update.State)
+		}
+		if msg := progressMessage(update.Result); msg != "" && msg != progress {
+			fmt.Println("Progress:", msg)
+			progress = msg
+		}
+		if update.CancelRequested && !cmd.CancelRequested {
+			fmt.Println("Cancel requested")
+		}
+
+		switch update.State {
+		case store.CommandStateSuccess,
+			store.CommandStateError,
+			store.CommandStateDead,
+			store.CommandStateCanceled:
+			if update.LastError != nil && update.State != store.CommandStateSuccess {
+				fmt.Println("Error:", *update.LastError)
+			}
+			buf, _ := json.MarshalIndent(update.Result, "", "   ")
+			fmt.Println("Result:", string(buf))
+			return nil
+		}
+
+		state = update.State
+		cmd = update
+		time.Sleep(500 * time.Millisecond)
+	}
+}
+
+// progressMessage extracts the message of a
+// command progress result.
+func progressMessage(result interface{}) string {
+	r, ok := result.(map[string]interface{})
+	if !ok {
+		return ""
+	}
+	msg, _ := r["message"].(string)
+	return msg
+}
+
+// cancelCommand cancels a pending command
+func (c *Cli) cancelCommand(ctx *cli.Context) error {
+	dry := ctx.Bool("dry")
+	// Args should be the command id
+	if ctx.NArg() < 1 {
+		return fmt.Errorf("require: <id>")
+	}
+	id := ctx.Args().Get(0)
+
+	client, err := apiClient(ctx)
+	if err != nil {
+		return err
+	}
+	if dry {
+		fmt.Println("skipping cancel command (dry run)")
+		return nil
+	}
+	cmd, err := client.CommandCancel(ctx.Context, id)
+	if err != nil {
+		return err
+	}
+	if cmd.CancelRequested {
+		fmt.Println("command is running, cancel requested")
+		return nil
+	}
+	fmt.Println("command", cmd.State)
+	return nil
+}
+
+// showCommandSchedules lists the command schedules
+func (c *Cli) showCommandSchedules(ctx *cli.Context) error {
+	client, err := apiClient(ctx)
+	if err != nil {
+		return err
+	}
+	schedules, err := client.CommandSchedulesList(ctx.Context)
+	if err != nil {
+		return err
+	}
+
+	if ctx.Bool("json") {
+		buf, _ := json.MarshalIndent(schedules, "", "   ")
+		fmt.Println(string(buf))
+		return nil
+	}
+
+	for _, s := range schedules {
+		fmt.Printf("%s\t%s\t%s\n", s.ID, s.Action, s.Schedule)
+		fmt.Printf("  Next Run:\t %v\n", s.NextRunAt.Local())
+		if s.LastRunAt != nil {
+			fmt.Printf("  Last Run:\t %v\n", s.LastRunAt.Local())
+		}
+		if s.Params != nil {
+			fmt.Printf("  Params:\t %v\n", s.Params)
+		}
+		if s.Comment != "" {
+			fmt.Printf("  Comment:\t %s\n", s.Comment)
+		}
+		fmt.Println("")
+	}
+	return nil
+}
+
+// addCommandSchedule queues a command periodically
+func (c *Cli) addCommandSchedule(ctx *cli.Context) error {
+	dry := ctx.Bool("dry")
+	// Args should be the action
+	if ctx.NArg() < 1 {
+		return fmt.Errorf("require: <action>")
+	}
+	action := ctx.Args().Get(0)
+
+	client, err := apiClient(ctx)
+	if err != nil {
+		return err
+	}
+
+	var params interface{}
+	switch action {
+	case cluster.CmdEndAllMeetings:
+		host := ctx.String("backend")
+		if host == "" {
+			return fmt.Errorf("%s requires --backend", action)
+		}
+		backend, err := getBackendByHost(ctx.Context, client, host)
+		if err != nil {
+			return err
+		}
+		if backend == nil {
+			return fmt.Errorf("backend not found")
+		}
+		params = &cluster.EndAllMeetingsRequest{
+			BackendID: backend.ID,
+		}
+	case cluster.CmdReconcileMeetings:
+		req := &cluster.ReconcileMeetingsRequest{}
+		if host := ctx.String("backend"); host != "" {
+			backend, err := getBackendByHost(ctx.Context, client, host)
+			if err != nil {
+				return err
+			}
+			if backend == nil {
+				return fmt.Errorf("backend not found")
+			}
+			req.BackendID = backend.ID
+		}
+		params = req
+	case cluster.CmdCollectGarbage:
+	default:
+		return fmt.Errorf("action can not be scheduled: %s", action)
+	}
+
+	schedule := &store.CommandSchedule{
+		Action:   action,
+		Params:   params,
+		Schedule: ctx.String("cron"),
+		Comment:  ctx.String("comment"),
+	}
+	if dry {
+		fmt.Printf("skipping schedule %s for %s (dry run)\n",
+			schedule.Schedule, action)
+		return nil
+	}
+	schedule, err = client.CommandScheduleCreate(ctx.Context, schedule)
+	if err != nil {
+		return err
+	}
+	fmt.Println("scheduled command:", schedule.ID)
+	fmt.Println("next run:", schedule.NextRunAt.Local())
+	return nil
+}
+
+// deleteCommandSchedule removes a command schedule
+func (c *Cli) deleteCommandSchedule(ctx *cli.Context) error {
+	dry := ctx.Bool("dry")
+	// Args should be the schedule id
+	if ctx.NArg() < 1 {
+		return fmt.Errorf("require: <id>")
+	}
+	id := ctx.Args().Get(0)
+
+	client, err := apiClient(ctx)
+	if err != nil {
+		return err
+	}
+	if dry {
+		fmt.Println("skipping cancel schedule (dry run)")
+		return nil
+	}
+	schedule, err := client.CommandScheduleDelete(ctx.Context, id)
+	if err != nil {
+		return err
+	}
+	fmt.Println("removed schedule:", schedule.ID)
+	return nil
+}
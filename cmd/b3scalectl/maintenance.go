@@ -0,0 +1,166 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/urfave/cli/v2"
+
+	"github.com/b3scale/b3scale/pkg/store"
+)
+
+// parseTimeArg accepts RFC3339 timestamps
+// and local times like 2006-01-02 15:04
+func parseTimeArg(value string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	return time.ParseInLocation("2006-01-02 15:04", value, time.Local)
+}
+
+// showMaintenanceWindows lists the open maintenance
+// windows, optionally for a single backend.
+func (c *Cli) showMaintenanceWindows(ctx *cli.Context) error {
+	client, err := apiClient(ctx)
+	if err != nil {
+		return err
+	}
+
+	query := url.Values{}
+	if ctx.NArg() > 0 {
+		host := ctx.Args().Get(0)
+		backend, err := getBackendByHost(ctx.Context, client, host)
+		if err != nil {
+			return err
+		}
+		if backend == nil {
+			return fmt.Errorf("backend not found")
+		}
+		query.Set("backend_id", backend.ID)
+	}
+	if state := ctx.String("state"); state != "" {
+		query.Set("state", state)
+	}
+
+	windows, err := client.MaintenanceWindowsList(ctx.Context, query)
+	if err != nil {
+		return err
+	}
+
+	if ctx.Bool("json") {
+		buf, _ := json.MarshalIndent(windows, "", "   ")
+		fmt.Println(string(buf))
+		return nil
+	}
+
+	for _, w := range windows {
+		if !ctx.Bool("all") && !w.IsOpen() {
+			continue
+		}
+		fmt.Printf("%s\n  Backend:\t %s\n", w.ID, w.BackendID)
+		fmt.Printf("  State:\t %s\t", w.State)
+		fmt.Printf("  AdminState:\t %s\n", w.AdminState)
+		fmt.Printf("  Starts:\t %v\n", w.StartsAt.Local())
+		fmt.Printf("  Ends:\t\t %v\n", w.EndsAt.Local())
+		if w.Comment != "" {
+			fmt.Printf("  Comment:\t %s\n", w.Comment)
+		}
+		fmt.Println("")
+	}
+
+	return nil
+}
+
+// addMaintenanceWindow schedules a maintenance
+// window for a backend
+func (c *Cli) addMaintenanceWindow(ctx *cli.Context) error {
+	dry := ctx.Bool("dry")
+	// Args should be host
+	if ctx.NArg() < 1 {
+		return fmt.Errorf("require: <host>")
+	}
+	host := ctx.Args().Get(0)
+	if !strings.HasSuffix(host, "/") {
+		host += "/"
+	}
+
+	startsAt, err := parseTimeArg(ctx.String("start"))
+	if err != nil {
+		return fmt.Errorf("invalid start: %w", err)
+	}
+	var endsAt time.Time
+	if ctx.IsSet("end") {
+		endsAt, err = parseTimeArg(ctx.String("end"))
+		if err != nil {
+			return fmt.Errorf("invalid end: %w", err)
+		}
+	} else {
+		endsAt = startsAt.Add(ctx.Duration("duration"))
+	}
+
+	adminState := "draining"
+	if ctx.Bool("stop") {
+		adminState = "stopped"
+	}
+
+	client, err := apiClient(ctx)
+	if err != nil {
+		return err
+	}
+	backend, err := getBackendByHost(ctx.Context, client, host)
+	if err != nil {
+		return err
+	}
+	if backend == nil {
+		return fmt.Errorf("backend not found")
+	}
+
+	window := &store.MaintenanceWindow{
+		BackendID:  backend.ID,
+		AdminState: adminState,
+		Comment:    ctx.String("comment"),
+		StartsAt:   startsAt,
+		EndsAt:     endsAt,
+	}
+	if dry {
+		fmt.Printf("skipping maintenance window %v - %v (dry run)\n",
+			startsAt.Local(), endsAt.Local())
+		return nil
+	}
+
+	window, err = client.MaintenanceWindowCreate(ctx.Context, window)
+	if err != nil {
+		return err
+	}
+	fmt.Println("scheduled maintenance window:", window.ID)
+	return nil
+}
+
+// cancelMaintenanceWindow cancels a maintenance window.
+// An active window will make the backend ready again.
+func (c *Cli) cancelMaintenanceWindow(ctx *cli.Context) error {
+	dry := ctx.Bool("dry")
+	// Args should be the window id
+	if ctx.NArg() < 1 {
+		return fmt.Errorf("require: <id>")
+	}
+	id := ctx.Args().Get(0)
+
+	client, err := apiClient(ctx)
+	if err != nil {
+		return err
+	}
+	if dry {
+		fmt.Println("skipping cancel maintenance window (dry run)")
+		return nil
+	}
+	window, err := client.MaintenanceWindowCancel(ctx.Context, id)
+	if err != nil {
+		return err
+	}
+	fmt.Println("maintenance window", window.State)
+	return nil
+}
@@ -1,11 +1,17 @@
 package main
 
 import (
+	"encoding/json"
 	"fmt"
+	"net/url"
 	"strings"
+	"time"
 
 	"github.com/b3scale/b3scale/pkg/bbb"
+	"github.com/b3scale/b3scale/pkg/cluster"
+	"github.com/b3scale/b3scale/pkg/http/api"
 	"github.com/b3scale/b3scale/pkg/http/auth"
+	"github.com/b3scale/b3scale/pkg/store"
 	"github.com/urfave/cli/v2"
 )
 
@@ -99,3 +105,213 @@ func (c *Cli) createMeeting(ctx *cli.Context) error {
 
 	return nil
 }
+
+// showRouting displays the routing record of a meeting
+func (c *Cli) showRouting(ctx *cli.Context) error {
+	meetingID := ctx.Args().Get(0)
+	if meetingID == "" {
+		return fmt.Errorf("require: <meeting>")
+	}
+
+	client, err := apiClient(ctx)
+	if err != nil {
+		return err
+	}
+	record, err := client.MeetingRoutingRetrieve(ctx.Context, meetingID)
+	if err != nil {
+		return err
+	}
+
+	if ctx.Bool("json") {
+		buf, _ := json.MarshalIndent(record, "", "   ")
+		fmt.Println(string(buf))
+		return nil
+	}
+
+	fmt.Println("Meeting:", meetingID)
+	printRoutingRecord(record)
+
+	return nil
+}
+
+// simulateCreate selects a backend for a create request
+// of a frontend, without creating the meeting.
+func (c *Cli) simulateCreate(ctx *cli.Context) error {
+	feKey := ctx.String("frontend")
+	if feKey == "" {
+		return fmt.Errorf("a frontend is required")
+	}
+
+	// Convert bbb params
+	bbbParams := bbb.Params{
+		bbb.ParamMeetingID: auth.GenerateNonce(23),
+	}
+	for _, param := range ctx.StringSlice("param") {
+		tokens := strings.SplitN(param, "=", 2)
+		if len(tokens) != 2 {
+			return fmt.Errorf("invalid param: %s", param)
+		}
+		bbbParams[tokens[0]] = tokens[1]
+	}
+
+	client, err := apiClient(ctx)
+	if err != nil {
+		return err
+	}
+	record, err := client.RoutingSimulate(ctx.Context, &api.RoutingSimulation{
+		FrontendKey: feKey,
+		Params:      bbbParams,
+	})
+	if err != nil {
+		return err
+	}
+
+	if ctx.Bool("json") {
+		buf, _ := json.MarshalIndent(record, "", "   ")
+		fmt.Println(string(buf))
+		return nil
+	}
+
+	fmt.Println("Frontend:", feKey)
+	printRoutingRecord(record)
+
+	return nil
+}
+
+// printRoutingRecord displays the steps and candidates
+// of a backend selection
+func printRoutingRecord(record *store.RoutingRecord) {
+	fmt.Println("Routed at:", record.CreatedAt)
+	for i, step := range record.Steps {
+		fmt.Printf("%d. %s (%d backends)\n",
+			i+1, step.Middleware, len(step.Backends))
+		for _, r := range step.Removed {
+			fmt.Printf("     - %s: %s\n", r.Backend, r.Reason)
+		}
+		if step.Error != "" {
+			fmt.Println("     Error:", step.Error)
+		}
+	}
+
+	fmt.Println("Candidates:")
+	for _, candidate := range record.Candidates {
+		selected := " "
+		if record.BackendID != nil && *record.BackendID == candidate.BackendID {
+			selected = "*"
+		}
+		fmt.Printf("  %s %s\t stress: %.02f\n",
+			selected, candidate.Host, candidate.Stress)
+	}
+	if record.Error != "" {
+		fmt.Println("Error:", record.Error)
+	}
+}
+
+// reconcileMeetings compares the meetings in the state
+// with the meetings running on the backends.
+func (c *Cli) reconcileMeetings(ctx *cli.Context) error {
+	if ctx.Bool("adopt") && ctx.Bool("end") {
+		return fmt.Errorf("use either --adopt or --end")
+	}
+	client, err := apiClient(ctx)
+	if err != nil {
+		return err
+	}
+
+	req := &cluster.ReconcileMeetingsRequest{}
+	if host := ctx.String("backend"); host != "" {
+		backend, err := getBackendByHost(ctx.Context, client, host)
+		if err != nil {
+			return err
+		}
+		if backend == nil {
+			return fmt.Errorf("no such backend")
+		}
+		req.BackendID = backend.ID
+	}
+	if ctx.Bool("adopt") {
+		req.Unknown = cluster.ReconcileAdopt
+	}
+	if ctx.Bool("end") {
+		req.Unknown = cluster.ReconcileEnd
+	}
+
+	cmd, err := client.MeetingsReconcile(ctx.Context, req)
+	if err != nil {
+		return err
+	}
+	fmt.Println("Dispatch:", cmd.Action, cmd.Params)
+
+	return followCommand(ctx, client, cmd)
+}
+
+// showMeetingsHistory prints a summary of the
+// meetings history.
+func (c *Cli) showMeetingsHistory(ctx *cli.Context) error {
+	query := url.Values{}
+	query.Set("summary", "true")
+	if ctx.Bool("hourly") {
+		query.Set("resolution", store.HistoryResolutionHourly)
+	}
+	if fe := ctx.String("frontend"); fe != "" {
+		query.Set("frontend_key", fe)
+	}
+	if backend := ctx.String("backend"); backend != "" {
+		query.Set("backend_host", backend)
+	}
+	if from := ctx.String("from"); from != "" {
+		query.Set("from", from)
+	}
+	if to := ctx.String("to"); to != "" {
+		query.Set("to", to)
+	}
+
+	client, err := apiClient(ctx)
+	if err != nil {
+		return err
+	}
+	history, err := client.MeetingsHistory(ctx.Context, query)
+	if err != nil {
+		return err
+	}
+
+	if ctx.Bool("json") {
+		buf, _ := json.MarshalIndent(history, "", "   ")
+		fmt.Println(string(buf))
+		return nil
+	}
+
+	layout := "2006-01-02"
+	if history.Resolution == store.HistoryResolutionHourly {
+		layout = "2006-01-02 15:04"
+	}
+
+	// The aggregates are calculated in UTC
+	fmt.Printf("Meetings from %s to %s\n\n",
+		history.From.UTC().Format(time.RFC3339),
+		history.To.UTC().Format(time.RFC3339))
+	fmt.Println("Time\t\t\tMeetings\tPeak\tAvg. Duration")
+
+	total := 0
+	peak := 0
+	duration := 0.0
+	for _, b := range history.Aggregates {
+		fmt.Printf("%-16s\t%d\t\t%d\t%.0fm\n",
+			b.Time.UTC().Format(layout),
+			b.Meetings,
+			b.PeakConcurrency,
+			b.AverageDuration)
+		total += b.Meetings
+		duration += b.AverageDuration * float64(b.Meetings)
+		if b.PeakConcurrency > peak {
+			peak = b.PeakConcurrency
+		}
+	}
+	avg := 0.0
+	if total > 0 {
+		avg = duration / float64(total)
+	}
+	fmt.Printf("\nTotal:\t\t\t%d\t\t%d\t%.0fm\n", total, peak, avg)
+
+	return nil
+}
@@ -0,0 +1,122 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/urfave/cli/v2"
+
+	"github.com/b3scale/b3scale/pkg/store"
+)
+
+// showWebhooks lists all webhooks
+func (c *Cli) showWebhooks(ctx *cli.Context) error {
+	client, err := apiClient(ctx)
+	if err != nil {
+		return err
+	}
+	webhooks, err := client.WebhooksList(ctx.Context)
+	if err != nil {
+		return err
+	}
+
+	if ctx.Bool("json") {
+		buf, _ := json.MarshalIndent(webhooks, "", "   ")
+		fmt.Println(string(buf))
+		return nil
+	}
+
+	for _, w := range webhooks {
+		events := "all"
+		if len(w.Events) > 0 {
+			events = strings.Join(w.Events, ", ")
+		}
+		fmt.Printf("%s\n  URL:\t\t %s\n", w.ID, w.URL)
+		fmt.Printf("  Active:\t %v\n", w.Active)
+		fmt.Printf("  Events:\t %s\n", events)
+		if w.FrontendID != nil {
+			fmt.Printf("  Frontend:\t %s\n", *w.FrontendID)
+		}
+		if w.Comment != "" {
+			fmt.Printf("  Comment:\t %s\n", w.Comment)
+		}
+		fmt.Println("")
+	}
+	return nil
+}
+
+// setWebhook subscribes a webhook to cluster events
+func (c *Cli) setWebhook(ctx *cli.Context) error {
+	dry := ctx.Bool("dry")
+	// Args should be the url
+	if ctx.NArg() < 1 {
+		return fmt.Errorf("require: <url>")
+	}
+
+	client, err := apiClient(ctx)
+	if err != nil {
+		return err
+	}
+
+	webhook := &store.Webhook{
+		URL:     ctx.Args().Get(0),
+		Secret:  ctx.String("secret"),
+		Events:  ctx.StringSlice("event"),
+		Active:  true,
+		Comment: ctx.String("comment"),
+	}
+	if key := ctx.String("frontend"); key != "" {
+		frontend, err := getFrontendByKey(ctx.Context, client, key)
+		if err != nil {
+			return err
+		}
+		if frontend == nil {
+			return fmt.Errorf("frontend not found")
+		}
+		webhook.FrontendID = &frontend.ID
+	}
+	for _, t := range webhook.Events {
+		if !store.IsEventType(t) {
+			return fmt.Errorf("unknown event type: %s (known: %s)",
+				t, strings.Join(store.EventTypes, ", "))
+		}
+	}
+
+	if dry {
+		fmt.Println("skipping webhook", webhook.URL, "(dry run)")
+		return nil
+	}
+	webhook, err = client.WebhookCreate(ctx.Context, webhook)
+	if err != nil {
+		return err
+	}
+	fmt.Println("created webhook:", webhook.ID)
+	fmt.Println("secret:", webhook.Secret)
+	return nil
+}
+
+// deleteWebhook removes a webhook
+func (c *Cli) deleteWebhook(ctx *cli.Context) error {
+	dry := ctx.Bool("dry")
+	// Args should be the webhook id
+	if ctx.NArg() < 1 {
+		return fmt.Errorf("require: <id>")
+	}
+	id := ctx.Args().Get(0)
+
+	client, err := apiClient(ctx)
+	if err != nil {
+		return err
+	}
+	if dry {
+		fmt.Println("skipping delete webhook (dry run)")
+		return nil
+	}
+	webhook, err := client.WebhookDelete(ctx.Context, id)
+	if err != nil {
+		return err
+	}
+	fmt.Println("deleted webhook:", webhook.ID)
+	return nil
+}
@@ -69,6 +69,26 @@ func main() {
 		}
 	}
 
+	// Configure the stress model used for load balancing
+	stressWeights, err := cluster.StressWeightsFromEnv()
+	if err != nil {
+		log.Fatal().Err(err).Msg("stress model configuration")
+	}
+	cluster.SetStressModel(cluster.NewWeightedStress(stressWeights))
+
+	// Configure the retention of stale data
+	gcPolicy, err := cluster.GCPolicyFromEnv()
+	if err != nil {
+		log.Fatal().Err(err).Msg("garbage collection policy")
+	}
+	cluster.SetGCPolicy(gcPolicy)
+
+	// Failing commands are retried depending on their action
+	store.SetCommandRetryPolicies(cluster.CommandRetryPolicies)
+
+	// Processed commands are published to the events stream
+	store.SetCommandPublisher(cluster.PublishCommandEvent)
+
 	// Initialize cluster
 	ctrl := cluster.NewController()
 
@@ -76,7 +96,11 @@ func main() {
 	// IMPORTANT: The middlewares are executed in reverse order.
 	router := cluster.NewRouter(ctrl)
 	router.Use(routing.SortLoad)
+	router.Use(routing.CapacityLimits)
+	router.Use(routing.Zone)
 	router.Use(routing.RequiredTags)
+	router.Use(routing.TagsAffinity)
+	router.Use(routing.Hints)
 
 	// Start cluster request handler, and apply middlewares.
 	// IMPORTANT: The middlewares are executed in reverse order.
@@ -103,7 +127,7 @@ func main() {
 	go ctrl.Start(ctx)
 
 	// Start HTTP interface
-	httpServer := http.NewServer("http", ctrl, gateway)
+	httpServer := http.NewServer("http", ctrl, gateway, router)
 	go httpServer.Start(listenHTTP)
 
 	<-ctx.Done()
@@ -19,6 +19,7 @@ const (
 	ResourcePutRecordingTextTrack  = "putRecordingTextTrack"
 	ResourceDeleteRecordings       = "deleteRecordings"
 	ResourcePublishRecordings      = "publishRecordings"
+	ResourceSendChatMessage        = "sendChatMessage"
 )
 
 // API is the bbb api interface
@@ -37,4 +38,5 @@ type API interface {
 	SetConfigXML(*Request) (*SetConfigXMLResponse, error)
 	GetRecordingTextTracks(*Request) (*GetRecordingTextTracksResponse, error)
 	PutRecordingTextTrack(*Request) (*PutRecordingTextTrackResponse, error)
+	SendChatMessage(*Request) (*SendChatMessageResponse, error)
 }
@@ -77,6 +77,8 @@ func unmarshalRequestResponse(req *Request, data []byte) (Response, error) {
 		return UnmarshalGetRecordingTextTracksResponse(data)
 	case ResourcePutRecordingTextTrack:
 		return UnmarshalPutRecordingTextTrackResponse(data)
+	case ResourceSendChatMessage:
+		return UnmarshalSendChatMessageResponse(data)
 	}
 
 	return nil, fmt.Errorf(
@@ -34,6 +34,22 @@ type UserLeftMeetingEvent struct {
 	InternalID        string
 }
 
+// UserBroadcastCamStartedEvent indicates that a user
+// started sharing a webcam stream
+type UserBroadcastCamStartedEvent struct {
+	InternalMeetingID string
+	InternalUserID    string
+	Stream            string
+}
+
+// UserBroadcastCamStoppedEvent indicates that a user
+// stopped sharing a webcam stream
+type UserBroadcastCamStoppedEvent struct {
+	InternalMeetingID string
+	InternalUserID    string
+	Stream            string
+}
+
 // BreakoutRoomStartedEvent indicates the start of a breakout room
 type BreakoutRoomStartedEvent struct {
 	ParentInternalMeetingID string
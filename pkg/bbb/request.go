@@ -325,6 +325,18 @@ func EndRequest(params Params) *Request {
 	}
 }
 
+// SendChatMessageRequest creates a request for posting
+// a message to the public chat of a meeting
+func SendChatMessageRequest(params Params) *Request {
+	return &Request{
+		Request: &http.Request{
+			Method: http.MethodGet,
+		},
+		Resource: ResourceSendChatMessage,
+		Params:   params,
+	}
+}
+
 // CreateRequest creates a new create request
 func CreateRequest(params Params, body []byte) *Request {
 	return &Request{
@@ -342,6 +342,51 @@ func (res *EndResponse) SetStatus(s int) {
 	res.XMLResponse.SetStatus(s)
 }
 
+// SendChatMessageResponse is the response of the
+// sendChatMessage resource
+type SendChatMessageResponse struct {
+	*XMLResponse
+}
+
+// UnmarshalSendChatMessageResponse decodes the xml response
+func UnmarshalSendChatMessageResponse(
+	data []byte,
+) (*SendChatMessageResponse, error) {
+	res := &SendChatMessageResponse{}
+	err := xml.Unmarshal(data, res)
+	return res, err
+}
+
+// Marshal SendChatMessageResponse to XML
+func (res *SendChatMessageResponse) Marshal() ([]byte, error) {
+	return xml.Marshal(res)
+}
+
+// Merge SendChatMessageResponses
+func (res *SendChatMessageResponse) Merge(other Response) error {
+	return ErrCantBeMerged
+}
+
+// Header returns the HTTP response headers
+func (res *SendChatMessageResponse) Header() http.Header {
+	return res.XMLResponse.Header()
+}
+
+// SetHeader sets the HTTP response headers
+func (res *SendChatMessageResponse) SetHeader(h http.Header) {
+	res.XMLResponse.SetHeader(h)
+}
+
+// Status returns the HTTP response status code
+func (res *SendChatMessageResponse) Status() int {
+	return res.XMLResponse.Status()
+}
+
+// SetStatus sets the HTTP response status code
+func (res *SendChatMessageResponse) SetStatus(s int) {
+	res.XMLResponse.SetStatus(s)
+}
+
 // GetMeetingInfoResponse contains detailed meeting information
 type GetMeetingInfoResponse struct {
 	*XMLResponse
@@ -174,6 +174,29 @@ func TestMergeEndResponse(t *testing.T) {
 	}
 }
 
+// SendChatMessageResponse
+
+func TestUnmarshalSendChatMessageResponse(t *testing.T) {
+	data := readTestResponse("sendChatMessageSuccess.xml")
+	response, err := UnmarshalSendChatMessageResponse(data)
+	if err != nil {
+		t.Error(err)
+	}
+
+	if !response.IsSuccess() {
+		t.Error("Unexpected Returncode:", response.Returncode)
+	}
+}
+
+func TestMergeSendChatMessageResponse(t *testing.T) {
+	a := &SendChatMessageResponse{}
+	b := &SendChatMessageResponse{}
+
+	if !errors.Is(a.Merge(b), ErrCantBeMerged) {
+		t.Error("SendChatMessageResponse should not be merged")
+	}
+}
+
 // GetMeetingInfoResponse
 
 func TestUnmarshalGetMeetingInfoRespons(t *testing.T) {
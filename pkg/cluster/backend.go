@@ -4,7 +4,6 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"math"
 	"net/http"
 	"net/url"
 	"time"
@@ -24,7 +23,9 @@ const (
 	BackendStateReady          = "ready"
 	BackendStateError          = "error"
 	BackendStateStopped        = "stopped"
+	BackendStateDraining       = "draining"
 	BackendStateDecommissioned = "decommissioned"
+	BackendStateDestroyed      = "destroyed"
 )
 
 var (
@@ -73,6 +74,21 @@ func (b *Backend) Tags() []string {
 	return b.state.Settings.Tags
 }
 
+// Settings retrieves the backend's settings from it's state
+func (b *Backend) Settings() *store.BackendSettings {
+	return &b.state.Settings
+}
+
+// MeetingsCount retrieves the number of meetings on the backend
+func (b *Backend) MeetingsCount() uint {
+	return b.state.MeetingsCount
+}
+
+// AttendeesCount retrieves the number of attendees on the backend
+func (b *Backend) AttendeesCount() uint {
+	return b.state.AttendeesCount
+}
+
 // HasTag checks for the presence of a tag
 func (b *Backend) HasTag(tag string) bool {
 	if tag == "" {
@@ -142,18 +158,23 @@ func GetBackend(
 	return backends[0], nil
 }
 
-// Stress calculates the current node load
+// Stress calculates the current node load using
+// the configured stress model.
 func (b *Backend) Stress() float64 {
-	f := b.state.LoadFactor
-
-	// Assume a base load of n attendees per meeting. This should come from
-	// some a config or should be set per frontend or even be hinted from the create() call.
-	attendeeBaseLoad := 15.0
+	return GetStressModel().Stress(b.state)
+}
 
-	// Assume that every Meeting will eventually have attendeeBaseLoad attendees on average,
-	// but use AttendeesCount if bigger. A rough estimate, but cheap to calculate.
-	minAssumedAttendees := math.Max(float64(b.state.MeetingsCount)*attendeeBaseLoad, float64(b.state.AttendeesCount))
-	return f * minAssumedAttendees
+// ProjectedStress estimates the node load after creating
+// a new meeting with the expected attendees from the
+// routing hints. Without hints this is the current stress.
+func (b *Backend) ProjectedStress(hints *RoutingHints) float64 {
+	if hints == nil || hints.ExpectedAttendees == 0 {
+		return b.Stress()
+	}
+	state := *b.state
+	state.MeetingsCount++
+	state.AttendeesCount += hints.ExpectedAttendees
+	return GetStressModel().Stress(&state)
 }
 
 // refreshNodeState will fetch all meetings from the backend.
@@ -185,6 +206,13 @@ func (b *Backend) refreshNodeState(
 	}
 	defer tx.Rollback(ctx) //nolint
 
+	// Changes of the node state are emitted as event
+	prevNodeState := b.state.NodeState
+	emitStateChanged := func() error {
+		return EmitBackendStateChanged(
+			ctx, tx, b.state, prevNodeState, b.state.AdminState)
+	}
+
 	// Measure latency
 	t0 := time.Now()
 	req := bbb.GetMeetingsRequest(bbb.Params{}).WithBackend(b.state.Backend)
@@ -199,6 +227,9 @@ func (b *Backend) refreshNodeState(
 		if err := b.state.Save(ctx, tx); err != nil {
 			return err
 		}
+		if err := emitStateChanged(); err != nil {
+			return err
+		}
 		return tx.Commit(ctx)
 	}
 	t1 := time.Now()
@@ -214,6 +245,9 @@ func (b *Backend) refreshNodeState(
 		if err := b.state.Save(ctx, tx); err != nil {
 			return err
 		}
+		if err := emitStateChanged(); err != nil {
+			return err
+		}
 
 		return tx.Commit(ctx)
 	}
@@ -229,6 +263,9 @@ func (b *Backend) refreshNodeState(
 	if err := b.state.Save(ctx, tx); err != nil {
 		return err
 	}
+	if err := emitStateChanged(); err != nil {
+		return err
+	}
 
 	if err := tx.Commit(ctx); err != nil {
 		log.Error().
@@ -402,7 +439,31 @@ func (b *Backend) Create(
 		return nil, err
 	}
 	if meetingState == nil {
-		_, err = b.state.CreateMeetingState(ctx, tx, req.Frontend, createRes.Meeting)
+		meetingState, err = b.state.CreateMeetingState(
+			ctx, tx, req.Frontend, createRes.Meeting)
+		if err != nil {
+			return nil, err
+		}
+	} else if meetingState.BackendID == nil {
+		// The meeting was detached from a failed backend
+		// and is now recreated on this backend.
+		log.Info().
+			Str("meetingID", meetingState.ID).
+			Str("backendID", b.ID()).
+			Msg("reattaching meeting to backend")
+
+		meetingState.BackendID = &b.state.ID
+		meetingState.Meeting = createRes.Meeting
+		meetingState.MarkSynced()
+		if err := meetingState.Save(ctx, tx); err != nil {
+			return nil, err
+		}
+	}
+
+	// Keep the routing decision with the meeting
+	if trace := RoutingTraceFromContext(ctx); trace != nil {
+		err := store.SetMeetingRouting(
+			ctx, tx, meetingState.ID, trace.Record())
 		if err != nil {
 			return nil, err
 		}
@@ -506,6 +567,38 @@ func (b *Backend) End(
 	return res.(*bbb.EndResponse), err
 }
 
+// EndMeeting sends an end request for a meeting
+func (b *Backend) EndMeeting(
+	ctx context.Context,
+	m *store.MeetingState,
+) error {
+	req := bbb.EndRequest(bbb.Params{
+		"meetingID": m.Meeting.MeetingID,
+		"password":  m.Meeting.ModeratorPW,
+	})
+	res, err := b.End(ctx, req)
+	if err != nil {
+		return err
+	}
+	if res.Returncode != bbb.RetSuccess {
+		return fmt.Errorf("%s: %s", res.MessageKey, res.Message)
+	}
+	return nil
+}
+
+// SendChatMessage posts a message to the public
+// chat of a meeting
+func (b *Backend) SendChatMessage(
+	ctx context.Context,
+	req *bbb.Request,
+) (*bbb.SendChatMessageResponse, error) {
+	res, err := b.client.Do(ctx, req.WithBackend(b.state.Backend))
+	if err != nil {
+		return nil, err
+	}
+	return res.(*bbb.SendChatMessageResponse), err
+}
+
 // GetMeetingInfo gets the meeting details
 func (b *Backend) GetMeetingInfo(
 	ctx context.Context,
@@ -94,3 +94,23 @@ func TestBackendHasTags(t *testing.T) {
 		t.Error("should not have tags foo")
 	}
 }
+
+func TestBackendProjectedStress(t *testing.T) {
+	b := &Backend{state: &store.BackendState{
+		ID:             "A",
+		MeetingsCount:  10,
+		LoadFactor:     1,
+		AttendeesCount: 20,
+	}}
+	if s := b.ProjectedStress(nil); s != 150 {
+		t.Error("unexpected result for projected stress:", s)
+	}
+	hints := &RoutingHints{ExpectedAttendees: 300}
+	if s := b.ProjectedStress(hints); s != 320 {
+		t.Error("unexpected result for projected stress:", s)
+	}
+	// The state must not be modified
+	if b.state.AttendeesCount != 20 || b.state.MeetingsCount != 10 {
+		t.Error("state was modified")
+	}
+}
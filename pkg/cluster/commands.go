@@ -4,8 +4,10 @@ package cluster
 
 import (
 	"errors"
+	"fmt"
 	"time"
 
+	"github.com/b3scale/b3scale/pkg/http/callbacks"
 	"github.com/b3scale/b3scale/pkg/store"
 )
 
@@ -14,15 +16,58 @@ const (
 	// Backend
 	CmdUpdateNodeState     = "update_node_state"
 	CmdDecommissionBackend = "decommission_backend"
+	CmdDrainBackend        = "drain_backend"
 
 	// Meetings
 	CmdUpdateMeetingState = "update_meeting_state"
 	CmdEndAllMeetings     = "end_all_meetings"
+	CmdReconcileMeetings  = "reconcile_meetings"
 
 	// Maintenance
 	CmdCollectGarbage = "collect_garbage"
+
+	// Events
+	CmdDeliverWebhook = "deliver_webhook"
 )
 
+// CommandRetryPolicies define how often failing commands
+// are attempted. Commands not listed here run only once.
+// Periodic commands like update_node_state are not retried,
+// because they are requested again anyway.
+var CommandRetryPolicies = map[string]store.RetryPolicy{
+	CmdEndAllMeetings: {
+		MaxAttempts: 3,
+		Backoff:     10 * time.Second,
+		MaxBackoff:  1 * time.Minute,
+	},
+	CmdDecommissionBackend: {
+		MaxAttempts: 3,
+		Backoff:     30 * time.Second,
+		MaxBackoff:  5 * time.Minute,
+	},
+	CmdUpdateMeetingState: {
+		MaxAttempts: 2,
+		Backoff:     5 * time.Second,
+		MaxBackoff:  30 * time.Second,
+	},
+	CmdDeliverWebhook: {
+		MaxAttempts: callbacks.WebhookRetryCount,
+		Backoff:     callbacks.WebhookRetryWaitMin,
+		MaxBackoff:  callbacks.WebhookRetryWaitMax,
+	},
+}
+
+// commandKey creates an idempotency key for a command.
+// Queuing a command is a no-op while a command with the
+// same key is pending.
+func commandKey(action string, id ...string) *string {
+	key := action
+	if len(id) > 0 {
+		key = fmt.Sprintf("%s:%s", action, id[0])
+	}
+	return &key
+}
+
 var (
 	// ErrUnknownCommand indicates, that the command was not
 	// understood by the controller.
@@ -45,6 +90,22 @@ func DecommissionBackend(req *DecommissionBackendRequest) *store.Command {
 	}
 }
 
+// DrainBackendRequest declares that empty meetings
+// on a draining backend should be ended.
+type DrainBackendRequest struct {
+	ID string `json:"id"`
+}
+
+// DrainBackend will end all empty meetings on
+// a backend in the draining state.
+func DrainBackend(req *DrainBackendRequest) *store.Command {
+	return &store.Command{
+		Action:   CmdDrainBackend,
+		Params:   req,
+		Deadline: store.NextDeadline(5 * time.Minute),
+	}
+}
+
 // UpdateNodeStateRequest requests a status update
 // from a backend identified by ID
 type UpdateNodeStateRequest struct {
@@ -54,9 +115,10 @@ type UpdateNodeStateRequest struct {
 // UpdateNodeState creates a update status command
 func UpdateNodeState(req *UpdateNodeStateRequest) *store.Command {
 	return &store.Command{
-		Action:   CmdUpdateNodeState,
-		Params:   req,
-		Deadline: store.NextDeadline(10 * time.Minute),
+		Action:         CmdUpdateNodeState,
+		Params:         req,
+		IdempotencyKey: commandKey(CmdUpdateNodeState, req.ID),
+		Deadline:       store.NextDeadline(10 * time.Minute),
 	}
 }
 
@@ -70,9 +132,10 @@ func UpdateMeetingState(
 	req *UpdateMeetingStateRequest,
 ) *store.Command {
 	return &store.Command{
-		Action:   CmdUpdateMeetingState,
-		Params:   req,
-		Deadline: store.NextDeadline(10 * time.Minute),
+		Action:         CmdUpdateMeetingState,
+		Params:         req,
+		IdempotencyKey: commandKey(CmdUpdateMeetingState, req.ID),
+		Deadline:       store.NextDeadline(10 * time.Minute),
 	}
 }
 
@@ -92,10 +155,74 @@ func EndAllMeetings(req *EndAllMeetingsRequest) *store.Command {
 	}
 }
 
-// CollectGarbage requests removing stale states.
-func CollectGarbage() *store.Command {
+// ReconcileMeetingsRequest contains the parameters for
+// comparing the meetings of the store with the backends.
+type ReconcileMeetingsRequest struct {
+	// BackendID limits the reconciliation to a single
+	// backend. All backends are checked if empty.
+	BackendID string `json:"backend_id,omitempty"`
+
+	// Unknown selects what to do with meetings unknown
+	// to b3scale: "adopt" or "end" them. They are only
+	// reported if empty.
+	Unknown string `json:"unknown,omitempty"`
+}
+
+// Validate checks the resolution for unknown meetings
+func (req *ReconcileMeetingsRequest) Validate() error {
+	switch req.Unknown {
+	case "", ReconcileAdopt, ReconcileEnd:
+		return nil
+	}
+	return fmt.Errorf("unknown meetings can not be resolved by: %s", req.Unknown)
+}
+
+// ReconcileMeetings creates a command comparing the
+// meetings in the store with the meetings on the backends.
+func ReconcileMeetings(req *ReconcileMeetingsRequest) *store.Command {
+	return &store.Command{
+		Action:   CmdReconcileMeetings,
+		Params:   req,
+		Deadline: store.NextDeadline(5 * time.Minute),
+	}
+}
+
+// CollectGarbageRequest contains parameters for
+// the collect garbage command.
+type CollectGarbageRequest struct {
+	// DryRun only counts the garbage for all rules
+	DryRun bool `json:"dry_run"`
+}
+
+// CollectGarbage requests removing stale states
+// as configured in the GCPolicy. Dry runs are not
+// coalesced with collecting the garbage.
+func CollectGarbage(req *CollectGarbageRequest) *store.Command {
+	key := commandKey(CmdCollectGarbage)
+	if req.DryRun {
+		key = commandKey(CmdCollectGarbage, "dry_run")
+	}
+	return &store.Command{
+		Action:         CmdCollectGarbage,
+		Params:         req,
+		IdempotencyKey: key,
+		Deadline:       store.NextDeadline(5 * time.Minute),
+	}
+}
+
+// DeliverWebhookRequest contains the event
+// to post to a webhook.
+type DeliverWebhookRequest struct {
+	WebhookID string `json:"webhook_id"`
+	Event     *Event `json:"event"`
+}
+
+// DeliverWebhook creates a command for posting
+// an event to a webhook. Failed deliveries are retried.
+func DeliverWebhook(req *DeliverWebhookRequest) *store.Command {
 	return &store.Command{
-		Action:   CmdCollectGarbage,
+		Action:   CmdDeliverWebhook,
+		Params:   req,
 		Deadline: store.NextDeadline(5 * time.Minute),
 	}
 }
@@ -0,0 +1,60 @@
+package cluster
+
+import (
+	"testing"
+)
+
+func TestCommandIdempotencyKeys(t *testing.T) {
+	c1 := UpdateNodeState(&UpdateNodeStateRequest{ID: "backend1"})
+	c2 := UpdateNodeState(&UpdateNodeStateRequest{ID: "backend1"})
+	c3 := UpdateNodeState(&UpdateNodeStateRequest{ID: "backend2"})
+	if *c1.IdempotencyKey != *c2.IdempotencyKey {
+		t.Error("expected same key for same backend")
+	}
+	if *c1.IdempotencyKey == *c3.IdempotencyKey {
+		t.Error("expected different keys for different backends")
+	}
+
+	m := UpdateMeetingState(&UpdateMeetingStateRequest{ID: "backend1"})
+	if *m.IdempotencyKey == *c1.IdempotencyKey {
+		t.Error("keys must include the action")
+	}
+	if *CollectGarbage(&CollectGarbageRequest{}).IdempotencyKey != CmdCollectGarbage {
+		t.Error("unexpected key:", *CollectGarbage(&CollectGarbageRequest{}).IdempotencyKey)
+	}
+	dryRun := CollectGarbage(&CollectGarbageRequest{DryRun: true})
+	if *dryRun.IdempotencyKey == CmdCollectGarbage {
+		t.Error("dry runs should not be coalesced with collecting garbage")
+	}
+	if EndAllMeetings(&EndAllMeetingsRequest{}).IdempotencyKey != nil {
+		t.Error("end_all_meetings should not be coalesced")
+	}
+}
+
+func TestReconcileMeetingsRequestValidate(t *testing.T) {
+	for _, unknown := range []string{"", ReconcileAdopt, ReconcileEnd} {
+		req := &ReconcileMeetingsRequest{Unknown: unknown}
+		if err := req.Validate(); err != nil {
+			t.Error(unknown, err)
+		}
+	}
+	req := &ReconcileMeetingsRequest{Unknown: "delete"}
+	if err := req.Validate(); err == nil {
+		t.Error("expected an error")
+	}
+}
+
+func TestBackendReconciliationInSync(t *testing.T) {
+	r := &BackendReconciliation{
+		Unknown:          []*MeetingDiff{},
+		Missing:          []*MeetingDiff{},
+		FrontendMismatch: []*MeetingDiff{},
+	}
+	if !r.InSync() {
+		t.Error("expected backend to be in sync")
+	}
+	r.Missing = append(r.Missing, &MeetingDiff{MeetingID: "m1"})
+	if r.InSync() {
+		t.Error("expected backend not to be in sync")
+	}
+}
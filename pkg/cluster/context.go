@@ -11,6 +11,7 @@ var (
 	backendsContextKey = requestContextKey(1)
 	backendContextKey  = requestContextKey(2)
 	frontendContextKey = requestContextKey(3)
+	hintsContextKey    = requestContextKey(4)
 )
 
 // NewRequestContext create a new context
@@ -68,3 +69,20 @@ func FrontendFromContext(ctx context.Context) *Frontend {
 	}
 	return frontend
 }
+
+// ContextWithRoutingHints creates a context with routing hints
+func ContextWithRoutingHints(
+	ctx context.Context, hints *RoutingHints,
+) context.Context {
+	return context.WithValue(ctx, hintsContextKey, hints)
+}
+
+// RoutingHintsFromContext retrieves the routing hints
+// from a context. If no hints are present, nil is returned.
+func RoutingHintsFromContext(ctx context.Context) *RoutingHints {
+	hints, ok := ctx.Value(hintsContextKey).(*RoutingHints)
+	if !ok {
+		return nil
+	}
+	return hints
+}
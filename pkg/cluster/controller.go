@@ -2,14 +2,20 @@ package cluster
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"math/rand"
+	"os"
 	"sync"
 	"time"
 
+	"github.com/jackc/pgx/v4"
 	"github.com/rs/zerolog/log"
 
 	"github.com/b3scale/b3scale/pkg/bbb"
+	"github.com/b3scale/b3scale/pkg/config"
+	"github.com/b3scale/b3scale/pkg/http/callbacks"
 	"github.com/b3scale/b3scale/pkg/store"
 )
 
@@ -21,6 +27,11 @@ const (
 	// NodeSyncInterval is the amount of time after a backend
 	// node is considered stale and should be refreshed.
 	NodeSyncInterval = 20 * time.Second
+
+	// DrainGracePeriod is the amount of time a meeting on
+	// a draining backend may stay empty after it was created,
+	// before it is ended. This gives the attendees time to join.
+	DrainGracePeriod = 2 * time.Minute
 )
 
 // The Controller interfaces with the state of the cluster
@@ -31,6 +42,17 @@ const (
 type Controller struct {
 	cmds *store.CommandQueue
 
+	// failoverTimeout is the time a backend agent may be
+	// offline, before the meetings are detached.
+	failoverTimeout time.Duration
+
+	// meetingSyncLimit is the maximum number of stale
+	// meetings refreshed per background run.
+	meetingSyncLimit int
+
+	// Only the leader runs the periodic background tasks.
+	leader *store.LeaderLock
+
 	lastStartBackground time.Time
 	mtx                 sync.Mutex
 }
@@ -40,8 +62,21 @@ type Controller struct {
 // which will be used by the backend instances.
 func NewController() *Controller {
 	return &Controller{
-		cmds: store.NewCommandQueue(),
+		cmds:             store.NewCommandQueue(),
+		failoverTimeout:  config.GetBackendFailoverTimeout(),
+		meetingSyncLimit: config.GetMeetingSyncLimit(),
+		leader:           store.NewLeaderLock(instanceID()),
+	}
+}
+
+// instanceID identifies this b3scale instance
+// in the cluster.
+func instanceID() string {
+	host, err := os.Hostname()
+	if err != nil {
+		host = "unknown"
 	}
+	return fmt.Sprintf("%s/%d", host, os.Getpid())
 }
 
 // Start the controller
@@ -64,12 +99,18 @@ func (c *Controller) Start(ctx context.Context) {
 
 	// Controller Main: Handle queued commands.
 	c.cmds.StartReceive(ctx, c.handleCommand)
+
+	// Let another instance take over
+	c.leader.Release(context.Background())
 }
 
 // StartBackground will be run periodically triggered by
 // requests and should only add tasks to the command queue.
 // These tasks will take care of syncing the backends with
 // our state by refreshing nodes and meetings.
+//
+// The tasks are only run by the cluster leader. All other
+// instances only process the queued commands.
 func (c *Controller) StartBackground() {
 	c.mtx.Lock()
 	defer c.mtx.Unlock()
@@ -91,6 +132,16 @@ func (c *Controller) StartBackground() {
 	defer conn.Release()
 	ctx = store.ContextWithConnection(ctx, conn)
 
+	isLeader, err := c.leader.Acquire(ctx)
+	if err != nil {
+		log.Error().Err(err).Msg("leader election")
+		return
+	}
+	if !isLeader {
+		log.Debug().Msg("not the cluster leader, skipping background tasks")
+		return
+	}
+
 	// Dispatch loading of the backend state if the
 	// last sync was verly long.
 	if err := c.requestSyncStaleNodes(ctx); err != nil {
@@ -98,16 +149,27 @@ func (c *Controller) StartBackground() {
 	}
 
 	// Dispatch refreshing stale meetings if the last
-	// sync was a while ago.
-	/*
-		CAVEAT:
-		This puts a high pressure on the system and might
-		not be necessary. For now this is disabled.
+	// sync was a while ago. This is limited to a couple of
+	// meetings per run, to not put too much pressure on
+	// the backends.
+	if err := c.requestSyncStaleMeetings(ctx); err != nil {
+		log.Error().Err(err).Msg("requestSyncStaleMeetings")
+	}
 
-		if err := c.requestSyncStaleMeetings(ctx); err != nil {
-			log.Error().Err(err).Msg("requestSyncStaleMeetings")
-		}
-	*/
+	// Start and end scheduled maintenance of backends
+	if err := c.evaluateMaintenanceWindows(ctx); err != nil {
+		log.Error().Err(err).Msg("evaluateMaintenanceWindows")
+	}
+
+	// Queue commands of due schedules
+	if err := c.requestScheduledCommands(ctx); err != nil {
+		log.Error().Err(err).Msg("requestScheduledCommands")
+	}
+
+	// Dispatch draining of backends
+	if err := c.requestBackendDrains(ctx); err != nil {
+		log.Error().Err(err).Msg("requestBackendDrains")
+	}
 
 	// Dispatch decommissioning of marked backends
 	if err := c.requestBackendDecommissions(ctx); err != nil {
@@ -124,6 +186,12 @@ func (c *Controller) StartBackground() {
 	if err := c.warnOfflineBackends(ctx); err != nil {
 		log.Error().Err(err).Msg("warnOfflineBackends")
 	}
+
+	// Release meetings from backends, where the agent
+	// is gone for too long.
+	if err := c.failoverDeadBackends(ctx); err != nil {
+		log.Error().Err(err).Msg("failoverDeadBackends")
+	}
 }
 
 // Command callback handler: Decode the operation and
@@ -139,6 +207,9 @@ func (c *Controller) handleCommand(
 	case CmdDecommissionBackend:
 		log.Debug().Str("cmd", CmdDecommissionBackend).Msg("EXEC")
 		return c.handleDecommissionBackend(ctx, cmd)
+	case CmdDrainBackend:
+		log.Debug().Str("cmd", CmdDrainBackend).Msg("EXEC")
+		return c.handleDrainBackend(ctx, cmd)
 	case CmdUpdateNodeState:
 		log.Debug().Str("cmd", CmdUpdateNodeState).Msg("EXEC")
 		return c.handleUpdateNodeState(ctx, cmd)
@@ -148,9 +219,15 @@ func (c *Controller) handleCommand(
 	case CmdEndAllMeetings:
 		log.Debug().Str("cmd", CmdEndAllMeetings).Msg("EXEC")
 		return c.handleEndAllMeetings(ctx, cmd)
+	case CmdReconcileMeetings:
+		log.Debug().Str("cmd", CmdReconcileMeetings).Msg("EXEC")
+		return c.handleReconcileMeetings(ctx, cmd)
 	case CmdCollectGarbage:
 		log.Debug().Str("cmd", CmdCollectGarbage).Msg("EXEC")
-		return c.handleCollectGarbage(ctx)
+		return c.handleCollectGarbage(ctx, cmd)
+	case CmdDeliverWebhook:
+		log.Debug().Str("cmd", CmdDeliverWebhook).Msg("EXEC")
+		return c.handleDeliverWebhook(ctx, cmd)
 	default:
 		return nil, ErrUnknownCommand
 	}
@@ -167,22 +244,28 @@ func (c *Controller) handleDecommissionBackend(
 		return nil, err
 	}
 
-	tx, err := store.ConnectionFromContext(ctx).Begin(ctx)
-	if err != nil {
-		return nil, err
-	}
-	defer tx.Rollback(ctx) //nolint
-
 	// Get backend for decommissioning
-	bstate, err := store.GetBackendState(ctx, tx, store.Q().
+	backend, err := GetBackend(ctx, store.Q().
 		Where("id = ?", req.ID))
 	if err != nil {
 		return nil, err
 	}
-	if bstate == nil {
+	if backend == nil {
 		return false, fmt.Errorf("no such backend: %s", req.ID)
 	}
 
+	// Decommissioning starts with draining the backend,
+	// ending all meetings as soon as they are empty.
+	if _, err := c.drainBackend(ctx, backend); err != nil {
+		return nil, err
+	}
+
+	tx, err := store.ConnectionFromContext(ctx).Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx) //nolint
+
 	// So. This is how this goes: We check if the backend
 	// has active meetings. If this is the case we abort.
 	// However, as the admin state indicates a non ready state
@@ -205,7 +288,9 @@ func (c *Controller) handleDecommissionBackend(
 
 	// Decommission backend by deleting the state
 	// and related meetings
-	if err := bstate.Delete(ctx, tx); err != nil {
+	if err := EmitBackendStateChange(ctx, tx, req.ID, func() error {
+		return backend.state.Delete(ctx, tx)
+	}); err != nil {
 		return false, err
 	}
 
@@ -216,6 +301,173 @@ func (c *Controller) handleDecommissionBackend(
 	return true, nil
 }
 
+// Command: DrainBackend
+// Ends all empty meetings on a draining backend
+func (c *Controller) handleDrainBackend(
+	ctx context.Context,
+	cmd *store.Command,
+) (interface{}, error) {
+	req := &DrainBackendRequest{}
+	if err := cmd.FetchParams(ctx, req); err != nil {
+		return nil, err
+	}
+
+	backend, err := GetBackend(ctx, store.Q().
+		Where("id = ?", req.ID))
+	if err != nil {
+		return nil, err
+	}
+	if backend == nil {
+		return false, fmt.Errorf("no such backend: %s", req.ID)
+	}
+	if backend.state.AdminState != BackendStateDraining {
+		return false, nil // The drain was canceled
+	}
+
+	remaining, err := c.drainBackend(ctx, backend)
+	if err != nil {
+		return nil, err
+	}
+
+	return remaining == 0, nil
+}
+
+// drainBackend ends all meetings on the backend, which
+// are empty. When the drain starts, the drain warning from the
+// backend settings is posted to the chat of all meetings.
+// The number of remaining meetings is returned.
+func (c *Controller) drainBackend(
+	ctx context.Context,
+	backend *Backend,
+) (int, error) {
+	tx, err := store.ConnectionFromContext(ctx).Begin(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback(ctx) //nolint
+
+	started, err := backend.state.StartDrain(ctx, tx)
+	if err != nil {
+		return 0, err
+	}
+	mstates, err := store.GetMeetingStates(ctx, tx, store.Q().
+		Where("meetings.backend_id = ?", backend.ID()))
+	if err != nil {
+		return 0, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return 0, err
+	}
+
+	if started {
+		log.Info().
+			Str("backendID", backend.ID()).
+			Int("meetings", len(mstates)).
+			Msg("backend drain started")
+
+		warning := backend.state.Settings.DrainWarning
+		if warning != "" {
+			sendDrainWarning(ctx, backend, mstates, warning)
+		}
+	}
+
+	now := time.Now().UTC()
+	ended := []*store.MeetingState{}
+	for _, m := range mstates {
+		if !isMeetingDrainable(m, now) {
+			continue
+		}
+		log.Info().
+			Str("backendID", backend.ID()).
+			Str("meetingID", m.Meeting.MeetingID).
+			Msg("end empty meeting on draining backend")
+
+		res, err := backend.End(ctx, bbb.EndRequest(bbb.Params{
+			"meetingID": m.Meeting.MeetingID,
+			"password":  m.Meeting.ModeratorPW,
+		}))
+		if err != nil {
+			log.Error().Err(err).
+				Str("meetingID", m.Meeting.MeetingID).
+				Msg("end meeting failed")
+			continue
+		}
+		if !res.IsSuccess() {
+			log.Error().
+				Str("meetingID", m.Meeting.MeetingID).
+				Str("msg", res.Message).
+				Str("msgKey", res.MessageKey).
+				Msg("end meeting failed")
+			continue
+		}
+		ended = append(ended, m)
+	}
+
+	if len(ended) == 0 {
+		return len(mstates), nil
+	}
+
+	// Remove the ended meetings and update the progress
+	tx, err = store.ConnectionFromContext(ctx).Begin(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback(ctx) //nolint
+
+	for _, m := range ended {
+		if err := store.DeleteMeetingStateByID(ctx, tx, m.ID); err != nil {
+			return 0, err
+		}
+	}
+	if err := backend.state.AddDrainMeetingsEnded(
+		ctx, tx, len(ended)); err != nil {
+		return 0, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return 0, err
+	}
+
+	return len(mstates) - len(ended), nil
+}
+
+// isMeetingDrainable checks if a meeting has no attendees
+// and the grace period for joining has passed.
+func isMeetingDrainable(m *store.MeetingState, now time.Time) bool {
+	if len(m.Meeting.Attendees) > 0 {
+		return false
+	}
+	return now.Sub(m.CreatedAt) > DrainGracePeriod
+}
+
+// sendDrainWarning posts the warning to the chat of
+// all meetings. Failures are logged and ignored.
+func sendDrainWarning(
+	ctx context.Context,
+	backend *Backend,
+	mstates []*store.MeetingState,
+	warning string,
+) {
+	for _, m := range mstates {
+		res, err := backend.SendChatMessage(ctx, bbb.SendChatMessageRequest(
+			bbb.Params{
+				"meetingID": m.Meeting.MeetingID,
+				"message":   warning,
+			}))
+		if err != nil {
+			log.Warn().Err(err).
+				Str("meetingID", m.Meeting.MeetingID).
+				Msg("could not send drain warning")
+			continue
+		}
+		if !res.IsSuccess() {
+			log.Warn().
+				Str("meetingID", m.Meeting.MeetingID).
+				Str("msgKey", res.MessageKey).
+				Msg("could not send drain warning")
+		}
+	}
+}
+
 // Command: UpdateNodeState
 func (c *Controller) handleUpdateNodeState(
 	ctx context.Context,
@@ -341,56 +593,229 @@ func (c *Controller) handleEndAllMeetings(
 	// Release early: we should not block the connection any longer
 	tx.Rollback(ctx) //nolint
 
+	// Failures are collected per meeting. The progress is
+	// reported after each meeting, which allows checking
+	// if the command was canceled.
+	progress := store.NewCommandProgress(len(mstates), "meetings ended")
+	if _, err := cmd.ReportProgress(ctx, progress); err != nil {
+		return nil, err
+	}
+
 	for _, m := range mstates {
 		log.Info().
 			Str("backendID", req.BackendID).
 			Str("meetingID", m.Meeting.MeetingID).
 			Msg("force end meeting")
 
-		req := bbb.EndRequest(bbb.Params{
-			"meetingID": m.Meeting.MeetingID,
-			"password":  m.Meeting.ModeratorPW,
-		})
-		res, err := backend.End(ctx, req)
+		if err := backend.EndMeeting(ctx, m); err != nil {
+			log.Error().
+				Err(err).
+				Str("meetingID", m.Meeting.MeetingID).
+				Msg("end meeting failed")
+			progress.Fail(m.Meeting.MeetingID, err)
+		} else {
+			progress.Succeed()
+		}
+
+		canceled, err := cmd.ReportProgress(ctx, progress)
 		if err != nil {
 			return nil, err
 		}
+		if canceled {
+			return progress, store.ErrCommandCanceled
+		}
+	}
 
-		if res.Returncode != bbb.RetSuccess {
-			log.Error().
-				Str("meetingID", m.Meeting.MeetingID).
-				Str("msg", res.Message).
-				Str("msgKey", res.MessageKey).
-				Msg("end meeting failed")
-			return nil, fmt.Errorf("end meeting failed: %s", res.MessageKey)
+	// Retry only if nothing worked
+	if progress.Failed > 0 && progress.Done == 0 {
+		return nil, fmt.Errorf("end meetings failed: %s", progress.Message)
+	}
+	return progress, nil
+}
+
+// handleReconcileMeetings compares the meetings in the
+// store with the meetings running on the backends. The
+// result is a report of the differences.
+func (c *Controller) handleReconcileMeetings(
+	ctx context.Context,
+	cmd *store.Command,
+) (interface{}, error) {
+	req := &ReconcileMeetingsRequest{}
+	if err := cmd.FetchParams(ctx, req); err != nil {
+		return nil, err
+	}
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	q := store.Q().Where("admin_state <> ?", "decommissioned")
+	if req.BackendID != "" {
+		q = q.Where("id = ?", req.BackendID)
+	}
+	backends, err := GetBackends(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+	if req.BackendID != "" && len(backends) == 0 {
+		return nil, fmt.Errorf("no such backend: %s", req.BackendID)
+	}
+
+	progress := store.NewCommandProgress(len(backends), "backends checked")
+	result := &MeetingsReconciliation{
+		Backends: make([]*BackendReconciliation, 0, len(backends)),
+	}
+	for _, backend := range backends {
+		report, err := backend.reconcileMeetings(ctx, req.Unknown)
+		if err != nil {
+			return nil, err
+		}
+		result.Backends = append(result.Backends, report)
+		if report.Error != nil {
+			progress.Fail(backend.Host(), errors.New(*report.Error))
+		} else {
+			progress.Succeed()
+		}
+
+		canceled, err := cmd.ReportProgress(ctx, progress)
+		if err != nil {
+			return nil, err
+		}
+		if canceled {
+			return result, store.ErrCommandCanceled
 		}
 	}
 
-	return true, nil
+	return result, nil
 }
 
 // handleCollectGarbage will do maintenance tasks
-// which include clearing stale data
+// which include clearing stale data. The rules of the
+// GCPolicy are applied and the result is a report
+// for each enabled rule.
 func (c *Controller) handleCollectGarbage(
 	ctx context.Context,
+	cmd *store.Command,
 ) (interface{}, error) {
+	var req *CollectGarbageRequest
+	if err := cmd.FetchParams(ctx, &req); err != nil {
+		return nil, err
+	}
+	forceDryRun := req != nil && req.DryRun
+
+	policy := GetGCPolicy()
+	now := time.Now().UTC()
+	reports := map[string]*GCReport{}
+
+	// Recordings are removed first, so frontends without
+	// recordings can be removed afterwards.
+	if rule := policy.InactiveFrontendRecordings; rule.Enabled() {
+		dryRun := rule.DryRun || forceDryRun
+		report, err := collectInactiveFrontendRecordings(
+			ctx, rule.Threshold(now), dryRun)
+		if err != nil {
+			return nil, err
+		}
+		reports[GCInactiveFrontendRecordings] = report
+	}
+
+	rules := []struct {
+		name    string
+		rule    *GCRule
+		collect func(context.Context, pgx.Tx, time.Time, bool) (int64, error)
+	}{
+		{GCFrontendMeetings, policy.FrontendMeetings, store.CollectStaleFrontendMeetings},
+		{GCFinishedCommands, policy.FinishedCommands, store.CollectFinishedCommands},
+		{GCOrphanMeetings, policy.OrphanMeetings, store.CollectOrphanMeetings},
+		{GCInactiveFrontends, policy.InactiveFrontends, store.CollectInactiveFrontends},
+		{GCMeetingsHistory, policy.MeetingsHistory, store.CollectMeetingsHistory},
+	}
+
 	tx, err := store.ConnectionFromContext(ctx).Begin(ctx)
 	if err != nil {
 		return nil, err
 	}
 	defer tx.Rollback(ctx) //nolint
 
-	// Clear stale frontend meeting mappings,
-	// older than a week.
-	timeAgo := 7 * 24 * time.Hour
-	th := time.Now().UTC().Add(-timeAgo)
+	for _, r := range rules {
+		if !r.rule.Enabled() {
+			continue
+		}
+		dryRun := r.rule.DryRun || forceDryRun
+		count, err := r.collect(ctx, tx, r.rule.Threshold(now), dryRun)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", r.name, err)
+		}
+		reports[r.name] = &GCReport{
+			Count:  count,
+			DryRun: dryRun,
+		}
+		if count > 0 {
+			log.Info().
+				Str("rule", r.name).
+				Int64("count", count).
+				Bool("dry_run", dryRun).
+				Msg("collected garbage")
+		}
+	}
 
-	if err := store.RemoveStaleFrontendMeetings(
-		ctx, tx, th); err != nil {
+	if err := tx.Commit(ctx); err != nil {
 		return nil, err
 	}
 
-	return true, nil
+	return reports, nil
+}
+
+// collectInactiveFrontendRecordings removes the recordings
+// including the files of frontends inactive since before t.
+// Recordings, where removing the files failed, are kept.
+func collectInactiveFrontendRecordings(
+	ctx context.Context,
+	t time.Time,
+	dryRun bool,
+) (*GCReport, error) {
+	tx, err := store.ConnectionFromContext(ctx).Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx) //nolint
+
+	recordings, err := store.GetRecordingStates(ctx, tx,
+		store.QueryRecordingsOfInactiveFrontends(t))
+	if err != nil {
+		return nil, err
+	}
+
+	report := &GCReport{DryRun: dryRun}
+	if dryRun {
+		report.Count = int64(len(recordings))
+		return report, nil
+	}
+
+	for _, rec := range recordings {
+		if err := rec.DeleteFiles(); err != nil {
+			log.Error().
+				Err(err).
+				Str("recordID", rec.RecordID).
+				Msg("could not remove recording files")
+			report.Failed++
+			continue
+		}
+		if err := rec.Delete(ctx, tx); err != nil {
+			return nil, err
+		}
+		report.Count++
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+	if report.Count > 0 {
+		log.Info().
+			Str("rule", GCInactiveFrontendRecordings).
+			Int64("count", report.Count).
+			Msg("collected garbage")
+	}
+	return report, nil
 }
 
 // Internal command generators
@@ -436,10 +861,16 @@ func (c *Controller) requestSyncStaleNodes(ctx context.Context) error {
 	return nil
 }
 
-/*
 // requestSyncStaleMeetings triggers a background sync
 // for meetings that have not been synced in a while.
+// Only the stalest meetings up to the sync limit are
+// refreshed, spread across the backends. This catches
+// state drift, e.g. when the agent missed an event.
 func (c *Controller) requestSyncStaleMeetings(ctx context.Context) error {
+	if c.meetingSyncLimit <= 0 {
+		return nil // disabled
+	}
+
 	tx, err := store.ConnectionFromContext(ctx).Begin(ctx)
 	if err != nil {
 		return err
@@ -447,24 +878,21 @@ func (c *Controller) requestSyncStaleMeetings(ctx context.Context) error {
 	defer tx.Rollback(ctx) //nolint
 
 	log.Debug().Msg("starting stale meeting refresh")
-	stale, err := store.GetMeetingStates(ctx, tx, store.Q().
-		Where(`now() - COALESCE(
-				synced_at,
-				TIMESTAMP '0001-01-01 00:00:00') > ?`,
-			MeetingSyncInterval))
+	stale, err := store.GetStaleMeetingIDs(
+		ctx, tx, MeetingSyncInterval, c.meetingSyncLimit)
 	if err != nil {
 		return err
 	}
 
 	// For each stale meeting create a refresh request.
-	for _, meeting := range stale {
+	for _, id := range stale {
 		log.Debug().
 			Str("cmd", "UpdateMeetingState").
-			Str("id", meeting.ID).
+			Str("id", id).
 			Msg("DISPATCH")
 		if err := store.QueueCommand(ctx, tx,
 			UpdateMeetingState(&UpdateMeetingStateRequest{
-				ID: meeting.ID,
+				ID: id,
 			})); err != nil {
 			return err
 		}
@@ -476,7 +904,6 @@ func (c *Controller) requestSyncStaleMeetings(ctx context.Context) error {
 
 	return nil
 }
-*/
 
 // requestBackendDecommissions will request a decommissioning
 // of a backend for all backends, which admin state is marked
@@ -527,6 +954,149 @@ func (c *Controller) requestBackendDecommissions(ctx context.Context) error {
 	return nil
 }
 
+// evaluateMaintenanceWindows sets the admin state of backends
+// with a maintenance window starting and sets the backend ready
+// again when the window ends.
+func (c *Controller) evaluateMaintenanceWindows(ctx context.Context) error {
+	tx, err := store.ConnectionFromContext(ctx).Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx) //nolint
+
+	now := time.Now().UTC()
+
+	// End active windows first, so a following window
+	// can take over the backend.
+	ending, err := store.GetMaintenanceWindows(ctx, tx, store.Q().
+		Where("maintenance_windows.state = ?", store.MaintenanceWindowActive).
+		Where("maintenance_windows.ends_at <= ?", now))
+	if err != nil {
+		return err
+	}
+	for _, w := range ending {
+		log.Info().
+			Str("backendID", w.BackendID).
+			Str("windowID", w.ID).
+			Msg("maintenance window ended")
+		if err := EmitBackendStateChange(ctx, tx, w.BackendID, func() error {
+			return w.End(ctx, tx, store.MaintenanceWindowFinished)
+		}); err != nil {
+			return err
+		}
+	}
+
+	starting, err := store.GetMaintenanceWindows(ctx, tx, store.Q().
+		Where("maintenance_windows.state = ?", store.MaintenanceWindowScheduled).
+		Where("maintenance_windows.starts_at <= ?", now))
+	if err != nil {
+		return err
+	}
+	for _, w := range starting {
+		// The window might have passed without the
+		// controller running.
+		if !w.EndsAt.After(now) {
+			log.Warn().
+				Str("backendID", w.BackendID).
+				Str("windowID", w.ID).
+				Msg("maintenance window missed")
+			if err := w.End(ctx, tx, store.MaintenanceWindowFinished); err != nil {
+				return err
+			}
+			continue
+		}
+
+		log.Info().
+			Str("backendID", w.BackendID).
+			Str("windowID", w.ID).
+			Str("adminState", w.AdminState).
+			Msg("maintenance window started")
+		if err := EmitBackendStateChange(ctx, tx, w.BackendID, func() error {
+			return w.Begin(ctx, tx)
+		}); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit(ctx)
+}
+
+// requestScheduledCommands queues the commands of all
+// due command schedules. Missed runs are queued only once.
+func (c *Controller) requestScheduledCommands(ctx context.Context) error {
+	tx, err := store.ConnectionFromContext(ctx).Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx) //nolint
+
+	now := time.Now()
+	due, err := store.GetCommandSchedules(ctx, tx, store.Q().
+		Where("command_schedules.next_run_at <= ?", now.UTC()))
+	if err != nil {
+		return err
+	}
+	for _, s := range due {
+		log.Debug().
+			Str("cmd", s.Action).
+			Str("scheduleID", s.ID).
+			Msg("DISPATCH")
+		if err := store.QueueCommand(ctx, tx, s.Command()); err != nil {
+			return err
+		}
+
+		lastRun := now.UTC()
+		s.LastRunAt = &lastRun
+		if err := s.Advance(now); err != nil {
+			return err
+		}
+		if err := s.Save(ctx, tx); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit(ctx)
+}
+
+// requestBackendDrains will dispatch ending empty
+// meetings on all draining backends. The progress of
+// canceled drains is reset.
+func (c *Controller) requestBackendDrains(ctx context.Context) error {
+	tx, err := store.ConnectionFromContext(ctx).Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx) //nolint
+
+	if err := store.ResetBackendDrains(ctx, tx); err != nil {
+		return err
+	}
+
+	states, err := store.GetBackendStates(ctx, tx, store.Q().
+		Where("admin_state = ?", BackendStateDraining))
+	if err != nil {
+		return err
+	}
+
+	for _, s := range states {
+		log.Debug().
+			Str("host", s.Backend.Host).
+			Str("backendID", s.ID).
+			Msg("requesting backend drain")
+
+		if err := store.QueueCommand(ctx, tx,
+			DrainBackend(&DrainBackendRequest{
+				ID: s.ID,
+			})); err != nil {
+			log.Error().
+				Err(err).
+				Msg("could not queue drain backend request")
+		}
+	}
+
+	return tx.Commit(ctx)
+}
+
 // requestCollectGarbage will dispatch a collect
 // garbage command.
 func (c *Controller) requestCollectGarbage(
@@ -542,7 +1112,8 @@ func (c *Controller) requestCollectGarbage(
 		Str("cmd", "CollectGarbage").
 		Msg("DISPATCH")
 
-	if err := store.QueueCommand(ctx, tx, CollectGarbage()); err != nil {
+	if err := store.QueueCommand(ctx, tx,
+		CollectGarbage(&CollectGarbageRequest{})); err != nil {
 		return err
 	}
 
@@ -575,3 +1146,93 @@ func (c *Controller) warnOfflineBackends(ctx context.Context) error {
 
 	return nil
 }
+
+// failoverDeadBackends detaches the meetings from all
+// backends where the node agent was not seen for longer
+// than the failover timeout. When the meeting is created
+// again, a new backend will be selected.
+func (c *Controller) failoverDeadBackends(ctx context.Context) error {
+	if c.failoverTimeout <= 0 {
+		return nil // failover is disabled
+	}
+
+	tx, err := store.ConnectionFromContext(ctx).Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx) //nolint
+
+	deadline := time.Now().UTC().Add(-c.failoverTimeout)
+	states, err := store.GetBackendStates(ctx, tx, store.Q().
+		Where("backends.agent_heartbeat < ?", deadline).
+		Where("backends.meetings_count > 0"))
+	if err != nil {
+		return err
+	}
+
+	for _, s := range states {
+		count, err := s.DetachMeetings(ctx, tx)
+		if err != nil {
+			return err
+		}
+		log.Warn().
+			Str("backendID", s.ID).
+			Str("host", s.Backend.Host).
+			Time("agentHeartbeat", s.AgentHeartbeat).
+			Int64("meetings", count).
+			Msg("agent is gone, detached meetings from backend")
+		if err := EmitEvent(ctx, tx, NewBackendFailoverEvent(s)); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit(ctx)
+}
+
+// Command: DeliverWebhook
+// Posts a signed event to a webhook. An error will
+// cause the delivery to be retried.
+func (c *Controller) handleDeliverWebhook(
+	ctx context.Context,
+	cmd *store.Command,
+) (interface{}, error) {
+	req := &DeliverWebhookRequest{}
+	if err := cmd.FetchParams(ctx, req); err != nil {
+		return nil, err
+	}
+	if req.Event == nil {
+		return nil, fmt.Errorf("event is required")
+	}
+
+	tx, err := store.ConnectionFromContext(ctx).Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx) //nolint
+
+	webhook, err := store.GetWebhook(ctx, tx, store.Q().
+		Where("webhooks.id = ?", req.WebhookID))
+	if err != nil {
+		return nil, err
+	}
+	// Release the connection before the request
+	tx.Rollback(ctx) //nolint
+
+	if webhook == nil || !webhook.Active {
+		return false, nil // The subscription was removed
+	}
+
+	payload, err := json.Marshal(req.Event)
+	if err != nil {
+		return nil, err
+	}
+	hook := callbacks.PostWebhook(webhook.URL, webhook.Secret, &callbacks.Webhook{
+		Event:    req.Event.Type,
+		Delivery: req.Event.ID,
+		Payload:  payload,
+	})
+	if err := callbacks.Invoke(ctx, hook); err != nil {
+		return nil, err
+	}
+	return true, nil
+}
@@ -2,9 +2,44 @@ package cluster
 
 import (
 	"testing"
+	"time"
 
 	_ "github.com/jackc/pgx/v4/pgxpool"
+
+	"github.com/b3scale/b3scale/pkg/bbb"
+	"github.com/b3scale/b3scale/pkg/store"
 )
 
 func TestGetBackend(t *testing.T) {
 }
+
+func TestIsMeetingDrainable(t *testing.T) {
+	now := time.Now().UTC()
+	old := now.Add(-2 * DrainGracePeriod)
+
+	empty := &store.MeetingState{
+		CreatedAt: old,
+		Meeting:   &bbb.Meeting{},
+	}
+	if !isMeetingDrainable(empty, now) {
+		t.Error("expected empty meeting to be drainable")
+	}
+
+	fresh := &store.MeetingState{
+		CreatedAt: now,
+		Meeting:   &bbb.Meeting{},
+	}
+	if isMeetingDrainable(fresh, now) {
+		t.Error("meeting within grace period should not be drainable")
+	}
+
+	attended := &store.MeetingState{
+		CreatedAt: old,
+		Meeting: &bbb.Meeting{
+			Attendees: []*bbb.Attendee{{UserID: "user1"}},
+		},
+	}
+	if isMeetingDrainable(attended, now) {
+		t.Error("meeting with attendees should not be drainable")
+	}
+}
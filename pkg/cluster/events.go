@@ -0,0 +1,440 @@
+package cluster
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v4"
+	"github.com/rs/zerolog/log"
+
+	"github.com/b3scale/b3scale/pkg/bbb"
+	"github.com/b3scale/b3scale/pkg/store"
+)
+
+// An Event notifies subscribers about a change
+// in the cluster. The event types are declared
+// in the store.
+type Event struct {
+	ID        string    `json:"id" doc:"The event ID. Retried deliveries have the same ID."`
+	Type      string    `json:"type" doc:"The event type." example:"meeting.created"`
+	CreatedAt time.Time `json:"created_at"`
+
+	FrontendID *string `json:"frontend_id" doc:"The frontend related to the event, if any."`
+	BackendID  *string `json:"backend_id" doc:"The backend related to the event, if any."`
+
+	Data interface{} `json:"data" doc:"The event payload, depending on the event type."`
+}
+
+// NewEvent creates a new event
+func NewEvent(eventType string, data interface{}) *Event {
+	return &Event{
+		ID:        uuid.New().String(),
+		Type:      eventType,
+		CreatedAt: time.Now().UTC(),
+		Data:      data,
+	}
+}
+
+// MeetingEventData is the payload of the
+// meeting.created and meeting.ended events.
+type MeetingEventData struct {
+	MeetingID         string `json:"meeting_id"`
+	InternalMeetingID string `json:"internal_meeting_id"`
+	MeetingName       string `json:"meeting_name"`
+}
+
+// NewMeetingEvent creates a meeting event
+func NewMeetingEvent(eventType string, m *store.MeetingState) *Event {
+	data := &MeetingEventData{
+		MeetingID:         m.ID,
+		InternalMeetingID: m.InternalID,
+	}
+	if m.Meeting != nil {
+		data.MeetingName = m.Meeting.MeetingName
+	}
+	ev := NewEvent(eventType, data)
+	ev.FrontendID = m.FrontendID
+	ev.BackendID = m.BackendID
+	return ev
+}
+
+// AttendeeEventData is the payload of the
+// attendee.joined and attendee.left events.
+type AttendeeEventData struct {
+	MeetingID         string `json:"meeting_id"`
+	InternalMeetingID string `json:"internal_meeting_id"`
+	UserID            string `json:"user_id"`
+	InternalUserID    string `json:"internal_user_id"`
+	FullName          string `json:"full_name"`
+	Role              string `json:"role"`
+	Attendees         int    `json:"attendees"`
+}
+
+// NewAttendeeEvent creates an attendee event. The attendee
+// may be nil, if only the internal user ID is known.
+func NewAttendeeEvent(
+	eventType string,
+	m *store.MeetingState,
+	internalUserID string,
+	a *bbb.Attendee,
+) *Event {
+	data := &AttendeeEventData{
+		MeetingID:         m.ID,
+		InternalMeetingID: m.InternalID,
+		InternalUserID:    internalUserID,
+	}
+	if a != nil {
+		data.UserID = a.UserID
+		data.FullName = a.FullName
+		data.Role = a.Role
+	}
+	if m.Meeting != nil {
+		data.Attendees = len(m.Meeting.Attendees)
+	}
+	ev := NewEvent(eventType, data)
+	ev.FrontendID = m.FrontendID
+	ev.BackendID = m.BackendID
+	return ev
+}
+
+// BackendStateEventData is the payload of the
+// backend.state_changed event.
+type BackendStateEventData struct {
+	Host               string `json:"host"`
+	NodeState          string `json:"node_state"`
+	AdminState         string `json:"admin_state"`
+	PreviousNodeState  string `json:"previous_node_state"`
+	PreviousAdminState string `json:"previous_admin_state"`
+
+	// Reason is set if the event is not caused
+	// by a change of the node or admin state.
+	Reason string `json:"reason,omitempty"`
+}
+
+// BackendStateReasonFailover is the reason of the event
+// when the meetings were detached from a backend,
+// because the node agent is gone.
+const BackendStateReasonFailover = "failover"
+
+// NewBackendStateEvent creates a backend.state_changed event
+func NewBackendStateEvent(
+	state *store.BackendState,
+	prevNodeState string,
+	prevAdminState string,
+) *Event {
+	ev := NewEvent(store.EventBackendStateChanged, &BackendStateEventData{
+		Host:               state.Backend.Host,
+		NodeState:          state.NodeState,
+		AdminState:         state.AdminState,
+		PreviousNodeState:  prevNodeState,
+		PreviousAdminState: prevAdminState,
+	})
+	ev.BackendID = &state.ID
+	return ev
+}
+
+// NewBackendFailoverEvent creates a backend.state_changed
+// event for a backend, where the meetings were detached
+// because the node agent is gone.
+func NewBackendFailoverEvent(state *store.BackendState) *Event {
+	ev := NewBackendStateEvent(state, state.NodeState, state.AdminState)
+	ev.Data.(*BackendStateEventData).Reason = BackendStateReasonFailover
+	return ev
+}
+
+// RecordingEventData is the payload of the
+// recording.imported event.
+type RecordingEventData struct {
+	RecordID          string `json:"record_id"`
+	MeetingID         string `json:"meeting_id"`
+	InternalMeetingID string `json:"internal_meeting_id"`
+}
+
+// NewRecordingEvent creates a recording.imported event
+func NewRecordingEvent(rec *store.RecordingState) *Event {
+	ev := NewEvent(store.EventRecordingImported, &RecordingEventData{
+		RecordID:          rec.RecordID,
+		MeetingID:         rec.MeetingID,
+		InternalMeetingID: rec.InternalMeetingID,
+	})
+	if rec.FrontendID != "" {
+		ev.FrontendID = &rec.FrontendID
+	}
+	return ev
+}
+
+// AttendeesLimitEventData is the payload of the
+// frontend.attendees_limit_reached event.
+type AttendeesLimitEventData struct {
+	FrontendKey string `json:"frontend_key"`
+	MeetingID   string `json:"meeting_id"`
+	Limit       int    `json:"limit"`
+	Attendees   int    `json:"attendees"`
+}
+
+// NewAttendeesLimitEvent creates a
+// frontend.attendees_limit_reached event
+func NewAttendeesLimitEvent(
+	fe *Frontend,
+	meetingID string,
+	limit int,
+	attendees int,
+) *Event {
+	ev := NewEvent(store.EventFrontendAttendeesLimitReached,
+		&AttendeesLimitEventData{
+			FrontendKey: fe.Key(),
+			MeetingID:   meetingID,
+			Limit:       limit,
+			Attendees:   attendees,
+		})
+	id := fe.ID()
+	ev.FrontendID = &id
+	return ev
+}
+
+// HeartbeatEventData is the payload of the
+// backend.heartbeat event.
+type HeartbeatEventData struct {
+	Host       string    `json:"host"`
+	NodeState  string    `json:"node_state"`
+	AdminState string    `json:"admin_state"`
+	Heartbeat  time.Time `json:"heartbeat"`
+}
+
+// NewHeartbeatEvent creates a backend.heartbeat event
+func NewHeartbeatEvent(
+	state *store.BackendState,
+	heartbeat *store.AgentHeartbeat,
+) *Event {
+	ev := NewEvent(store.EventBackendHeartbeat, &HeartbeatEventData{
+		Host:       state.Backend.Host,
+		NodeState:  state.NodeState,
+		AdminState: state.AdminState,
+		Heartbeat:  heartbeat.Heartbeat,
+	})
+	ev.BackendID = &state.ID
+	return ev
+}
+
+// CommandEventData is the payload of the
+// command.state_changed event.
+type CommandEventData struct {
+	CommandID   string  `json:"command_id"`
+	Action      string  `json:"action"`
+	State       string  `json:"state"`
+	Attempts    int     `json:"attempts"`
+	MaxAttempts int     `json:"max_attempts"`
+	Duration    float64 `json:"duration_seconds"`
+}
+
+// NewCommandEvent creates a command.state_changed event
+func NewCommandEvent(
+	cmd *store.Command,
+	state string,
+	duration time.Duration,
+) *Event {
+	return NewEvent(store.EventCommandStateChanged, &CommandEventData{
+		CommandID:   cmd.ID,
+		Action:      cmd.Action,
+		State:       state,
+		Attempts:    cmd.Attempts,
+		MaxAttempts: cmd.MaxAttempts,
+		Duration:    duration.Seconds(),
+	})
+}
+
+// PublishEvent sends the event to the subscribers of the
+// events stream on all instances, when the transaction
+// is committed.
+func PublishEvent(ctx context.Context, tx pgx.Tx, ev *Event) error {
+	payload, err := json.Marshal(ev)
+	if err != nil {
+		return err
+	}
+	return store.NotifyEvent(ctx, tx, payload)
+}
+
+// EmitEvent publishes the event and queues a delivery
+// for each webhook subscribed to the event. The deliveries
+// are queued in the transaction, so they are only sent
+// if the change causing the event is committed.
+func EmitEvent(ctx context.Context, tx pgx.Tx, ev *Event) error {
+	if err := PublishEvent(ctx, tx, ev); err != nil {
+		return err
+	}
+	webhooks, err := store.GetWebhooksForEvent(
+		ctx, tx, ev.Type, ev.FrontendID)
+	if err != nil {
+		return err
+	}
+	for _, w := range webhooks {
+		cmd := DeliverWebhook(&DeliverWebhookRequest{
+			WebhookID: w.ID,
+			Event:     ev,
+		})
+		if err := store.QueueCommand(ctx, tx, cmd); err != nil {
+			return err
+		}
+	}
+	if len(webhooks) > 0 {
+		log.Debug().
+			Str("event", ev.Type).
+			Str("event_id", ev.ID).
+			Int("webhooks", len(webhooks)).
+			Msg("queued webhook deliveries")
+	}
+	return nil
+}
+
+// EmitBackendStateChanged emits a backend.state_changed
+// event if the node or admin state changed.
+func EmitBackendStateChanged(
+	ctx context.Context,
+	tx pgx.Tx,
+	state *store.BackendState,
+	prevNodeState string,
+	prevAdminState string,
+) error {
+	if state.NodeState == prevNodeState &&
+		state.AdminState == prevAdminState {
+		return nil
+	}
+	return EmitEvent(ctx, tx, NewBackendStateEvent(
+		state, prevNodeState, prevAdminState))
+}
+
+// EmitBackendStateChange applies a change to the backend and
+// emits a backend.state_changed event, if the node or admin
+// state changed. A removed backend is reported as destroyed.
+func EmitBackendStateChange(
+	ctx context.Context,
+	tx pgx.Tx,
+	backendID string,
+	change func() error,
+) error {
+	q := store.Q().Where("id = ?", backendID)
+	prev, err := store.GetBackendState(ctx, tx, q)
+	if err != nil {
+		return err
+	}
+	if err := change(); err != nil {
+		return err
+	}
+	if prev == nil {
+		return nil
+	}
+	state, err := store.GetBackendState(ctx, tx, q)
+	if err != nil {
+		return err
+	}
+	if state == nil {
+		removed := *prev
+		removed.AdminState = BackendStateDestroyed
+		state = &removed
+	}
+	return EmitBackendStateChanged(
+		ctx, tx, state, prev.NodeState, prev.AdminState)
+}
+
+// PublishCommandEvent publishes a command.state_changed
+// event in the transaction of the processed command, so
+// no additional connection is needed.
+func PublishCommandEvent(
+	ctx context.Context,
+	tx pgx.Tx,
+	cmd *store.Command,
+	state string,
+	duration time.Duration,
+) error {
+	return PublishEvent(ctx, tx, NewCommandEvent(cmd, state, duration))
+}
+
+// EventSubscriberBufferSize is the number of events
+// buffered for a subscriber. If the subscriber can not
+// keep up, events are dropped.
+const EventSubscriberBufferSize = 100
+
+// An EventBroker distributes the events published by all
+// instances to the subscribers of this instance. The broker
+// only listens for events while there are subscribers.
+type EventBroker struct {
+	mtx         sync.Mutex
+	subscribers map[chan *Event]struct{}
+	stop        context.CancelFunc
+
+	// listen is replaced in tests
+	listen func(context.Context, func([]byte))
+}
+
+// NewEventBroker creates a new event broker
+func NewEventBroker() *EventBroker {
+	return &EventBroker{
+		subscribers: map[chan *Event]struct{}{},
+		listen:      store.ListenEvents,
+	}
+}
+
+// Subscribe returns a channel receiving all events
+// and a function for ending the subscription.
+func (b *EventBroker) Subscribe() (<-chan *Event, func()) {
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+
+	ch := make(chan *Event, EventSubscriberBufferSize)
+	b.subscribers[ch] = struct{}{}
+	if b.stop == nil {
+		ctx, cancel := context.WithCancel(context.Background())
+		b.stop = cancel
+		go b.listen(ctx, b.dispatch)
+	}
+
+	unsubscribe := func() {
+		b.mtx.Lock()
+		defer b.mtx.Unlock()
+		if _, ok := b.subscribers[ch]; !ok {
+			return
+		}
+		delete(b.subscribers, ch)
+		close(ch)
+		if len(b.subscribers) == 0 && b.stop != nil {
+			b.stop()
+			b.stop = nil
+		}
+	}
+	return ch, unsubscribe
+}
+
+// dispatch decodes the event and sends it
+// to all subscribers
+func (b *EventBroker) dispatch(payload []byte) {
+	ev := &Event{}
+	if err := json.Unmarshal(payload, ev); err != nil {
+		log.Error().Err(err).Msg("could not decode event")
+		return
+	}
+
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+	for ch := range b.subscribers {
+		select {
+		case ch <- ev:
+		default:
+			log.Warn().
+				Str("event", ev.Type).
+				Msg("event subscriber is too slow, dropping event")
+		}
+	}
+}
+
+// eventBroker is the event broker of this instance
+var eventBroker = NewEventBroker()
+
+// SubscribeEvents subscribes to the events of the cluster.
+// The subscription must be ended by calling the returned
+// function.
+func SubscribeEvents() (<-chan *Event, func()) {
+	return eventBroker.Subscribe()
+}
@@ -0,0 +1,123 @@
+package cluster
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/b3scale/b3scale/pkg/bbb"
+	"github.com/b3scale/b3scale/pkg/store"
+)
+
+func TestNewAttendeeEvent(t *testing.T) {
+	frontendID := "fe1"
+	m := &store.MeetingState{
+		ID:         "meeting1",
+		InternalID: "internal1",
+		FrontendID: &frontendID,
+		Meeting: &bbb.Meeting{
+			Attendees: []*bbb.Attendee{
+				{InternalUserID: "w_23", FullName: "Jane"},
+			},
+		},
+	}
+	ev := NewAttendeeEvent(
+		store.EventAttendeeJoined, m, "w_23", m.Meeting.Attendees[0])
+	if ev.ID == "" {
+		t.Error("expected an event ID")
+	}
+	if ev.FrontendID == nil || *ev.FrontendID != "fe1" {
+		t.Error("unexpected frontend:", ev.FrontendID)
+	}
+
+	// Decode the payload as a webhook receiver would
+	buf, err := json.Marshal(ev)
+	if err != nil {
+		t.Fatal(err)
+	}
+	payload := &struct {
+		Type string             `json:"type"`
+		Data *AttendeeEventData `json:"data"`
+	}{}
+	if err := json.Unmarshal(buf, payload); err != nil {
+		t.Fatal(err)
+	}
+	if payload.Type != store.EventAttendeeJoined {
+		t.Error("unexpected type:", payload.Type)
+	}
+	if payload.Data.FullName != "Jane" || payload.Data.Attendees != 1 {
+		t.Error("unexpected data:", payload.Data)
+	}
+}
+
+func TestEmitBackendStateChangedUnchanged(t *testing.T) {
+	state := &store.BackendState{
+		NodeState:  BackendStateReady,
+		AdminState: BackendStateReady,
+		Backend:    &bbb.Backend{Host: "https://bbb1/"},
+	}
+	// Without a change, no event is emitted and the
+	// transaction is not used.
+	if err := EmitBackendStateChanged(
+		context.Background(), nil, state,
+		BackendStateReady, BackendStateReady,
+	); err != nil {
+		t.Error(err)
+	}
+
+	ev := NewBackendStateEvent(state, BackendStateError, BackendStateReady)
+	data := ev.Data.(*BackendStateEventData)
+	if data.PreviousNodeState != BackendStateError {
+		t.Error("unexpected previous node state:", data.PreviousNodeState)
+	}
+}
+
+func TestEventBrokerSubscribe(t *testing.T) {
+	listening := make(chan func([]byte), 1)
+	stopped := make(chan struct{})
+	b := NewEventBroker()
+	b.listen = func(ctx context.Context, handler func([]byte)) {
+		listening <- handler
+		<-ctx.Done()
+		close(stopped)
+	}
+
+	events, unsubscribe := b.Subscribe()
+	dispatch := <-listening
+
+	payload, _ := json.Marshal(NewEvent(store.EventMeetingCreated, nil))
+	dispatch(payload)
+	dispatch([]byte("invalid")) // should be ignored
+
+	ev := <-events
+	if ev.Type != store.EventMeetingCreated {
+		t.Error("unexpected event:", ev.Type)
+	}
+
+	unsubscribe()
+	unsubscribe() // should be safe
+	if _, ok := <-events; ok {
+		t.Error("expected closed channel")
+	}
+	<-stopped
+}
+
+func TestNewBackendFailoverEvent(t *testing.T) {
+	state := &store.BackendState{
+		ID:         "backend1",
+		NodeState:  "ready",
+		AdminState: "ready",
+		Backend:    &bbb.Backend{Host: "https://bbb1/"},
+	}
+	ev := NewBackendFailoverEvent(state)
+	if ev.Type != store.EventBackendStateChanged {
+		t.Error("unexpected type:", ev.Type)
+	}
+	data := ev.Data.(*BackendStateEventData)
+	if data.Reason != BackendStateReasonFailover {
+		t.Error("unexpected reason:", data.Reason)
+	}
+	if ev.BackendID == nil || *ev.BackendID != "backend1" {
+		t.Error("unexpected backend:", ev.BackendID)
+	}
+}
@@ -0,0 +1,150 @@
+package cluster
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/b3scale/b3scale/pkg/config"
+)
+
+// Garbage collection rules
+const (
+	GCFrontendMeetings           = "frontend_meetings"
+	GCFinishedCommands           = "finished_commands"
+	GCOrphanMeetings             = "orphan_meetings"
+	GCInactiveFrontendRecordings = "inactive_frontend_recordings"
+	GCInactiveFrontends          = "inactive_frontends"
+	GCMeetingsHistory            = "meetings_history"
+)
+
+// A GCRule removes data older than the retention
+// in days. The rule is disabled if the retention is 0.
+// In a dry run, the data is only counted.
+type GCRule struct {
+	Days   int  `json:"days"`
+	DryRun bool `json:"dry_run"`
+}
+
+// Enabled is true if the rule has a retention
+func (r *GCRule) Enabled() bool {
+	return r != nil && r.Days > 0
+}
+
+// Threshold calculates the point in time, before
+// which data is collected.
+func (r *GCRule) Threshold(now time.Time) time.Time {
+	return now.Add(-time.Duration(r.Days) * 24 * time.Hour)
+}
+
+// The GCPolicy configures the garbage collection.
+type GCPolicy struct {
+	// FrontendMeetings are the mappings of meeting IDs
+	// to frontends, used for associating recordings.
+	FrontendMeetings *GCRule `json:"frontend_meetings,omitempty"`
+
+	// FinishedCommands are commands in the success, error,
+	// dead or canceled state.
+	FinishedCommands *GCRule `json:"finished_commands,omitempty"`
+
+	// OrphanMeetings are meetings without a backend.
+	OrphanMeetings *GCRule `json:"orphan_meetings,omitempty"`
+
+	// InactiveFrontendRecordings are the recordings, including
+	// the files, of frontends inactive longer than the retention.
+	InactiveFrontendRecordings *GCRule `json:"inactive_frontend_recordings,omitempty"`
+
+	// InactiveFrontends are frontends inactive longer than
+	// the retention. Frontends with recordings are kept.
+	InactiveFrontends *GCRule `json:"inactive_frontends,omitempty"`
+
+	// MeetingsHistory are the archived meetings, which
+	// ended longer than the retention ago.
+	MeetingsHistory *GCRule `json:"meetings_history,omitempty"`
+}
+
+// DefaultGCPolicy keeps the frontend meetings and finished
+// commands for a week. Orphan meetings, frontends, recordings
+// and the meetings history are never removed by default.
+func DefaultGCPolicy() *GCPolicy {
+	return &GCPolicy{
+		FrontendMeetings:           &GCRule{Days: 7},
+		FinishedCommands:           &GCRule{Days: 7},
+		OrphanMeetings:             &GCRule{},
+		InactiveFrontendRecordings: &GCRule{},
+		InactiveFrontends:          &GCRule{},
+		MeetingsHistory:            &GCRule{},
+	}
+}
+
+// Merge creates a copy of the policy, where all rules
+// set in the update take precedence.
+func (p *GCPolicy) Merge(update *GCPolicy) *GCPolicy {
+	merged := *p
+	if update == nil {
+		return &merged
+	}
+	if update.FrontendMeetings != nil {
+		merged.FrontendMeetings = update.FrontendMeetings
+	}
+	if update.FinishedCommands != nil {
+		merged.FinishedCommands = update.FinishedCommands
+	}
+	if update.OrphanMeetings != nil {
+		merged.OrphanMeetings = update.OrphanMeetings
+	}
+	if update.InactiveFrontendRecordings != nil {
+		merged.InactiveFrontendRecordings = update.InactiveFrontendRecordings
+	}
+	if update.InactiveFrontends != nil {
+		merged.InactiveFrontends = update.InactiveFrontends
+	}
+	if update.MeetingsHistory != nil {
+		merged.MeetingsHistory = update.MeetingsHistory
+	}
+	return &merged
+}
+
+// GCPolicyFromEnv reads the garbage collection policy
+// from the environment. Rules not present in the
+// configuration use the default.
+func GCPolicyFromEnv() (*GCPolicy, error) {
+	policy := DefaultGCPolicy()
+	repr, ok := config.GetEnvOpt(config.EnvGCPolicy)
+	if !ok {
+		return policy, nil
+	}
+	update := &GCPolicy{}
+	if err := json.Unmarshal([]byte(repr), update); err != nil {
+		return nil, fmt.Errorf(
+			"invalid %s: %w", config.EnvGCPolicy, err)
+	}
+	return policy.Merge(update), nil
+}
+
+// GCReport is the result of a garbage collection rule
+type GCReport struct {
+	Count  int64 `json:"count"`
+	Failed int64 `json:"failed,omitempty"`
+	DryRun bool  `json:"dry_run"`
+}
+
+var (
+	gcPolicy    = DefaultGCPolicy()
+	gcPolicyMtx sync.RWMutex
+)
+
+// SetGCPolicy configures the garbage collection
+func SetGCPolicy(policy *GCPolicy) {
+	gcPolicyMtx.Lock()
+	defer gcPolicyMtx.Unlock()
+	gcPolicy = DefaultGCPolicy().Merge(policy)
+}
+
+// GetGCPolicy returns the current garbage collection policy
+func GetGCPolicy() *GCPolicy {
+	gcPolicyMtx.RLock()
+	defer gcPolicyMtx.RUnlock()
+	return gcPolicy
+}
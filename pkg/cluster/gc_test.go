@@ -0,0 +1,48 @@
+package cluster
+
+import (
+	"testing"
+	"time"
+
+	"github.com/b3scale/b3scale/pkg/config"
+)
+
+func TestGCPolicyFromEnv(t *testing.T) {
+	t.Setenv(config.EnvGCPolicy, `{
+		"finished_commands": {"days": 30},
+		"inactive_frontends": {"days": 90, "dry_run": true}
+	}`)
+	policy, err := GCPolicyFromEnv()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if policy.FinishedCommands.Days != 30 {
+		t.Error("unexpected finished commands rule:", policy.FinishedCommands)
+	}
+	if !policy.InactiveFrontends.Enabled() || !policy.InactiveFrontends.DryRun {
+		t.Error("unexpected inactive frontends rule:", policy.InactiveFrontends)
+	}
+	if policy.FrontendMeetings.Days != 7 {
+		t.Error("expected default frontend meetings rule")
+	}
+	if policy.InactiveFrontendRecordings.Enabled() {
+		t.Error("recordings should not be collected by default")
+	}
+
+	t.Setenv(config.EnvGCPolicy, `{"orphan_meetings": 1}`)
+	if _, err := GCPolicyFromEnv(); err == nil {
+		t.Error("expected an error")
+	}
+}
+
+func TestGCRuleThreshold(t *testing.T) {
+	now := time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC)
+	r := &GCRule{Days: 2}
+	if th := r.Threshold(now); !th.Equal(time.Date(2026, 10, 15, 12, 0, 0, 0, time.UTC)) {
+		t.Error("unexpected threshold:", th)
+	}
+	var disabled *GCRule
+	if disabled.Enabled() {
+		t.Error("nil rule should be disabled")
+	}
+}
@@ -0,0 +1,92 @@
+package cluster
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/b3scale/b3scale/pkg/bbb"
+	"github.com/b3scale/b3scale/pkg/store"
+)
+
+// Routing hints a frontend can pass as meta
+// parameters when creating a meeting.
+const (
+	HintExpectedAttendees = "expected-attendees"
+	HintTags              = "tags"
+)
+
+// Meta parameters for passing routing hints
+var (
+	MetaParamExpectedAttendees = bbb.MetaParam("b3scale-" + HintExpectedAttendees)
+	MetaParamTags              = bbb.MetaParam("b3scale-" + HintTags)
+)
+
+// RoutingHints are validated hints for selecting
+// a backend for a new meeting.
+type RoutingHints struct {
+	// ExpectedAttendees is the number of attendees
+	// the meeting will presumably have.
+	ExpectedAttendees uint
+
+	// Tags the backend is required to provide.
+	Tags []string
+}
+
+// RoutingHintsFromParams reads the routing hints from the
+// request parameters. Only hints allowed by the settings
+// are considered, all others are ignored.
+// If no hint is present, nil is returned.
+func RoutingHintsFromParams(
+	params bbb.Params,
+	settings *store.RoutingHintsSettings,
+) *RoutingHints {
+	if settings == nil {
+		return nil
+	}
+	hints := &RoutingHints{}
+	present := false
+
+	if repr, ok := params[MetaParamExpectedAttendees]; ok &&
+		settings.Allows(HintExpectedAttendees) {
+		n, err := strconv.ParseUint(strings.TrimSpace(repr), 10, 32)
+		if err != nil {
+			log.Warn().
+				Err(err).
+				Str("hint", HintExpectedAttendees).
+				Msg("ignoring invalid routing hint")
+		} else {
+			if settings.MaxExpectedAttendees > 0 &&
+				n > uint64(settings.MaxExpectedAttendees) {
+				n = uint64(settings.MaxExpectedAttendees)
+			}
+			hints.ExpectedAttendees = uint(n)
+			present = true
+		}
+	}
+
+	if repr, ok := params[MetaParamTags]; ok &&
+		settings.Allows(HintTags) {
+		for _, tag := range strings.Split(repr, ",") {
+			tag = strings.TrimSpace(tag)
+			if tag == "" {
+				continue
+			}
+			if !settings.AllowsTag(tag) {
+				log.Warn().
+					Str("hint", HintTags).
+					Str("tag", tag).
+					Msg("ignoring tag not allowed as routing hint")
+				continue
+			}
+			hints.Tags = append(hints.Tags, tag)
+			present = true
+		}
+	}
+
+	if !present {
+		return nil
+	}
+	return hints
+}
@@ -0,0 +1,62 @@
+package cluster
+
+import (
+	"testing"
+
+	"github.com/b3scale/b3scale/pkg/bbb"
+	"github.com/b3scale/b3scale/pkg/store"
+)
+
+func TestRoutingHintsFromParams(t *testing.T) {
+	params := bbb.Params{
+		MetaParamExpectedAttendees: "300",
+		MetaParamTags:              "sip, premium,,gpu",
+	}
+
+	// Hints are not allowed by default
+	if hints := RoutingHintsFromParams(params, nil); hints != nil {
+		t.Error("unexpected hints:", hints)
+	}
+
+	settings := &store.RoutingHintsSettings{
+		Allowed:              []string{HintExpectedAttendees, HintTags},
+		AllowedTags:          store.Tags{"sip", "premium"},
+		MaxExpectedAttendees: 250,
+	}
+	hints := RoutingHintsFromParams(params, settings)
+	if hints == nil {
+		t.Fatal("expected hints")
+	}
+	if hints.ExpectedAttendees != 250 {
+		t.Error("unexpected expected attendees:", hints.ExpectedAttendees)
+	}
+	if len(hints.Tags) != 2 || hints.Tags[0] != "sip" || hints.Tags[1] != "premium" {
+		t.Error("unexpected tags:", hints.Tags)
+	}
+
+	// Only allow tags
+	settings.Allowed = []string{HintTags}
+	hints = RoutingHintsFromParams(params, settings)
+	if hints.ExpectedAttendees != 0 {
+		t.Error("expected attendees hint should be ignored")
+	}
+
+	// Invalid values are ignored
+	settings.Allowed = []string{HintExpectedAttendees}
+	params[MetaParamExpectedAttendees] = "lots"
+	if hints := RoutingHintsFromParams(params, settings); hints != nil {
+		t.Error("unexpected hints:", hints)
+	}
+}
+
+func TestRoutingHintsFromParamsNoAllowedTags(t *testing.T) {
+	params := bbb.Params{
+		MetaParamTags: "premium",
+	}
+	settings := &store.RoutingHintsSettings{
+		Allowed: []string{HintTags},
+	}
+	if hints := RoutingHintsFromParams(params, settings); hints != nil {
+		t.Error("tags should be denied without allow-list:", hints)
+	}
+}
@@ -0,0 +1,227 @@
+package cluster
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/b3scale/b3scale/pkg/bbb"
+	"github.com/b3scale/b3scale/pkg/store"
+)
+
+// Resolutions for meetings running on a node,
+// which are unknown to b3scale.
+const (
+	ReconcileAdopt = "adopt"
+	ReconcileEnd   = "end"
+)
+
+// A MeetingDiff is a meeting differing between
+// the store and the backend.
+type MeetingDiff struct {
+	MeetingID         string `json:"meeting_id"`
+	InternalMeetingID string `json:"internal_meeting_id"`
+	MeetingName       string `json:"meeting_name,omitempty"`
+
+	// FrontendID is the frontend of the meeting in the
+	// store, ExpectedFrontendID is the frontend known for
+	// the meeting ID from previous meetings.
+	FrontendID         *string `json:"frontend_id,omitempty"`
+	ExpectedFrontendID *string `json:"expected_frontend_id,omitempty"`
+
+	// Resolution is "adopted" or "ended" if the
+	// difference was resolved.
+	Resolution string  `json:"resolution,omitempty"`
+	Error      *string `json:"error,omitempty"`
+}
+
+// BackendReconciliation lists the differences between
+// the meetings in the store and on a backend.
+type BackendReconciliation struct {
+	BackendID string  `json:"backend_id"`
+	Host      string  `json:"host"`
+	Error     *string `json:"error,omitempty"`
+
+	// Unknown meetings are running on the node, but are
+	// not known or not associated with a frontend.
+	Unknown []*MeetingDiff `json:"unknown"`
+
+	// Missing meetings are in the store, but not
+	// running on the node.
+	Missing []*MeetingDiff `json:"missing"`
+
+	// FrontendMismatch lists meetings associated with a
+	// different frontend than the meeting ID was before.
+	FrontendMismatch []*MeetingDiff `json:"frontend_mismatch"`
+}
+
+// InSync is true if there are no differences
+func (r *BackendReconciliation) InSync() bool {
+	return r.Error == nil &&
+		len(r.Unknown) == 0 &&
+		len(r.Missing) == 0 &&
+		len(r.FrontendMismatch) == 0
+}
+
+// MeetingsReconciliation is the result of the
+// reconcile meetings command.
+type MeetingsReconciliation struct {
+	Backends []*BackendReconciliation `json:"backends"`
+}
+
+// newMeetingDiff creates a diff from a meeting
+func newMeetingDiff(m *bbb.Meeting) *MeetingDiff {
+	return &MeetingDiff{
+		MeetingID:         m.MeetingID,
+		InternalMeetingID: m.InternalMeetingID,
+		MeetingName:       m.MeetingName,
+	}
+}
+
+// reconcileMeetings compares the meetings on the node
+// with the store. Unknown meetings are adopted or ended,
+// depending on the resolution.
+func (b *Backend) reconcileMeetings(
+	ctx context.Context,
+	resolution string,
+) (*BackendReconciliation, error) {
+	report := &BackendReconciliation{
+		BackendID:        b.ID(),
+		Host:             b.Host(),
+		Unknown:          []*MeetingDiff{},
+		Missing:          []*MeetingDiff{},
+		FrontendMismatch: []*MeetingDiff{},
+	}
+
+	res, err := b.GetMeetings(ctx, bbb.GetMeetingsRequest(bbb.Params{}))
+	if err == nil && res.Returncode != bbb.RetSuccess {
+		err = fmt.Errorf("%s: %s", res.MessageKey, res.Message)
+	}
+	if err != nil {
+		// The node is not reachable, so we can not
+		// tell the differences.
+		errMsg := err.Error()
+		report.Error = &errMsg
+		return report, nil
+	}
+
+	tx, err := store.ConnectionFromContext(ctx).Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx) //nolint
+
+	onNode := make(map[string]bool, len(res.Meetings))
+	for _, m := range res.Meetings {
+		onNode[m.InternalMeetingID] = true
+
+		mstate, err := store.GetMeetingState(ctx, tx, store.Q().
+			Where("meetings.internal_id = ?", m.InternalMeetingID))
+		if err != nil {
+			return nil, err
+		}
+		frontendID, known, err := store.LookupFrontendIDByMeetingID(
+			ctx, tx, m.MeetingID)
+		if err != nil {
+			return nil, err
+		}
+
+		diff := newMeetingDiff(m)
+		if known {
+			diff.ExpectedFrontendID = &frontendID
+		}
+		if mstate == nil || mstate.FrontendID == nil {
+			report.Unknown = append(report.Unknown, diff)
+			continue
+		}
+		diff.FrontendID = mstate.FrontendID
+		if known && *mstate.FrontendID != frontendID {
+			report.FrontendMismatch = append(report.FrontendMismatch, diff)
+		}
+	}
+
+	// Meetings in the store, but gone from the node
+	mstates, err := store.GetMeetingStates(ctx, tx, store.Q().
+		Where("meetings.backend_id = ?", b.ID()))
+	if err != nil {
+		return nil, err
+	}
+	for _, m := range mstates {
+		if onNode[m.InternalID] {
+			continue
+		}
+		diff := newMeetingDiff(m.Meeting)
+		diff.FrontendID = m.FrontendID
+		report.Missing = append(report.Missing, diff)
+	}
+	tx.Rollback(ctx) //nolint
+
+	if resolution == "" {
+		return report, nil
+	}
+
+	meetings := make(map[string]*bbb.Meeting, len(res.Meetings))
+	for _, m := range res.Meetings {
+		meetings[m.InternalMeetingID] = m
+	}
+	for _, diff := range report.Unknown {
+		m := meetings[diff.InternalMeetingID]
+		var err error
+		switch resolution {
+		case ReconcileAdopt:
+			err = b.adoptMeeting(ctx, m, diff.ExpectedFrontendID)
+			diff.Resolution = "adopted"
+		case ReconcileEnd:
+			err = b.EndMeeting(ctx, &store.MeetingState{Meeting: m})
+			diff.Resolution = "ended"
+		}
+		if err != nil {
+			log.Error().
+				Err(err).
+				Str("backend", b.Host()).
+				Str("meetingID", m.MeetingID).
+				Str("resolution", resolution).
+				Msg("could not resolve unknown meeting")
+			errMsg := err.Error()
+			diff.Error = &errMsg
+			diff.Resolution = ""
+		}
+	}
+
+	return report, nil
+}
+
+// adoptMeeting stores a meeting running on the node
+// and binds it to the frontend, if it is known.
+func (b *Backend) adoptMeeting(
+	ctx context.Context,
+	m *bbb.Meeting,
+	frontendID *string,
+) error {
+	tx, err := store.ConnectionFromContext(ctx).Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx) //nolint
+
+	if err := b.state.CreateOrUpdateMeetingState(ctx, tx, m); err != nil {
+		return err
+	}
+	if frontendID != nil {
+		mstate, err := store.GetMeetingState(ctx, tx, store.Q().
+			Where("meetings.internal_id = ?", m.InternalMeetingID))
+		if err != nil {
+			return err
+		}
+		if err := mstate.BindFrontendID(ctx, tx, *frontendID); err != nil {
+			return err
+		}
+	}
+	log.Info().
+		Str("backend", b.Host()).
+		Str("meetingID", m.MeetingID).
+		Msg("adopted unknown meeting")
+
+	return tx.Commit(ctx)
+}
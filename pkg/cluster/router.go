@@ -22,6 +22,10 @@ var (
 	// available for creating a meeting.
 	ErrNoBackendAvailable = errors.New("no free backend available for meeting")
 
+	// ErrNoBackendCapacity indicates that there are backends
+	// available, however all of them reached their limits.
+	ErrNoBackendCapacity = errors.New("all backends reached their capacity limits")
+
 	// ErrMeetingIDMissing indicates that there is a meetingID
 	// expected to be in the requests params, but it is missing.
 	ErrMeetingIDMissing = errors.New("meetingID missing from request")
@@ -56,12 +60,18 @@ func nilHandler(
 	backends []*Backend,
 	req *bbb.Request,
 ) ([]*Backend, error) {
+	if trace := RoutingTraceFromContext(ctx); trace != nil {
+		trace.recordStress(ctx, backends)
+	}
 	return backends, nil
 }
 
-// Use will insert a middleware into the chain
+// Use will insert a middleware into the chain.
+// When a routing trace is present in the context, the
+// backends entering and leaving the middleware are recorded.
 func (r *Router) Use(middleware RouterMiddleware) {
-	r.middleware = middleware(r.middleware)
+	name := middlewareName(middleware)
+	r.middleware = traceMiddleware(name, middleware)(r.middleware)
 }
 
 // SelectBackend will apply the routing middleware
@@ -69,6 +79,9 @@ func (r *Router) Use(middleware RouterMiddleware) {
 // the cluster where the admin state is also ready.
 // Selecting a backend will fail if no backends are available
 // as routing targets.
+//
+// If the context has a routing trace, the selection
+// is recorded in the trace.
 func (r *Router) SelectBackend(
 	ctx context.Context, req *bbb.Request,
 ) (*Backend, error) {
@@ -84,12 +97,15 @@ func (r *Router) SelectBackend(
 		return nil, err
 	}
 	backends, err = r.middleware(ctx, backends, req)
+	if err == nil && len(backends) == 0 {
+		err = ErrNoBackendAvailable
+	}
+	if trace := RoutingTraceFromContext(ctx); trace != nil {
+		trace.finish(backends, err)
+	}
 	if err != nil {
 		return nil, err
 	}
-	if len(backends) == 0 {
-		return nil, ErrNoBackendAvailable
-	}
 
 	// Use first backend
 	return backends[0], nil
@@ -0,0 +1,259 @@
+package cluster
+
+import (
+	"context"
+	"reflect"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/b3scale/b3scale/pkg/bbb"
+	"github.com/b3scale/b3scale/pkg/store"
+)
+
+// A RoutingTrace records the steps of selecting
+// a backend for a request. Routing middlewares can
+// explain why a backend was removed.
+type RoutingTrace struct {
+	record *store.RoutingRecord
+	stress map[string]float64
+
+	// simulation is set when the backend selection is
+	// only simulated, e.g. through the routing API.
+	simulation bool
+}
+
+// NewRoutingTrace creates a new empty trace
+func NewRoutingTrace() *RoutingTrace {
+	return &RoutingTrace{
+		record: &store.RoutingRecord{
+			CreatedAt:  time.Now().UTC(),
+			Steps:      []*store.RoutingStep{},
+			Candidates: []*store.RoutingCandidate{},
+		},
+		stress: make(map[string]float64),
+	}
+}
+
+// NewRoutingSimulationTrace creates a trace for a simulated
+// backend selection. Routing middlewares must not have
+// side effects, like updating metrics, in a simulation.
+func NewRoutingSimulationTrace() *RoutingTrace {
+	t := NewRoutingTrace()
+	t.simulation = true
+	return t
+}
+
+// Record returns the routing record
+func (t *RoutingTrace) Record() *store.RoutingRecord {
+	return t.record
+}
+
+// A routingStep is the currently traced middleware
+type routingStep struct {
+	record  *store.RoutingStep
+	in      []*Backend
+	reasons map[string]string
+	done    bool
+}
+
+// beginStep adds a step for the middleware
+// with the entering backends
+func (t *RoutingTrace) beginStep(
+	name string,
+	backends []*Backend,
+) *routingStep {
+	step := &routingStep{
+		record: &store.RoutingStep{
+			Middleware: name,
+			Backends:   backendHosts(backends),
+			Removed:    []*store.RoutingRemoval{},
+		},
+		in:      backends,
+		reasons: make(map[string]string),
+	}
+	t.record.Steps = append(t.record.Steps, step.record)
+	return step
+}
+
+// endStep records the backends removed
+// by the middleware
+func (s *routingStep) end(out []*Backend) {
+	if s.done {
+		return
+	}
+	s.done = true
+
+	passed := make(map[*Backend]bool, len(out))
+	for _, be := range out {
+		passed[be] = true
+	}
+	for _, be := range s.in {
+		if passed[be] {
+			continue
+		}
+		reason, ok := s.reasons[be.ID()]
+		if !ok {
+			reason = "filtered"
+		}
+		s.record.Removed = append(s.record.Removed, &store.RoutingRemoval{
+			Backend: be.Host(),
+			Reason:  reason,
+		})
+	}
+}
+
+// recordStress remembers the stress of the backends
+// at the end of the middleware chain.
+func (t *RoutingTrace) recordStress(
+	ctx context.Context,
+	backends []*Backend,
+) {
+	hints := RoutingHintsFromContext(ctx)
+	for _, be := range backends {
+		t.stress[be.ID()] = be.ProjectedStress(hints)
+	}
+}
+
+// finish records the candidates in order of preference
+// and the selected backend or the error.
+func (t *RoutingTrace) finish(backends []*Backend, err error) {
+	for _, be := range backends {
+		stress, ok := t.stress[be.ID()]
+		if !ok {
+			stress = be.Stress()
+		}
+		t.record.Candidates = append(t.record.Candidates,
+			&store.RoutingCandidate{
+				BackendID: be.ID(),
+				Host:      be.Host(),
+				Stress:    stress,
+			})
+	}
+	if err != nil {
+		t.record.Error = err.Error()
+		return
+	}
+	if len(backends) > 0 {
+		id := backends[0].ID()
+		t.record.BackendID = &id
+	}
+}
+
+type routingTraceContextKey int
+
+// Context keys for the routing trace and
+// the current routing step
+var (
+	routingTraceKey = routingTraceContextKey(1)
+	routingStepKey  = routingTraceContextKey(2)
+)
+
+// ContextWithRoutingTrace creates a context with a routing trace
+func ContextWithRoutingTrace(
+	ctx context.Context, trace *RoutingTrace,
+) context.Context {
+	return context.WithValue(ctx, routingTraceKey, trace)
+}
+
+// RoutingTraceFromContext retrieves the routing trace
+// from the context. If there is no trace, nil is returned.
+func RoutingTraceFromContext(ctx context.Context) *RoutingTrace {
+	trace, ok := ctx.Value(routingTraceKey).(*RoutingTrace)
+	if !ok {
+		return nil
+	}
+	return trace
+}
+
+// IsRoutingSimulation checks if the backend selection
+// in the context is only simulated.
+func IsRoutingSimulation(ctx context.Context) bool {
+	trace := RoutingTraceFromContext(ctx)
+	return trace != nil && trace.simulation
+}
+
+// routingStepFromContext retrieves the current step
+func routingStepFromContext(ctx context.Context) *routingStep {
+	step, ok := ctx.Value(routingStepKey).(*routingStep)
+	if !ok {
+		return nil
+	}
+	return step
+}
+
+// RejectBackend explains why a routing middleware
+// removed a backend. This is a noop when the request
+// is not traced.
+func RejectBackend(ctx context.Context, be *Backend, reason string) {
+	step := routingStepFromContext(ctx)
+	if step == nil {
+		return
+	}
+	step.reasons[be.ID()] = reason
+}
+
+// traceMiddleware wraps a routing middleware, recording
+// the backends entering and leaving the middleware.
+func traceMiddleware(
+	name string,
+	middleware RouterMiddleware,
+) RouterMiddleware {
+	return func(next RouterHandler) RouterHandler {
+		// Record the backends passed on by the middleware
+		handler := middleware(func(
+			ctx context.Context,
+			backends []*Backend,
+			req *bbb.Request,
+		) ([]*Backend, error) {
+			if step := routingStepFromContext(ctx); step != nil {
+				step.end(backends)
+			}
+			return next(ctx, backends, req)
+		})
+
+		return func(
+			ctx context.Context,
+			backends []*Backend,
+			req *bbb.Request,
+		) ([]*Backend, error) {
+			trace := RoutingTraceFromContext(ctx)
+			if trace == nil {
+				return handler(ctx, backends, req)
+			}
+			step := trace.beginStep(name, backends)
+			ctx = context.WithValue(ctx, routingStepKey, step)
+			res, err := handler(ctx, backends, req)
+			if err != nil {
+				step.record.Error = err.Error()
+				return nil, err
+			}
+			// The middleware did not call the next handler
+			step.end(res)
+			return res, nil
+		}
+	}
+}
+
+// middlewareName derives the name of the middleware
+// from the function, e.g. routing.RequiredTags
+func middlewareName(middleware RouterMiddleware) string {
+	fn := runtime.FuncForPC(reflect.ValueOf(middleware).Pointer())
+	if fn == nil {
+		return "unknown"
+	}
+	name := fn.Name()
+	if i := strings.LastIndex(name, "/"); i >= 0 {
+		name = name[i+1:]
+	}
+	return name
+}
+
+// backendHosts lists the hosts of the backends
+func backendHosts(backends []*Backend) []string {
+	hosts := make([]string, 0, len(backends))
+	for _, be := range backends {
+		hosts = append(hosts, be.Host())
+	}
+	return hosts
+}
@@ -0,0 +1,101 @@
+package cluster
+
+import (
+	"context"
+	"testing"
+
+	"github.com/b3scale/b3scale/pkg/bbb"
+	"github.com/b3scale/b3scale/pkg/store"
+)
+
+// rejectB removes the backend with the ID B
+func rejectB(next RouterHandler) RouterHandler {
+	return func(
+		ctx context.Context,
+		backends []*Backend,
+		req *bbb.Request,
+	) ([]*Backend, error) {
+		filtered := []*Backend{}
+		for _, be := range backends {
+			if be.ID() == "B" {
+				RejectBackend(ctx, be, "testing")
+				continue
+			}
+			filtered = append(filtered, be)
+		}
+		return next(ctx, filtered, req)
+	}
+}
+
+// reverse the order of the backends
+func reverse(next RouterHandler) RouterHandler {
+	return func(
+		ctx context.Context,
+		backends []*Backend,
+		req *bbb.Request,
+	) ([]*Backend, error) {
+		res := make([]*Backend, len(backends))
+		for i, be := range backends {
+			res[len(backends)-1-i] = be
+		}
+		return next(ctx, res, req)
+	}
+}
+
+func TestRoutingTrace(t *testing.T) {
+	backends := []*Backend{}
+	for _, id := range []string{"A", "B", "C"} {
+		backends = append(backends, NewBackend(&store.BackendState{
+			ID:            id,
+			MeetingsCount: 1,
+			LoadFactor:    1.0,
+			Backend: &bbb.Backend{
+				Host: "https://" + id,
+			},
+		}))
+	}
+
+	r := NewRouter(nil)
+	r.Use(reverse)
+	r.Use(rejectB)
+
+	trace := NewRoutingTrace()
+	ctx := ContextWithRoutingTrace(context.Background(), trace)
+	req := bbb.CreateRequest(bbb.Params{}, nil)
+	res, err := r.middleware(ctx, backends, req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	trace.finish(res, nil)
+
+	record := trace.Record()
+	if len(record.Steps) != 2 {
+		t.Fatal("unexpected steps:", record.Steps)
+	}
+	step := record.Steps[0]
+	if step.Middleware != "cluster.rejectB" {
+		t.Error("unexpected middleware name:", step.Middleware)
+	}
+	if len(step.Backends) != 3 {
+		t.Error("unexpected backends:", step.Backends)
+	}
+	if len(step.Removed) != 1 ||
+		step.Removed[0].Backend != "https://B" ||
+		step.Removed[0].Reason != "testing" {
+		t.Error("unexpected removed:", step.Removed)
+	}
+	if len(record.Steps[1].Removed) != 0 {
+		t.Error("unexpected removed:", record.Steps[1].Removed)
+	}
+
+	if len(record.Candidates) != 2 {
+		t.Fatal("unexpected candidates:", record.Candidates)
+	}
+	if record.Candidates[0].Host != "https://C" ||
+		record.Candidates[0].Stress != 15 {
+		t.Error("unexpected candidate:", record.Candidates[0])
+	}
+	if record.BackendID == nil || *record.BackendID != "C" {
+		t.Error("unexpected selected backend:", record.BackendID)
+	}
+}
@@ -0,0 +1,120 @@
+package cluster
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/b3scale/b3scale/pkg/config"
+	"github.com/b3scale/b3scale/pkg/store"
+)
+
+// A StressModel estimates the load of a backend
+// from its state. Backends with a lower stress
+// are preferred when creating a meeting.
+type StressModel interface {
+	Stress(state *store.BackendState) float64
+}
+
+// DefaultStressWeights assume that every meeting will
+// eventually have 15 attendees and ignore media streams,
+// meetings and latency.
+func DefaultStressWeights() *store.StressWeights {
+	return &store.StressWeights{
+		MeetingBaseAttendees: ptr(15.0),
+		Attendees:            ptr(1.0),
+		Videos:               ptr(0.0),
+		VoiceParticipants:    ptr(0.0),
+		Meetings:             ptr(0.0),
+		Latency:              ptr(0.0),
+	}
+}
+
+// StressWeightsFromEnv reads the cluster wide stress
+// weights from the environment. Weights not present
+// in the configuration use the default.
+func StressWeightsFromEnv() (*store.StressWeights, error) {
+	weights := DefaultStressWeights()
+	repr, ok := config.GetEnvOpt(config.EnvStressWeights)
+	if !ok {
+		return weights, nil
+	}
+	update := &store.StressWeights{}
+	if err := json.Unmarshal([]byte(repr), update); err != nil {
+		return nil, fmt.Errorf(
+			"invalid %s: %w", config.EnvStressWeights, err)
+	}
+	return weights.Merge(update), nil
+}
+
+// WeightedStress calculates the stress as a weighted
+// sum of attendees, video streams, voice participants,
+// meetings and latency. The weights can be overridden
+// in the backend settings.
+type WeightedStress struct {
+	weights *store.StressWeights
+}
+
+// NewWeightedStress creates a new weighted stress model
+// with cluster wide weights.
+func NewWeightedStress(weights *store.StressWeights) *WeightedStress {
+	return &WeightedStress{
+		weights: DefaultStressWeights().Merge(weights),
+	}
+}
+
+// Stress calculates the stress of the backend
+func (m *WeightedStress) Stress(state *store.BackendState) float64 {
+	w := m.weights.Merge(state.Settings.Stress)
+
+	// Assume that every meeting will eventually have MeetingBaseAttendees
+	// attendees on average, but use the AttendeesCount if bigger.
+	// A rough estimate, but cheap to calculate.
+	attendees := math.Max(
+		float64(state.MeetingsCount)*value(w.MeetingBaseAttendees),
+		float64(state.AttendeesCount))
+	latency := float64(state.Latency) / float64(time.Millisecond)
+
+	stress := attendees*value(w.Attendees) +
+		float64(state.VideoCount)*value(w.Videos) +
+		float64(state.VoiceParticipantCount)*value(w.VoiceParticipants) +
+		float64(state.MeetingsCount)*value(w.Meetings) +
+		latency*value(w.Latency)
+
+	return state.LoadFactor * stress
+}
+
+// The cluster wide stress model used by all backends
+var (
+	stressModel    StressModel = NewWeightedStress(nil)
+	stressModelMtx sync.RWMutex
+)
+
+// SetStressModel configures the stress model used
+// when calculating the stress of a backend.
+func SetStressModel(model StressModel) {
+	stressModelMtx.Lock()
+	defer stressModelMtx.Unlock()
+	stressModel = model
+}
+
+// GetStressModel returns the current stress model
+func GetStressModel() StressModel {
+	stressModelMtx.RLock()
+	defer stressModelMtx.RUnlock()
+	return stressModel
+}
+
+// Helpers for optional weights
+func ptr(v float64) *float64 {
+	return &v
+}
+
+func value(v *float64) float64 {
+	if v == nil {
+		return 0
+	}
+	return *v
+}
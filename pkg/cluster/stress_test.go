@@ -0,0 +1,60 @@
+package cluster
+
+import (
+	"testing"
+	"time"
+
+	"github.com/b3scale/b3scale/pkg/config"
+	"github.com/b3scale/b3scale/pkg/store"
+)
+
+func TestWeightedStress(t *testing.T) {
+	model := NewWeightedStress(&store.StressWeights{
+		MeetingBaseAttendees: ptr(0),
+		Videos:               ptr(2.0),
+		Latency:              ptr(0.5),
+	})
+	state := &store.BackendState{
+		MeetingsCount:  2,
+		AttendeesCount: 10,
+		VideoCount:     5,
+		Latency:        20 * time.Millisecond,
+		LoadFactor:     1.0,
+	}
+	// 10 attendees + 5 videos * 2 + 20ms * 0.5
+	if s := model.Stress(state); s != 30 {
+		t.Error("unexpected stress:", s)
+	}
+
+	// Override weights in backend settings
+	state.Settings.Stress = &store.StressWeights{
+		Meetings: ptr(10.0),
+		Videos:   ptr(0),
+	}
+	// 10 attendees + 2 meetings * 10 + 20ms * 0.5
+	if s := model.Stress(state); s != 40 {
+		t.Error("unexpected stress:", s)
+	}
+}
+
+func TestStressWeightsFromEnv(t *testing.T) {
+	t.Setenv(config.EnvStressWeights, `{"videos": 3, "attendees": 2}`)
+	weights, err := StressWeightsFromEnv()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *weights.Videos != 3 {
+		t.Error("unexpected videos weight:", *weights.Videos)
+	}
+	if *weights.Attendees != 2 {
+		t.Error("unexpected attendees weight:", *weights.Attendees)
+	}
+	if *weights.MeetingBaseAttendees != 15 {
+		t.Error("expected default meeting base attendees")
+	}
+
+	t.Setenv(config.EnvStressWeights, `{"videos": "many"}`)
+	if _, err := StressWeightsFromEnv(); err == nil {
+		t.Error("expected an error")
+	}
+}
@@ -30,6 +30,14 @@ const (
 	EnvReverseProxy = "B3SCALE_REVERSE_PROXY_MODE"
 	EnvLoadFactor   = "B3SCALE_LOAD_FACTOR"
 
+	EnvStressWeights = "B3SCALE_STRESS_WEIGHTS"
+
+	EnvBackendFailoverTimeout = "B3SCALE_BACKEND_FAILOVER_TIMEOUT"
+
+	EnvMeetingSyncLimit = "B3SCALE_MEETING_SYNC_LIMIT"
+
+	EnvGCPolicy = "B3SCALE_GC_POLICY"
+
 	EnvJWTSecret      = "B3SCALE_API_JWT_SECRET"
 	EnvAPIURL         = "B3SCALE_API_URL"
 	EnvAPIAccessToken = "B3SCALE_API_ACCESS_TOKEN"
@@ -61,6 +69,10 @@ const (
 	EnvReverseProxyDefault = "false"
 	EnvLoadFactorDefault   = "1.0"
 
+	EnvBackendFailoverTimeoutDefault = "300" // 5 minutes
+
+	EnvMeetingSyncLimitDefault = "25"
+
 	EnvRecordingsDefaultVisibilityDefault = "published"
 
 	EnvListenHTTPDefault = "127.0.0.1:42353" // :B3S
@@ -369,6 +381,27 @@ func GetHTTPIdleTimeout() time.Duration {
 	return getEnvTimeoutSec(EnvHTTPIdleTimeout, EnvHTTPIdleTimeoutDefault)
 }
 
+// GetBackendFailoverTimeout returns the time after which
+// meetings are detached from a backend without a running
+// node agent. A timeout of 0 disables the failover.
+func GetBackendFailoverTimeout() time.Duration {
+	return getEnvTimeoutSec(
+		EnvBackendFailoverTimeout, EnvBackendFailoverTimeoutDefault)
+}
+
+// GetMeetingSyncLimit returns the maximum number of stale
+// meetings refreshed in the background per run. Syncing stale
+// meetings is disabled when the limit is 0.
+func GetMeetingSyncLimit() int {
+	val := EnvOpt(EnvMeetingSyncLimit, EnvMeetingSyncLimitDefault)
+	limit, err := strconv.Atoi(val)
+	if err != nil || limit < 0 {
+		log.Error().Err(err).Msg("invalid value for " + EnvMeetingSyncLimit)
+		limit, _ = strconv.Atoi(EnvMeetingSyncLimitDefault)
+	}
+	return limit
+}
+
 // GetCmdWorkerPoolSize returns number of workers processing
 // background tasks.
 func GetCmdWorkerPoolSize() int {
@@ -87,3 +87,21 @@ func TestGetRecordingsInboxPath(t *testing.T) {
 		t.Error("expected unpublished, got", p)
 	}
 }
+
+func TestGetMeetingSyncLimit(t *testing.T) {
+	os.Unsetenv(EnvMeetingSyncLimit)
+	if l := GetMeetingSyncLimit(); l != 25 {
+		t.Error("unexpected default:", l)
+	}
+
+	os.Setenv(EnvMeetingSyncLimit, "0")
+	if l := GetMeetingSyncLimit(); l != 0 {
+		t.Error("unexpected limit:", l)
+	}
+
+	os.Setenv(EnvMeetingSyncLimit, "-1")
+	if l := GetMeetingSyncLimit(); l != 25 {
+		t.Error("invalid limit should fall back to default:", l)
+	}
+	os.Unsetenv(EnvMeetingSyncLimit)
+}
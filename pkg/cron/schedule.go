@@ -0,0 +1,183 @@
+// Package cron parses cron like schedules for
+// recurring commands.
+package cron
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrInvalidSchedule is returned when a schedule
+// could not be parsed.
+var ErrInvalidSchedule = errors.New("invalid schedule")
+
+// Descriptors are shortcuts for common schedules
+var descriptors = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+// A Schedule is a parsed cron expression with the
+// fields minute, hour, day of month, month and day of week.
+type Schedule struct {
+	minute uint64
+	hour   uint64
+	dom    uint64
+	month  uint64
+	dow    uint64
+
+	// Cron matches either the day of month or the
+	// day of week, if both are restricted.
+	domAny bool
+	dowAny bool
+}
+
+// field describes the bounds of a cron field
+type field struct {
+	name     string
+	min, max int
+}
+
+var fields = []field{
+	{"minute", 0, 59},
+	{"hour", 0, 23},
+	{"day of month", 1, 31},
+	{"month", 1, 12},
+	{"day of week", 0, 7},
+}
+
+// Parse reads a cron expression like "0 2 * * *"
+// or a descriptor like "@daily".
+func Parse(spec string) (*Schedule, error) {
+	spec = strings.TrimSpace(spec)
+	if d, ok := descriptors[spec]; ok {
+		spec = d
+	}
+	tokens := strings.Fields(spec)
+	if len(tokens) != len(fields) {
+		return nil, fmt.Errorf(
+			"%w: expected %d fields, got %d",
+			ErrInvalidSchedule, len(fields), len(tokens))
+	}
+
+	bits := make([]uint64, len(fields))
+	for i, f := range fields {
+		b, err := parseField(tokens[i], f)
+		if err != nil {
+			return nil, err
+		}
+		bits[i] = b
+	}
+
+	// Sunday is 0 or 7
+	dow := bits[4]
+	if dow&(1<<7) != 0 {
+		dow |= 1
+	}
+
+	return &Schedule{
+		minute: bits[0],
+		hour:   bits[1],
+		dom:    bits[2],
+		month:  bits[3],
+		dow:    dow,
+		domAny: tokens[2] == "*",
+		dowAny: tokens[4] == "*",
+	}, nil
+}
+
+// parseField parses a comma separated list of values,
+// ranges and steps, like "1,5-10,*/15".
+func parseField(token string, f field) (uint64, error) {
+	var bits uint64
+	for _, part := range strings.Split(token, ",") {
+		step := 1
+		if rng, s, ok := strings.Cut(part, "/"); ok {
+			n, err := strconv.Atoi(s)
+			if err != nil || n < 1 {
+				return 0, fmt.Errorf(
+					"%w: bad step in %s: %s", ErrInvalidSchedule, f.name, part)
+			}
+			part, step = rng, n
+		}
+
+		lo, hi := f.min, f.max
+		if part != "*" {
+			from, to, isRange := strings.Cut(part, "-")
+			var err error
+			if lo, err = strconv.Atoi(from); err != nil {
+				return 0, fmt.Errorf(
+					"%w: bad value in %s: %s", ErrInvalidSchedule, f.name, part)
+			}
+			hi = lo
+			if isRange {
+				if hi, err = strconv.Atoi(to); err != nil {
+					return 0, fmt.Errorf(
+						"%w: bad value in %s: %s", ErrInvalidSchedule, f.name, part)
+				}
+			} else if step > 1 {
+				hi = f.max // e.g. 5/15
+			}
+		}
+		if lo < f.min || hi > f.max || lo > hi {
+			return 0, fmt.Errorf(
+				"%w: %s out of range %d-%d: %s",
+				ErrInvalidSchedule, f.name, f.min, f.max, part)
+		}
+		for v := lo; v <= hi; v += step {
+			bits |= 1 << uint(v)
+		}
+	}
+	return bits, nil
+}
+
+// matchDay checks the day of month and day of week
+func (s *Schedule) matchDay(t time.Time) bool {
+	dom := s.dom&(1<<uint(t.Day())) != 0
+	dow := s.dow&(1<<uint(t.Weekday())) != 0
+	if s.domAny || s.dowAny {
+		return dom && dow
+	}
+	return dom || dow
+}
+
+// Next returns the first time after t matching the
+// schedule. The schedule is evaluated in the location of t.
+// A zero time is returned if there is no match within
+// the next five years.
+func (s *Schedule) Next(t time.Time) time.Time {
+	loc := t.Location()
+	t = time.Date(
+		t.Year(), t.Month(), t.Day(),
+		t.Hour(), t.Minute(), 0, 0, loc).Add(time.Minute)
+
+	limit := t.Year() + 5
+	for t.Year() <= limit {
+		if s.month&(1<<uint(t.Month())) == 0 {
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, loc)
+			continue
+		}
+		if !s.matchDay(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, loc)
+			continue
+		}
+		if s.hour&(1<<uint(t.Hour())) == 0 {
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, loc)
+			continue
+		}
+		if s.minute&(1<<uint(t.Minute())) == 0 {
+			t = t.Add(time.Minute)
+			continue
+		}
+		return t
+	}
+	return time.Time{}
+}
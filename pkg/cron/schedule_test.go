@@ -0,0 +1,68 @@
+package cron
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestParseInvalid(t *testing.T) {
+	specs := []string{
+		"",
+		"* * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * * 13 *",
+		"*/0 * * * *",
+		"5-1 * * * *",
+		"a * * * *",
+	}
+	for _, spec := range specs {
+		if _, err := Parse(spec); !errors.Is(err, ErrInvalidSchedule) {
+			t.Error("expected invalid schedule for:", spec, err)
+		}
+	}
+}
+
+func TestScheduleNext(t *testing.T) {
+	// Saturday
+	now := time.Date(2026, 10, 17, 13, 37, 42, 0, time.UTC)
+	tests := []struct {
+		spec string
+		next time.Time
+	}{
+		{"* * * * *", time.Date(2026, 10, 17, 13, 38, 0, 0, time.UTC)},
+		{"*/15 * * * *", time.Date(2026, 10, 17, 13, 45, 0, 0, time.UTC)},
+		{"0 2 * * *", time.Date(2026, 10, 18, 2, 0, 0, 0, time.UTC)},
+		{"@daily", time.Date(2026, 10, 18, 0, 0, 0, 0, time.UTC)},
+		{"@hourly", time.Date(2026, 10, 17, 14, 0, 0, 0, time.UTC)},
+		{"30 3 * * 1", time.Date(2026, 10, 19, 3, 30, 0, 0, time.UTC)},
+		{"0 0 * * 7", time.Date(2026, 10, 18, 0, 0, 0, 0, time.UTC)},
+		{"0 0 1 * *", time.Date(2026, 11, 1, 0, 0, 0, 0, time.UTC)},
+		{"0 12 1,15 1-3 *", time.Date(2027, 1, 1, 12, 0, 0, 0, time.UTC)},
+		{"0 0 31 2 *", time.Time{}},
+	}
+	for _, tt := range tests {
+		s, err := Parse(tt.spec)
+		if err != nil {
+			t.Fatal(tt.spec, err)
+		}
+		if next := s.Next(now); !next.Equal(tt.next) {
+			t.Error(tt.spec, "unexpected next:", next, "expected:", tt.next)
+		}
+	}
+}
+
+func TestScheduleNextDayOfMonthOrWeek(t *testing.T) {
+	// On the 1st or on mondays
+	s, err := Parse("0 0 1 * 1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Date(2026, 10, 17, 0, 0, 0, 0, time.UTC)
+	next := s.Next(now)
+	if !next.Equal(time.Date(2026, 10, 19, 0, 0, 0, 0, time.UTC)) {
+		t.Error("unexpected next:", next)
+	}
+}
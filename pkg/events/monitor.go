@@ -91,6 +91,10 @@ func decodeEvent(msg *redis.Message) bbb.Event {
 		return safeDecode(decodeUserJoinedMeetingEvent, m)
 	case "UserLeftMeetingEvtMsg":
 		return safeDecode(decodeUserLeftMeetingEvent, m)
+	case "UserBroadcastCamStartedEvtMsg":
+		return safeDecode(decodeUserBroadcastCamStartedEvent, m)
+	case "UserBroadcastCamStoppedEvtMsg":
+		return safeDecode(decodeUserBroadcastCamStoppedEvent, m)
 	}
 
 	return nil
@@ -151,3 +155,21 @@ func decodeUserLeftMeetingEvent(m *Message) bbb.Event {
 		InternalUserID:    header["userId"].(string),
 	}
 }
+
+func decodeUserBroadcastCamStartedEvent(m *Message) bbb.Event {
+	header := m.Core.Header
+	return &bbb.UserBroadcastCamStartedEvent{
+		InternalMeetingID: header["meetingId"].(string),
+		InternalUserID:    header["userId"].(string),
+		Stream:            m.Core.Body["stream"].(string),
+	}
+}
+
+func decodeUserBroadcastCamStoppedEvent(m *Message) bbb.Event {
+	header := m.Core.Header
+	return &bbb.UserBroadcastCamStoppedEvent{
+		InternalMeetingID: header["meetingId"].(string),
+		InternalUserID:    header["userId"].(string),
+		Stream:            m.Core.Body["stream"].(string),
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"net/http"
 
+	"github.com/b3scale/b3scale/pkg/cluster"
 	"github.com/b3scale/b3scale/pkg/http/auth"
 	"github.com/b3scale/b3scale/pkg/store"
 
@@ -73,6 +74,10 @@ func apiAgentHeartbeatCreate(
 	if err != nil {
 		return err
 	}
+	if err := cluster.PublishEvent(
+		ctx, tx, cluster.NewHeartbeatEvent(backend, heartbeat)); err != nil {
+		return err
+	}
 
 	if err := tx.Commit(ctx); err != nil {
 		return err
@@ -18,6 +18,7 @@ import (
 	"github.com/labstack/echo/v4"
 	"github.com/rs/zerolog/log"
 
+	"github.com/b3scale/b3scale/pkg/cluster"
 	"github.com/b3scale/b3scale/pkg/config"
 	"github.com/b3scale/b3scale/pkg/http/auth"
 	"github.com/b3scale/b3scale/pkg/store"
@@ -115,8 +116,9 @@ func ContextMiddleware(next echo.HandlerFunc) echo.HandlerFunc {
 }
 
 // Init sets up a group with authentication
-// for a restful management interface.
-func Init(e *echo.Echo) error {
+// for a restful management interface. The router
+// is used for simulating the backend selection.
+func Init(e *echo.Echo, router *cluster.Router) error {
 	// Get Configuration
 	apiSecret := config.MustEnv(config.EnvJWTSecret)
 
@@ -135,16 +137,48 @@ func Init(e *echo.Echo) error {
 
 	// API resources
 	ResourceFrontends.Mount(v1, "/frontends")
+	v1.POST("/frontends/:id/secret", Endpoint(RequireScope(
+		auth.ScopeAdmin,
+		auth.ScopeUser,
+	)(apiFrontendSecretRotate)))
+	v1.GET("/frontends/:id/usage", Endpoint(RequireScope(
+		auth.ScopeAdmin,
+		auth.ScopeUser,
+	)(apiFrontendUsageShow)))
+	v1.GET("/frontends/:id/meetings", Endpoint(RequireScope(
+		auth.ScopeAdmin,
+		auth.ScopeUser,
+	)(apiFrontendMeetingsList)))
+	v1.DELETE("/frontends/:id/meetings/:meeting_id", Endpoint(RequireScope(
+		auth.ScopeAdmin,
+		auth.ScopeUser,
+	)(apiFrontendMeetingEnd)))
 	ResourceBackends.Mount(v1, "/backends")
 	ResourceMeetings.Mount(v1, "/meetings")
+	ResourceMeetingsHistory.Mount(v1, "/meetings-history")
+	v1.GET("/meetings/:id/routing", Endpoint(RequireScope(
+		auth.ScopeAdmin,
+	)(apiMeetingRoutingShow)))
+	ResourceCommandSchedules.Mount(v1, "/commands/schedules")
 	ResourceCommands.Mount(v1, "/commands")
+	v1.POST("/commands/:id/requeue", Endpoint(RequireScope(
+		auth.ScopeAdmin,
+	)(apiCommandRequeue)))
+	ResourceMaintenanceWindows.Mount(v1, "/maintenance-windows")
 	ResourceRecordingsVisibility.Mount(v1, "/recordings-visibility")
 	ResourceRecordingsImport.Mount(v1, "/recordings-import")
 	ResourceRecordings.Mount(v1, "/recordings")
+	ResourceUsage.Mount(v1, "/usage")
+	ResourceWebhooks.Mount(v1, "/webhooks")
+	v1.GET("/events/stream", Endpoint(RequireScope(
+		auth.ScopeAdmin,
+		auth.ScopeUser,
+	)(apiEventsStream)))
 	ResourceAgentRPC.Mount(v1, "/agent/rpc")
 	ResourceAgentBackend.Mount(v1, "/agent/backend")
 	ResourceAgentHeartbeat.Mount(v1, "/agent/heartbeat")
 	ResourceCtlMigrate.Mount(v1, "/ctrl/migrate")
+	NewResourceRoutingSimulate(router).Mount(v1, "/routing/simulate")
 
 	// Protected Recordings
 	protected := e.Group("/api/v1/protected")
@@ -170,6 +204,7 @@ type StatusResponse struct {
 	AccountRef string         `json:"account_ref" doc:"The currently authenticated subject."`
 	IsAdmin    bool           `json:"is_admin" doc:"True if the subject has admin privileges."`
 	Database   *schema.Status `json:"database" doc:"Status of the database" api:"SchemaStatus"`
+	Leader     *string        `json:"leader,omitempty" doc:"The instance running the periodic background tasks. Only visible to admins."`
 }
 
 // apiStatusShow will respond with the api version and b3scale
@@ -185,9 +220,28 @@ func apiStatusShow(ctx context.Context, api *API) error {
 		IsAdmin:    api.HasScope(auth.ScopeAdmin),
 		Database:   m.Status(ctx),
 	}
+
+	if status.IsAdmin {
+		leader, err := apiClusterLeader(ctx, api)
+		if err != nil {
+			return err
+		}
+		status.Leader = &leader
+	}
+
 	return api.JSON(http.StatusOK, status)
 }
 
+// apiClusterLeader retrieves the current cluster leader
+func apiClusterLeader(ctx context.Context, api *API) (string, error) {
+	tx, err := api.Conn.Begin(ctx)
+	if err != nil {
+		return "", err
+	}
+	defer tx.Rollback(ctx) //nolint
+	return store.GetClusterLeader(ctx, tx)
+}
+
 // RequireScope creates a middleware to ensure the presence of
 // at least one required scope.
 func RequireScope(scopes ...string) ResourceMiddleware {
@@ -193,19 +193,20 @@ func apiBackendDestroy(
 		return echo.ErrNotFound
 	}
 
-	if force {
-		// force removal of backend. this is a hard delete
-		// without decommissioning.
-		if err := backend.Delete(ctx, tx); err != nil {
-			return err
+	if err := cluster.EmitBackendStateChange(ctx, tx, backend.ID, func() error {
+		if force {
+			// force removal of backend. this is a hard delete
+			// without decommissioning.
+			return backend.Delete(ctx, tx)
 		}
-		backend.AdminState = "destroyed"
-	} else {
 		// Request backend decommissioning.
-		backend.AdminState = "decommissioned"
-		if err := backend.Save(ctx, tx); err != nil {
-			return err
-		}
+		backend.AdminState = cluster.BackendStateDecommissioned
+		return backend.Save(ctx, tx)
+	}); err != nil {
+		return err
+	}
+	if force {
+		backend.AdminState = cluster.BackendStateDestroyed
 	}
 
 	if err := tx.Commit(ctx); err != nil {
@@ -253,6 +254,7 @@ func apiBackendUpdate(
 	}
 
 	// Apply update for well known fields
+	prevAdminState := backend.AdminState
 	backend.Backend = update.Backend
 	backend.Settings = update.Settings
 	backend.AdminState = update.AdminState
@@ -266,6 +268,11 @@ func apiBackendUpdate(
 	if err := backend.Save(ctx, tx); err != nil {
 		return err
 	}
+	if err := cluster.EmitBackendStateChanged(
+		ctx, tx, backend, backend.NodeState, prevAdminState,
+	); err != nil {
+		return err
+	}
 
 	// Enqueue node refresh command
 	cmd := cluster.UpdateNodeState(&cluster.UpdateNodeStateRequest{
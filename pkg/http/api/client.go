@@ -5,6 +5,7 @@ import (
 	"net/url"
 
 	"github.com/b3scale/b3scale/pkg/bbb"
+	"github.com/b3scale/b3scale/pkg/cluster"
 	"github.com/b3scale/b3scale/pkg/store"
 	"github.com/b3scale/b3scale/pkg/store/schema"
 )
@@ -30,6 +31,18 @@ type FrontendResourceClient interface {
 	FrontendDelete(
 		ctx context.Context, frontend *store.FrontendState,
 	) (*store.FrontendState, error)
+	FrontendSecretRotate(
+		ctx context.Context, frontend *store.FrontendState,
+	) (*store.FrontendState, error)
+	FrontendUsage(
+		ctx context.Context, frontend *store.FrontendState,
+	) (*store.FrontendUsage, error)
+	FrontendMeetingsList(
+		ctx context.Context, frontend *store.FrontendState,
+	) ([]*store.MeetingState, error)
+	FrontendMeetingEnd(
+		ctx context.Context, frontend *store.FrontendState, meetingID string,
+	) (*store.MeetingState, error)
 }
 
 // BackendResourceClient defines methods for
@@ -87,6 +100,14 @@ type MeetingResourceClient interface {
 		ctx context.Context,
 		id string,
 	) (*store.MeetingState, error)
+	MeetingRoutingRetrieve(
+		ctx context.Context,
+		id string,
+	) (*store.RoutingRecord, error)
+	MeetingsHistory(
+		ctx context.Context,
+		query ...url.Values,
+	) (*MeetingsHistoryResponse, error)
 }
 
 // RecordingsResourceClient defines recording related methods.
@@ -117,6 +138,10 @@ type CommandResourceClient interface {
 		ctx context.Context,
 		backendID string,
 	) (*store.Command, error)
+	MeetingsReconcile(
+		ctx context.Context,
+		req *cluster.ReconcileMeetingsRequest,
+	) (*store.Command, error)
 
 	CommandCreate(
 		ctx context.Context,
@@ -126,6 +151,32 @@ type CommandResourceClient interface {
 		ctx context.Context,
 		id string,
 	) (*store.Command, error)
+	CommandsList(
+		ctx context.Context,
+		query ...url.Values,
+	) ([]*store.Command, error)
+	CommandRequeue(
+		ctx context.Context,
+		id string,
+	) (*store.Command, error)
+	CommandCancel(
+		ctx context.Context,
+		id string,
+	) (*store.Command, error)
+
+	// Schedules
+	CommandSchedulesList(
+		ctx context.Context,
+		query ...url.Values,
+	) ([]*store.CommandSchedule, error)
+	CommandScheduleCreate(
+		ctx context.Context,
+		schedule *store.CommandSchedule,
+	) (*store.CommandSchedule, error)
+	CommandScheduleDelete(
+		ctx context.Context,
+		id string,
+	) (*store.CommandSchedule, error)
 
 	// Control commands
 	CtrlMigrate(
@@ -133,6 +184,62 @@ type CommandResourceClient interface {
 	) (*schema.Status, error)
 }
 
+// RoutingResourceClient defines methods for
+// inspecting the backend selection
+type RoutingResourceClient interface {
+	RoutingSimulate(
+		ctx context.Context,
+		sim *RoutingSimulation,
+	) (*store.RoutingRecord, error)
+}
+
+// MaintenanceWindowResourceClient defines methods for
+// scheduling maintenance of backends
+type MaintenanceWindowResourceClient interface {
+	MaintenanceWindowsList(
+		ctx context.Context,
+		query ...url.Values,
+	) ([]*store.MaintenanceWindow, error)
+	MaintenanceWindowCreate(
+		ctx context.Context,
+		window *store.MaintenanceWindow,
+	) (*store.MaintenanceWindow, error)
+	MaintenanceWindowCancel(
+		ctx context.Context,
+		id string,
+	) (*store.MaintenanceWindow, error)
+}
+
+// WebhookResourceClient defines methods for
+// subscribing to cluster events
+type WebhookResourceClient interface {
+	WebhooksList(
+		ctx context.Context,
+		query ...url.Values,
+	) ([]*store.Webhook, error)
+	WebhookCreate(
+		ctx context.Context,
+		webhook *store.Webhook,
+	) (*store.Webhook, error)
+	WebhookUpdate(
+		ctx context.Context,
+		webhook *store.Webhook,
+	) (*store.Webhook, error)
+	WebhookDelete(
+		ctx context.Context,
+		id string,
+	) (*store.Webhook, error)
+}
+
+// UsageResourceClient defines methods for exporting
+// the usage ledger.
+type UsageResourceClient interface {
+	UsageList(
+		ctx context.Context,
+		query ...url.Values,
+	) ([]*store.UsageRecord, error)
+}
+
 // AgentResourceClient defines node agent specific
 // methods.
 type AgentResourceClient interface {
@@ -157,5 +264,9 @@ type Client interface {
 	MeetingResourceClient
 	RecordingsResourceClient
 	CommandResourceClient
+	RoutingResourceClient
+	MaintenanceWindowResourceClient
+	WebhookResourceClient
+	UsageResourceClient
 	AgentResourceClient
 }
@@ -3,6 +3,7 @@ package client
 import (
 	"context"
 	"encoding/json"
+	"net/url"
 
 	"github.com/b3scale/b3scale/pkg/cluster"
 	"github.com/b3scale/b3scale/pkg/store"
@@ -53,6 +54,114 @@ func (c *Client) CommandRetrieve(
 	return cmd, nil
 }
 
+// CommandsList retrieves the commands in the queue.
+// The query can filter by state and action.
+func (c *Client) CommandsList(
+	ctx context.Context,
+	query ...url.Values,
+) ([]*store.Command, error) {
+	res, err := c.Request(ctx, Fetch(Commands(), query...))
+	if err != nil {
+		return nil, err
+	}
+	cmds := []*store.Command{}
+	if err := res.JSON(&cmds); err != nil {
+		return nil, err
+	}
+	return cmds, nil
+}
+
+// CommandRequeue puts a failed or dead command
+// back into the queue.
+func (c *Client) CommandRequeue(
+	ctx context.Context,
+	id string,
+) (*store.Command, error) {
+	res, err := c.Request(ctx, Create(Commands(id)+"/requeue", nil))
+	if err != nil {
+		return nil, err
+	}
+	cmd := &store.Command{}
+	if err := res.JSON(cmd); err != nil {
+		return nil, err
+	}
+	return cmd, nil
+}
+
+// CommandCancel cancels a pending command.
+func (c *Client) CommandCancel(
+	ctx context.Context,
+	id string,
+) (*store.Command, error) {
+	res, err := c.Request(ctx, Destroy(Commands(id)))
+	if err != nil {
+		return nil, err
+	}
+	cmd := &store.Command{}
+	if err := res.JSON(cmd); err != nil {
+		return nil, err
+	}
+	return cmd, nil
+}
+
+// CommandSchedules creates a command schedules resource
+func CommandSchedules(id ...string) string {
+	return Resource("commands/schedules", id)
+}
+
+// CommandSchedulesList retrieves all command schedules
+func (c *Client) CommandSchedulesList(
+	ctx context.Context,
+	query ...url.Values,
+) ([]*store.CommandSchedule, error) {
+	res, err := c.Request(ctx, Fetch(CommandSchedules(), query...))
+	if err != nil {
+		return nil, err
+	}
+	schedules := []*store.CommandSchedule{}
+	if err := res.JSON(&schedules); err != nil {
+		return nil, err
+	}
+	return schedules, nil
+}
+
+// CommandScheduleCreate adds a schedule for queuing
+// a command periodically.
+func (c *Client) CommandScheduleCreate(
+	ctx context.Context,
+	schedule *store.CommandSchedule,
+) (*store.CommandSchedule, error) {
+	payload, err := json.Marshal(schedule)
+	if err != nil {
+		return nil, err
+	}
+	res, err := c.Request(ctx, Create(CommandSchedules(), payload))
+	if err != nil {
+		return nil, err
+	}
+	schedule = &store.CommandSchedule{}
+	if err := res.JSON(schedule); err != nil {
+		return nil, err
+	}
+	return schedule, nil
+}
+
+// CommandScheduleDelete removes a command schedule
+func (c *Client) CommandScheduleDelete(
+	ctx context.Context,
+	id string,
+) (*store.CommandSchedule, error) {
+	res, err := c.Request(ctx, Destroy(CommandSchedules(id)))
+	if err != nil {
+		return nil, err
+	}
+	schedule := &store.CommandSchedule{}
+	if err := res.JSON(schedule); err != nil {
+		return nil, err
+	}
+	return schedule, nil
+}
+
 // BackendMeetingsEnd ends all meetings on a given backend
 func (c *Client) BackendMeetingsEnd(
 	ctx context.Context,
@@ -64,6 +173,15 @@ func (c *Client) BackendMeetingsEnd(
 	return c.CommandCreate(ctx, cmd)
 }
 
+// MeetingsReconcile compares the meetings in the store
+// with the meetings running on the backends
+func (c *Client) MeetingsReconcile(
+	ctx context.Context,
+	req *cluster.ReconcileMeetingsRequest,
+) (*store.Command, error) {
+	return c.CommandCreate(ctx, cluster.ReconcileMeetings(req))
+}
+
 // CtrlMigrate applies all pending migrations
 func (c *Client) CtrlMigrate(ctx context.Context) (*schema.Status, error) {
 	res, err := c.Request(ctx, Create(Resource("ctrl/migrate", nil), nil))
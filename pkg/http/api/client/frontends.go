@@ -111,3 +111,70 @@ func (c *Client) FrontendDelete(
 	}
 	return frontend, nil
 }
+
+// FrontendSecretRotate replaces the secret of the
+// frontend with a new random secret.
+func (c *Client) FrontendSecretRotate(
+	ctx context.Context,
+	frontend *store.FrontendState,
+) (*store.FrontendState, error) {
+	res, err := c.Request(ctx, Create(Frontends(frontend.ID)+"/secret", nil))
+	if err != nil {
+		return nil, err
+	}
+	frontend = &store.FrontendState{}
+	if err := res.JSON(frontend); err != nil {
+		return nil, err
+	}
+	return frontend, nil
+}
+
+// FrontendUsage retrieves the current usage of the frontend
+func (c *Client) FrontendUsage(
+	ctx context.Context,
+	frontend *store.FrontendState,
+) (*store.FrontendUsage, error) {
+	res, err := c.Request(ctx, Fetch(Frontends(frontend.ID)+"/usage"))
+	if err != nil {
+		return nil, err
+	}
+	usage := &store.FrontendUsage{}
+	if err := res.JSON(usage); err != nil {
+		return nil, err
+	}
+	return usage, nil
+}
+
+// FrontendMeetingsList retrieves the meetings of the frontend
+func (c *Client) FrontendMeetingsList(
+	ctx context.Context,
+	frontend *store.FrontendState,
+) ([]*store.MeetingState, error) {
+	res, err := c.Request(ctx, Fetch(Frontends(frontend.ID)+"/meetings"))
+	if err != nil {
+		return nil, err
+	}
+	meetings := []*store.MeetingState{}
+	if err := res.JSON(&meetings); err != nil {
+		return nil, err
+	}
+	return meetings, nil
+}
+
+// FrontendMeetingEnd ends a meeting of the frontend
+func (c *Client) FrontendMeetingEnd(
+	ctx context.Context,
+	frontend *store.FrontendState,
+	meetingID string,
+) (*store.MeetingState, error) {
+	res, err := c.Request(ctx, Destroy(
+		Frontends(frontend.ID)+"/meetings/"+url.PathEscape(meetingID)))
+	if err != nil {
+		return nil, err
+	}
+	meeting := &store.MeetingState{}
+	if err := res.JSON(meeting); err != nil {
+		return nil, err
+	}
+	return meeting, nil
+}
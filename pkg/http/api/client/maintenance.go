@@ -0,0 +1,67 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"net/url"
+
+	"github.com/b3scale/b3scale/pkg/store"
+)
+
+// MaintenanceWindows creates a maintenance windows resource
+func MaintenanceWindows(id ...string) string {
+	return Resource("maintenance-windows", id)
+}
+
+// MaintenanceWindowsList retrieves the maintenance windows.
+// The query can filter by backend_id and state.
+func (c *Client) MaintenanceWindowsList(
+	ctx context.Context,
+	query ...url.Values,
+) ([]*store.MaintenanceWindow, error) {
+	res, err := c.Request(ctx, Fetch(MaintenanceWindows(), query...))
+	if err != nil {
+		return nil, err
+	}
+	windows := []*store.MaintenanceWindow{}
+	if err := res.JSON(&windows); err != nil {
+		return nil, err
+	}
+	return windows, nil
+}
+
+// MaintenanceWindowCreate schedules a maintenance window
+func (c *Client) MaintenanceWindowCreate(
+	ctx context.Context,
+	window *store.MaintenanceWindow,
+) (*store.MaintenanceWindow, error) {
+	payload, err := json.Marshal(window)
+	if err != nil {
+		return nil, err
+	}
+	res, err := c.Request(ctx, Create(MaintenanceWindows(), payload))
+	if err != nil {
+		return nil, err
+	}
+	window = &store.MaintenanceWindow{}
+	if err := res.JSON(window); err != nil {
+		return nil, err
+	}
+	return window, nil
+}
+
+// MaintenanceWindowCancel cancels a maintenance window
+func (c *Client) MaintenanceWindowCancel(
+	ctx context.Context,
+	id string,
+) (*store.MaintenanceWindow, error) {
+	res, err := c.Request(ctx, Destroy(MaintenanceWindows(id)))
+	if err != nil {
+		return nil, err
+	}
+	window := &store.MaintenanceWindow{}
+	if err := res.JSON(window); err != nil {
+		return nil, err
+	}
+	return window, nil
+}
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"net/url"
 
+	"github.com/b3scale/b3scale/pkg/http/api"
 	"github.com/b3scale/b3scale/pkg/store"
 )
 
@@ -104,3 +105,38 @@ func (c *Client) MeetingDelete(
 	}
 	return meeting, nil
 }
+
+// MeetingRoutingRetrieve will fetch the routing
+// record of a meeting
+func (c *Client) MeetingRoutingRetrieve(
+	ctx context.Context,
+	id string,
+) (*store.RoutingRecord, error) {
+	res, err := c.Request(ctx, Fetch(Meetings(id)+"/routing"))
+	if err != nil {
+		return nil, err
+	}
+	record := &store.RoutingRecord{}
+	if err := res.JSON(record); err != nil {
+		return nil, err
+	}
+	return record, nil
+}
+
+// MeetingsHistory retrieves the archived meetings and
+// the aggregates. The query may contain filters and
+// the time range.
+func (c *Client) MeetingsHistory(
+	ctx context.Context,
+	query ...url.Values,
+) (*api.MeetingsHistoryResponse, error) {
+	res, err := c.Request(ctx, Fetch("meetings-history", query...))
+	if err != nil {
+		return nil, err
+	}
+	history := &api.MeetingsHistoryResponse{}
+	if err := res.JSON(history); err != nil {
+		return nil, err
+	}
+	return history, nil
+}
@@ -0,0 +1,30 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/b3scale/b3scale/pkg/http/api"
+	"github.com/b3scale/b3scale/pkg/store"
+)
+
+// RoutingSimulate selects a backend for a create request
+// without creating a meeting and retrieves the routing record.
+func (c *Client) RoutingSimulate(
+	ctx context.Context,
+	sim *api.RoutingSimulation,
+) (*store.RoutingRecord, error) {
+	payload, err := json.Marshal(sim)
+	if err != nil {
+		return nil, err
+	}
+	res, err := c.Request(ctx, Create("routing/simulate", payload))
+	if err != nil {
+		return nil, err
+	}
+	record := &store.RoutingRecord{}
+	if err := res.JSON(record); err != nil {
+		return nil, err
+	}
+	return record, nil
+}
@@ -0,0 +1,30 @@
+package client
+
+import (
+	"context"
+	"net/url"
+
+	"github.com/b3scale/b3scale/pkg/store"
+)
+
+// Usage creates the usage resource URL
+func Usage() string {
+	return Resource("usage", nil)
+}
+
+// UsageList retrieves the usage ledger. The query may
+// contain a frontend and a date range.
+func (c *Client) UsageList(
+	ctx context.Context,
+	query ...url.Values,
+) ([]*store.UsageRecord, error) {
+	res, err := c.Request(ctx, Fetch(Usage(), query...))
+	if err != nil {
+		return nil, err
+	}
+	records := []*store.UsageRecord{}
+	if err := res.JSON(&records); err != nil {
+		return nil, err
+	}
+	return records, nil
+}
@@ -0,0 +1,87 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"net/url"
+
+	"github.com/b3scale/b3scale/pkg/store"
+)
+
+// Webhooks creates a webhooks resource
+func Webhooks(id ...string) string {
+	return Resource("webhooks", id)
+}
+
+// WebhooksList retrieves the webhooks.
+// The query can filter by frontend_id.
+func (c *Client) WebhooksList(
+	ctx context.Context,
+	query ...url.Values,
+) ([]*store.Webhook, error) {
+	res, err := c.Request(ctx, Fetch(Webhooks(), query...))
+	if err != nil {
+		return nil, err
+	}
+	webhooks := []*store.Webhook{}
+	if err := res.JSON(&webhooks); err != nil {
+		return nil, err
+	}
+	return webhooks, nil
+}
+
+// WebhookCreate subscribes to cluster events
+func (c *Client) WebhookCreate(
+	ctx context.Context,
+	webhook *store.Webhook,
+) (*store.Webhook, error) {
+	payload, err := json.Marshal(webhook)
+	if err != nil {
+		return nil, err
+	}
+	res, err := c.Request(ctx, Create(Webhooks(), payload))
+	if err != nil {
+		return nil, err
+	}
+	webhook = &store.Webhook{}
+	if err := res.JSON(webhook); err != nil {
+		return nil, err
+	}
+	return webhook, nil
+}
+
+// WebhookUpdate PATCHes an existing webhook
+func (c *Client) WebhookUpdate(
+	ctx context.Context,
+	webhook *store.Webhook,
+) (*store.Webhook, error) {
+	payload, err := json.Marshal(webhook)
+	if err != nil {
+		return nil, err
+	}
+	res, err := c.Request(ctx, Update(Webhooks(webhook.ID), payload))
+	if err != nil {
+		return nil, err
+	}
+	webhook = &store.Webhook{}
+	if err := res.JSON(webhook); err != nil {
+		return nil, err
+	}
+	return webhook, nil
+}
+
+// WebhookDelete removes a webhook
+func (c *Client) WebhookDelete(
+	ctx context.Context,
+	id string,
+) (*store.Webhook, error) {
+	res, err := c.Request(ctx, Destroy(Webhooks(id)))
+	if err != nil {
+		return nil, err
+	}
+	webhook := &store.Webhook{}
+	if err := res.JSON(webhook); err != nil {
+		return nil, err
+	}
+	return webhook, nil
+}
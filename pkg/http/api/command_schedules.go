@@ -0,0 +1,164 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/b3scale/b3scale/pkg/http/auth"
+	"github.com/b3scale/b3scale/pkg/store"
+)
+
+// ResourceCommandSchedules is a restful group
+// for queuing commands periodically
+var ResourceCommandSchedules = &Resource{
+	List: RequireScope(
+		auth.ScopeAdmin,
+	)(apiCommandSchedulesList),
+
+	Show: RequireScope(
+		auth.ScopeAdmin,
+	)(apiCommandScheduleShow),
+
+	Create: RequireScope(
+		auth.ScopeAdmin,
+	)(apiCommandScheduleCreate),
+
+	Destroy: RequireScope(
+		auth.ScopeAdmin,
+	)(apiCommandScheduleDestroy),
+}
+
+// apiCommandSchedulesList returns the command
+// schedules, optionally filtered by action.
+func apiCommandSchedulesList(
+	ctx context.Context,
+	api *API,
+) error {
+	tx, err := api.Conn.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx) //nolint
+
+	q := store.Q()
+	action := strings.TrimSpace(api.QueryParam("action"))
+	if action != "" {
+		q = q.Where("command_schedules.action = ?", action)
+	}
+
+	schedules, err := store.GetCommandSchedules(ctx, tx, q)
+	if err != nil {
+		return err
+	}
+	return api.JSON(http.StatusOK, schedules)
+}
+
+// apiCommandScheduleShow returns a single
+// command schedule by ID
+func apiCommandScheduleShow(
+	ctx context.Context,
+	api *API,
+) error {
+	tx, err := api.Conn.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx) //nolint
+
+	id := api.Param("id")
+	schedule, err := store.GetCommandSchedule(ctx, tx, store.Q().
+		Where("command_schedules.id = ?", id))
+	if err != nil {
+		return err
+	}
+	if schedule == nil {
+		return echo.ErrNotFound
+	}
+	return api.JSON(http.StatusOK, schedule)
+}
+
+// apiCommandScheduleCreate adds a new schedule
+// for a well known command
+func apiCommandScheduleCreate(
+	ctx context.Context,
+	api *API,
+) error {
+	tx, err := api.Conn.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx) //nolint
+
+	req := &store.CommandSchedule{}
+	if err := api.Bind(req); err != nil {
+		return err
+	}
+	schedule := &store.CommandSchedule{
+		Action:   req.Action,
+		Params:   req.Params,
+		Schedule: req.Schedule,
+		Comment:  req.Comment,
+	}
+	if err := schedule.Validate(); err != nil {
+		return err
+	}
+	if err := validateCommand(schedule.Action); err != nil {
+		return err
+	}
+	if err := validateCommandParams(
+		ctx, tx, schedule.Action, schedule.Params); err != nil {
+		return err
+	}
+	if err := schedule.Advance(time.Now()); err != nil {
+		return err
+	}
+	if schedule.NextRunAt.IsZero() {
+		return store.ValidationError{
+			"schedule": []string{"the schedule never matches"},
+		}
+	}
+
+	if err := schedule.Save(ctx, tx); err != nil {
+		return err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return err
+	}
+
+	return api.JSON(http.StatusOK, schedule)
+}
+
+// apiCommandScheduleDestroy removes a command schedule.
+// Commands already queued by the schedule are not canceled.
+func apiCommandScheduleDestroy(
+	ctx context.Context,
+	api *API,
+) error {
+	tx, err := api.Conn.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx) //nolint
+
+	id := api.Param("id")
+	schedule, err := store.GetCommandSchedule(ctx, tx, store.Q().
+		Where("command_schedules.id = ?", id))
+	if err != nil {
+		return err
+	}
+	if schedule == nil {
+		return echo.ErrNotFound
+	}
+	if err := schedule.Delete(ctx, tx); err != nil {
+		return err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return err
+	}
+
+	return api.JSON(http.StatusOK, schedule)
+}
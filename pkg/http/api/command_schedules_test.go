@@ -0,0 +1,58 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/b3scale/b3scale/pkg/http/auth"
+)
+
+func TestCommandScheduleCreateDestroy(t *testing.T) {
+	api, res := NewTestRequest().
+		Authorize("admin42", auth.ScopeAdmin).
+		JSON(map[string]interface{}{
+			"action":   "collect_garbage",
+			"schedule": "@weekly",
+		}).
+		Context()
+	defer api.Release()
+
+	if err := api.Handle(ResourceCommandSchedules.Create); err != nil {
+		t.Fatal(err)
+	}
+	if err := res.StatusOK(); err != nil {
+		t.Fatal(err)
+	}
+	schedule := res.JSON()
+	if schedule["next_run_at"] == nil {
+		t.Error("expected next_run_at")
+	}
+
+	api, res = NewTestRequest().
+		Authorize("admin42", auth.ScopeAdmin).
+		Context()
+	defer api.Release()
+	api.SetParamNames("id")
+	api.SetParamValues(schedule["id"].(string))
+
+	if err := api.Handle(ResourceCommandSchedules.Destroy); err != nil {
+		t.Fatal(err)
+	}
+	if err := res.StatusOK(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestCommandScheduleCreateInvalid(t *testing.T) {
+	api, _ := NewTestRequest().
+		Authorize("admin42", auth.ScopeAdmin).
+		JSON(map[string]interface{}{
+			"action":   "decommission_backend",
+			"schedule": "@weekly",
+		}).
+		Context()
+	defer api.Release()
+
+	if err := api.Handle(ResourceCommandSchedules.Create); err == nil {
+		t.Error("expected action not to be allowed")
+	}
+}
@@ -2,8 +2,12 @@ package api
 
 import (
 	"context"
+	"encoding/json"
 	"net/http"
 
+	"github.com/jackc/pgx/v4"
+	"github.com/labstack/echo/v4"
+
 	"github.com/b3scale/b3scale/pkg/cluster"
 	"github.com/b3scale/b3scale/pkg/http/auth"
 	"github.com/b3scale/b3scale/pkg/store"
@@ -23,6 +27,18 @@ var ResourceCommands = &Resource{
 	Create: RequireScope(
 		auth.ScopeAdmin,
 	)(apiCommandCreate),
+
+	Destroy: RequireScope(
+		auth.ScopeAdmin,
+	)(apiCommandCancel),
+}
+
+// allowedCommands can be queued and scheduled
+// through the API.
+var allowedCommands = map[string]bool{
+	cluster.CmdEndAllMeetings:    true,
+	cluster.CmdReconcileMeetings: true,
+	cluster.CmdCollectGarbage:    true,
 }
 
 // ErrCommandNotAllowed is a validation error
@@ -31,14 +47,68 @@ var ErrCommandNotAllowed = store.ValidationError{
 }
 
 // validateCommand checks if the command is ok
-func validateCommand(cmd *store.Command) error {
-	// Only allow DeleteBackend for now
-	if cmd.Action != cluster.CmdEndAllMeetings {
+func validateCommand(action string) error {
+	if !allowedCommands[action] {
 		return ErrCommandNotAllowed
 	}
 	return nil
 }
 
+// validateCommandParams checks the parameters of an
+// allowed command before it is queued, so invalid
+// commands do not fail asynchronously.
+func validateCommandParams(
+	ctx context.Context,
+	tx pgx.Tx,
+	action string,
+	params interface{},
+) error {
+	if action != cluster.CmdReconcileMeetings {
+		return nil
+	}
+	req := &cluster.ReconcileMeetingsRequest{}
+	if err := decodeCommandParams(params, req); err != nil {
+		return err
+	}
+	if err := req.Validate(); err != nil {
+		return store.ValidationError{
+			"params": []string{err.Error()},
+		}
+	}
+	if req.BackendID == "" {
+		return nil
+	}
+	backend, err := store.GetBackendState(ctx, tx, store.Q().
+		Where("id = ?", req.BackendID))
+	if err != nil {
+		return err
+	}
+	if backend == nil {
+		return store.ValidationError{
+			"params": []string{"no such backend: " + req.BackendID},
+		}
+	}
+	return nil
+}
+
+// decodeCommandParams decodes the parameters
+// into the request of the command.
+func decodeCommandParams(params, req interface{}) error {
+	if params == nil {
+		return nil
+	}
+	data, err := json.Marshal(params)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(data, req); err != nil {
+		return store.ValidationError{
+			"params": []string{err.Error()},
+		}
+	}
+	return nil
+}
+
 // apiCommandList returns the command queue
 func apiCommandList(ctx context.Context, api *API) error {
 	// Begin TX
@@ -48,7 +118,16 @@ func apiCommandList(ctx context.Context, api *API) error {
 	}
 	defer tx.Rollback(ctx) //nolint
 
-	commands, err := store.GetCommands(ctx, tx, store.Q())
+	// Filter commands by state and action
+	qry := store.Q()
+	if state := api.QueryParam("state"); state != "" {
+		qry = qry.Where("state = ?", state)
+	}
+	if action := api.QueryParam("action"); action != "" {
+		qry = qry.Where("action = ?", action)
+	}
+
+	commands, err := store.GetCommands(ctx, tx, qry)
 	if err != nil {
 		return err
 	}
@@ -89,7 +168,10 @@ func apiCommandCreate(ctx context.Context, api *API) error {
 	if err := api.Bind(cmd); err != nil {
 		return err
 	}
-	if err := validateCommand(cmd); err != nil {
+	if err := validateCommand(cmd.Action); err != nil {
+		return err
+	}
+	if err := validateCommandParams(ctx, tx, cmd.Action, cmd.Params); err != nil {
 		return err
 	}
 	if err := store.QueueCommand(ctx, tx, cmd); err != nil {
@@ -102,3 +184,58 @@ func apiCommandCreate(ctx context.Context, api *API) error {
 	// Ok
 	return api.JSON(http.StatusAccepted, cmd)
 }
+
+// apiCommandRequeue puts a failed or dead command
+// back into the queue
+func apiCommandRequeue(ctx context.Context, api *API) error {
+	// Begin TX
+	tx, err := api.Conn.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx) //nolint
+
+	id := api.Param("id")
+	if err := store.RequeueCommand(ctx, tx, id); err != nil {
+		if err == store.ErrCommandNotRequeueable {
+			return echo.ErrNotFound
+		}
+		return err
+	}
+	cmd, err := store.GetCommand(ctx, tx, store.Q().Where("id = ?", id))
+	if err != nil {
+		return err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return err
+	}
+
+	return api.JSON(http.StatusAccepted, cmd)
+}
+
+// apiCommandCancel cancels a pending command
+func apiCommandCancel(ctx context.Context, api *API) error {
+	// Begin TX
+	tx, err := api.Conn.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx) //nolint
+
+	id := api.Param("id")
+	if err := store.CancelCommand(ctx, tx, id); err != nil {
+		if err == store.ErrCommandNotPending {
+			return echo.ErrNotFound
+		}
+		return err
+	}
+	cmd, err := store.GetCommand(ctx, tx, store.Q().Where("id = ?", id))
+	if err != nil {
+		return err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return err
+	}
+
+	return api.JSON(http.StatusOK, cmd)
+}
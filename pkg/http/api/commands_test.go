@@ -26,3 +26,50 @@ func TestQueueBackendMeetingsEnd(t *testing.T) {
 	}
 	t.Log(res.Body())
 }
+
+func TestCommandListFilterState(t *testing.T) {
+	api, res := NewTestRequest().
+		Authorize("admin42", auth.ScopeAdmin).
+		Query("state=dead").
+		Context()
+	defer api.Release()
+
+	if err := api.Handle(ResourceCommands.List); err != nil {
+		t.Fatal(err)
+	}
+	if err := res.StatusOK(); err != nil {
+		t.Error(err)
+	}
+	t.Log(res.Body())
+}
+
+func TestQueueReconcileMeetingsInvalid(t *testing.T) {
+	for _, params := range []*cluster.ReconcileMeetingsRequest{
+		{Unknown: "delete"},
+		{BackendID: "00000000-0000-0000-0000-000000000000"},
+	} {
+		api, _ := NewTestRequest().
+			Authorize("admin42", auth.ScopeAdmin).
+			JSON(cluster.ReconcileMeetings(params)).
+			Context()
+		if err := api.Handle(ResourceCommands.Create); err == nil {
+			t.Error("expected a validation error:", params)
+		}
+		api.Release()
+	}
+}
+
+func TestDecodeCommandParams(t *testing.T) {
+	req := &cluster.ReconcileMeetingsRequest{}
+	params := map[string]interface{}{"unknown": "adopt"}
+	if err := decodeCommandParams(params, req); err != nil {
+		t.Fatal(err)
+	}
+	if req.Unknown != cluster.ReconcileAdopt {
+		t.Error("unexpected request:", req)
+	}
+	params = map[string]interface{}{"unknown": 42}
+	if err := decodeCommandParams(params, req); err == nil {
+		t.Error("expected an error for an invalid type")
+	}
+}
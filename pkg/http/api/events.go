@@ -0,0 +1,207 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/rs/zerolog/log"
+
+	"github.com/b3scale/b3scale/pkg/cluster"
+	"github.com/b3scale/b3scale/pkg/http/auth"
+	"github.com/b3scale/b3scale/pkg/store"
+)
+
+// EventsStreamPath is the route of the events stream
+const EventsStreamPath = "/api/v1/events/stream"
+
+const (
+	// EventsStreamKeepAlive is the interval of comments
+	// sent to keep the connection of the stream open.
+	EventsStreamKeepAlive = 15 * time.Second
+
+	// eventsStreamFrontendsRefresh is the interval for
+	// reloading the frontends visible to a user.
+	eventsStreamFrontendsRefresh = 1 * time.Minute
+)
+
+// StreamingSkipper skips middlewares, which would end
+// long running streams, like the request timeout.
+func StreamingSkipper(c echo.Context) bool {
+	return c.Path() == EventsStreamPath
+}
+
+// eventsFilter selects the events visible
+// to the subscriber of the stream.
+type eventsFilter struct {
+	admin bool
+	ref   string
+	types map[string]bool
+
+	frontendIDs      map[string]bool
+	frontendsLoadAt  time.Time
+	loadFrontendsIDs func(context.Context, string) (map[string]bool, error)
+}
+
+// newEventsFilter creates a filter for the API
+// request. The event types can be limited by
+// a comma separated list in the types parameter.
+func newEventsFilter(api *API) (*eventsFilter, error) {
+	f := &eventsFilter{
+		admin:            api.HasScope(auth.ScopeAdmin),
+		ref:              api.Ref,
+		loadFrontendsIDs: loadAccountFrontendIDs,
+	}
+	param := strings.TrimSpace(api.QueryParam("types"))
+	if param == "" {
+		return f, nil
+	}
+	f.types = map[string]bool{}
+	for _, t := range strings.Split(param, ",") {
+		t = strings.TrimSpace(t)
+		if !isStreamEventType(t) {
+			return nil, echo.NewHTTPError(
+				http.StatusBadRequest, "unknown event type: "+t)
+		}
+		f.types[t] = true
+	}
+	return f, nil
+}
+
+// isStreamEventType checks if the event
+// type is published to the stream
+func isStreamEventType(t string) bool {
+	return store.IsEventType(t) ||
+		t == store.EventBackendHeartbeat ||
+		t == store.EventCommandStateChanged
+}
+
+// Visible checks if the event is visible to the subscriber.
+// Admins see all events, users only see the events of the
+// frontends of their account.
+func (f *eventsFilter) Visible(ctx context.Context, ev *cluster.Event) bool {
+	if f.types != nil && !f.types[ev.Type] {
+		return false
+	}
+	if f.admin {
+		return true
+	}
+	if ev.FrontendID == nil {
+		return false // Backends and commands are not visible
+	}
+	if time.Since(f.frontendsLoadAt) > eventsStreamFrontendsRefresh {
+		ids, err := f.loadFrontendsIDs(ctx, f.ref)
+		if err != nil {
+			log.Error().Err(err).Msg("could not load frontends for events stream")
+			return false
+		}
+		f.frontendIDs = ids
+		f.frontendsLoadAt = time.Now()
+	}
+	return f.frontendIDs[*ev.FrontendID]
+}
+
+// loadAccountFrontendIDs retrieves the IDs of the
+// frontends of an account.
+func loadAccountFrontendIDs(
+	ctx context.Context,
+	ref string,
+) (map[string]bool, error) {
+	conn, err := store.Acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Release()
+	tx, err := conn.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx) //nolint
+
+	frontends, err := store.GetFrontendStates(ctx, tx, store.Q().
+		Where("account_ref = ?", ref))
+	if err != nil {
+		return nil, err
+	}
+	ids := make(map[string]bool, len(frontends))
+	for _, fe := range frontends {
+		ids[fe.ID] = true
+	}
+	return ids, nil
+}
+
+// writeSSEEvent encodes the event as server sent event
+func writeSSEEvent(w io.Writer, ev *cluster.Event) error {
+	data, err := json.Marshal(ev)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "id: %s\nevent: %s\ndata: %s\n\n",
+		ev.ID, ev.Type, data)
+	return err
+}
+
+// apiEventsStream streams the events of the cluster
+// as server sent events, until the client disconnects.
+func apiEventsStream(
+	ctx context.Context,
+	api *API,
+) error {
+	filter, err := newEventsFilter(api)
+	if err != nil {
+		return err
+	}
+
+	// The stream does not need the connection of the
+	// request. Releasing it twice is safe.
+	api.Conn.Release()
+
+	events, unsubscribe := cluster.SubscribeEvents()
+	defer unsubscribe()
+
+	res := api.Response()
+	// The stream outlives the write timeout of the server
+	rc := http.NewResponseController(res)
+	if err := rc.SetWriteDeadline(time.Time{}); err != nil {
+		log.Warn().Err(err).Msg("could not clear write deadline of events stream")
+	}
+
+	header := res.Header()
+	header.Set(echo.HeaderContentType, "text/event-stream")
+	header.Set(echo.HeaderCacheControl, "no-cache")
+	header.Set(echo.HeaderConnection, "keep-alive")
+	header.Set("X-Accel-Buffering", "no") // Disable proxy buffering
+	res.WriteHeader(http.StatusOK)
+	res.Flush()
+
+	keepAlive := time.NewTicker(EventsStreamKeepAlive)
+	defer keepAlive.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-keepAlive.C:
+			if _, err := io.WriteString(res, ": keep-alive\n\n"); err != nil {
+				return nil // The client is gone
+			}
+			res.Flush()
+		case ev, ok := <-events:
+			if !ok {
+				return nil
+			}
+			if !filter.Visible(ctx, ev) {
+				continue
+			}
+			if err := writeSSEEvent(res, ev); err != nil {
+				return nil // The client is gone
+			}
+			res.Flush()
+		}
+	}
+}
@@ -0,0 +1,75 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/b3scale/b3scale/pkg/cluster"
+	"github.com/b3scale/b3scale/pkg/store"
+)
+
+func TestWriteSSEEvent(t *testing.T) {
+	ev := cluster.NewEvent(store.EventMeetingEnded, map[string]string{
+		"meeting_id": "m1",
+	})
+	buf := &bytes.Buffer{}
+	if err := writeSSEEvent(buf, ev); err != nil {
+		t.Fatal(err)
+	}
+	res := buf.String()
+	if !strings.HasPrefix(res, "id: "+ev.ID+"\nevent: meeting.ended\ndata: {") {
+		t.Error("unexpected event:", res)
+	}
+	if !strings.HasSuffix(res, "}\n\n") {
+		t.Error("event should end with an empty line:", res)
+	}
+}
+
+func TestEventsFilterVisible(t *testing.T) {
+	ctx := context.Background()
+	fe1, fe2 := "fe1", "fe2"
+	meeting := cluster.NewEvent(store.EventMeetingCreated, nil)
+	meeting.FrontendID = &fe1
+	other := cluster.NewEvent(store.EventMeetingCreated, nil)
+	other.FrontendID = &fe2
+	heartbeat := cluster.NewEvent(store.EventBackendHeartbeat, nil)
+
+	admin := &eventsFilter{admin: true}
+	if !admin.Visible(ctx, meeting) || !admin.Visible(ctx, heartbeat) {
+		t.Error("admin should see all events")
+	}
+
+	loads := 0
+	user := &eventsFilter{
+		ref: "account1",
+		loadFrontendsIDs: func(_ context.Context, ref string) (map[string]bool, error) {
+			loads++
+			if ref != "account1" {
+				t.Error("unexpected ref:", ref)
+			}
+			return map[string]bool{fe1: true}, nil
+		},
+	}
+	if !user.Visible(ctx, meeting) {
+		t.Error("user should see events of own frontend")
+	}
+	if user.Visible(ctx, other) {
+		t.Error("user should not see events of other frontends")
+	}
+	if user.Visible(ctx, heartbeat) {
+		t.Error("user should not see backend events")
+	}
+	if loads != 1 {
+		t.Error("frontends should be cached, loads:", loads)
+	}
+
+	typed := &eventsFilter{
+		admin: true,
+		types: map[string]bool{store.EventBackendHeartbeat: true},
+	}
+	if typed.Visible(ctx, meeting) || !typed.Visible(ctx, heartbeat) {
+		t.Error("events should be filtered by type")
+	}
+}
@@ -0,0 +1,135 @@
+package api
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/b3scale/b3scale/pkg/cluster"
+	"github.com/b3scale/b3scale/pkg/store"
+)
+
+// ErrMeetingNotRunning is returned when a meeting
+// is not associated with a backend.
+var ErrMeetingNotRunning = echo.NewHTTPError(
+	http.StatusConflict,
+	"the meeting is not running on a backend")
+
+// apiFrontendMeetingsList will retrieve all meetings
+// of a frontend including the attendees.
+func apiFrontendMeetingsList(
+	ctx context.Context,
+	api *API,
+) error {
+	tx, err := api.Conn.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx) //nolint
+
+	frontend, err := FrontendFromRequest(ctx, api, tx)
+	if err != nil {
+		return err
+	}
+
+	meetings, err := store.GetMeetingStates(ctx, tx, store.Q().
+		Where("meetings.frontend_id = ?", frontend.ID))
+	if err != nil {
+		return err
+	}
+
+	return api.JSON(http.StatusOK, meetings)
+}
+
+// apiFrontendMeetingEnd will end a meeting of the
+// frontend on the backend and remove the meeting
+// from the store.
+func apiFrontendMeetingEnd(
+	ctx context.Context,
+	api *API,
+) error {
+	tx, err := api.Conn.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx) //nolint
+
+	frontend, err := FrontendFromRequest(ctx, api, tx)
+	if err != nil {
+		return err
+	}
+	meeting, err := store.GetMeetingState(ctx, tx, store.Q().
+		Where("meetings.id = ?", api.Param("meeting_id")).
+		Where("meetings.frontend_id = ?", frontend.ID))
+	if err != nil {
+		return err
+	}
+	if meeting == nil {
+		return echo.ErrNotFound
+	}
+	if meeting.BackendID == nil {
+		return ErrMeetingNotRunning
+	}
+
+	// Release the transaction before talking to the backend
+	if err := tx.Rollback(ctx); err != nil {
+		return err
+	}
+
+	cctx := store.ContextWithConnection(ctx, api.Conn)
+	backend, err := cluster.GetBackend(cctx, store.Q().
+		Where("id = ?", *meeting.BackendID))
+	if err != nil {
+		return err
+	}
+	if backend == nil {
+		return ErrMeetingNotRunning
+	}
+	if err := backend.EndMeeting(cctx, meeting); err != nil {
+		return echo.NewHTTPError(http.StatusBadGateway, err.Error())
+	}
+
+	// The meeting is gone
+	tx, err = api.Conn.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx) //nolint
+	if err := store.DeleteMeetingStateByID(ctx, tx, meeting.ID); err != nil {
+		return err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return err
+	}
+
+	return api.JSON(http.StatusOK, meeting)
+}
+
+// apiFrontendUsageShow will respond with the current
+// number of meetings and attendees of the frontend
+// and the attendees limit.
+func apiFrontendUsageShow(
+	ctx context.Context,
+	api *API,
+) error {
+	tx, err := api.Conn.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx) //nolint
+
+	frontend, err := FrontendFromRequest(ctx, api, tx)
+	if err != nil {
+		return err
+	}
+	usage, err := store.GetFrontendUsage(ctx, tx, frontend.ID)
+	if err != nil {
+		return err
+	}
+	if limit := frontend.Settings.AttendeesLimit; limit != nil {
+		usage.AttendeesLimit = limit.Limit
+	}
+
+	return api.JSON(http.StatusOK, usage)
+}
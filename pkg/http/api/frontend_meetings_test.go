@@ -0,0 +1,136 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+
+	"github.com/b3scale/b3scale/pkg/bbb"
+	"github.com/b3scale/b3scale/pkg/http/auth"
+	"github.com/b3scale/b3scale/pkg/store"
+)
+
+// createTestFrontendMeeting creates a meeting
+// of the frontend.
+func createTestFrontendMeeting(
+	api *API,
+	frontend *store.FrontendState,
+) *store.MeetingState {
+	ctx := api.Ctx()
+	tx, err := api.Conn.Begin(ctx)
+	if err != nil {
+		panic(err)
+	}
+	defer tx.Rollback(ctx) //nolint
+
+	m := store.InitMeetingState(&store.MeetingState{
+		FrontendID: &frontend.ID,
+		Meeting: &bbb.Meeting{
+			MeetingID:         uuid.New().String(),
+			InternalMeetingID: uuid.New().String(),
+			Running:           true,
+		},
+	})
+	if err := m.Save(ctx, tx); err != nil {
+		panic(err)
+	}
+	if err := tx.Commit(ctx); err != nil {
+		panic(err)
+	}
+	return m
+}
+
+func TestFrontendMeetingsList(t *testing.T) {
+	api, res := NewTestRequest().
+		Authorize("user23", auth.ScopeUser).
+		Context()
+	defer api.Release()
+
+	f := createTestFrontend(api)
+	other := createTestAccountFrontend(api, "user42")
+
+	m := createTestFrontendMeeting(api, f)
+	createTestFrontendMeeting(api, other)
+
+	api.SetParamNames("id")
+	api.SetParamValues(f.ID)
+
+	if err := api.Handle(apiFrontendMeetingsList); err != nil {
+		t.Fatal(err)
+	}
+	if err := res.StatusOK(); err != nil {
+		t.Error(err)
+	}
+
+	// Only the meetings of the frontend are listed
+	meetings := []*store.MeetingState{}
+	if err := json.Unmarshal([]byte(res.Body()), &meetings); err != nil {
+		t.Fatal(err)
+	}
+	if len(meetings) != 1 {
+		t.Fatal("unexpected meetings:", meetings)
+	}
+	if meetings[0].ID != m.ID {
+		t.Error("unexpected meeting:", meetings[0].ID)
+	}
+}
+
+func TestFrontendMeetingsListOtherAccount(t *testing.T) {
+	api, _ := NewTestRequest().
+		Authorize("user23", auth.ScopeUser).
+		Context()
+	defer api.Release()
+
+	other := createTestAccountFrontend(api, "user42")
+	createTestFrontendMeeting(api, other)
+
+	api.SetParamNames("id")
+	api.SetParamValues(other.ID)
+
+	err := api.Handle(apiFrontendMeetingsList)
+	if !errors.Is(err, echo.ErrNotFound) {
+		t.Error("expected not found for a frontend of another account, got:", err)
+	}
+}
+
+func TestFrontendMeetingEndNotFound(t *testing.T) {
+	api, _ := NewTestRequest().
+		Authorize("user23", auth.ScopeUser).
+		Context()
+	defer api.Release()
+
+	f := createTestFrontend(api)
+
+	api.SetParamNames("id", "meeting_id")
+	api.SetParamValues(f.ID, "unknown-meeting")
+
+	if err := api.Handle(apiFrontendMeetingEnd); err == nil {
+		t.Error("expected an error for an unknown meeting")
+	}
+}
+
+func TestFrontendUsageShow(t *testing.T) {
+	api, res := NewTestRequest().
+		Authorize("user23", auth.ScopeUser).
+		Context()
+	defer api.Release()
+
+	f := createTestFrontend(api)
+
+	api.SetParamNames("id")
+	api.SetParamValues(f.ID)
+
+	if err := api.Handle(apiFrontendUsageShow); err != nil {
+		t.Fatal(err)
+	}
+	if err := res.StatusOK(); err != nil {
+		t.Error(err)
+	}
+	data := res.JSON()
+	if data["meetings"].(float64) != 0 {
+		t.Error("unexpected meetings:", data)
+	}
+}
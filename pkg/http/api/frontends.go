@@ -280,3 +280,37 @@ func apiFrontendUpdate(
 
 	return api.JSON(http.StatusOK, frontend)
 }
+
+// apiFrontendSecretRotate will replace the secret of
+// the frontend with a new random secret. The frontend
+// must use the new secret for all further requests.
+func apiFrontendSecretRotate(
+	ctx context.Context,
+	api *API,
+) error {
+	tx, err := api.Conn.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx) //nolint
+
+	frontend, err := FrontendFromRequest(ctx, api, tx)
+	if err != nil {
+		return err
+	}
+
+	secret, err := auth.GenerateSecret(20)
+	if err != nil {
+		return err
+	}
+	frontend.Frontend.Secret = secret
+
+	if err := frontend.Save(ctx, tx); err != nil {
+		return err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return err
+	}
+
+	return api.JSON(http.StatusOK, frontend)
+}
@@ -9,6 +9,15 @@ import (
 )
 
 func createTestFrontend(api *API) *store.FrontendState {
+	return createTestAccountFrontend(api, "user23")
+}
+
+// createTestAccountFrontend creates a frontend
+// associated with the account ref.
+func createTestAccountFrontend(
+	api *API,
+	ref string,
+) *store.FrontendState {
 	ctx := api.Ctx()
 	tx, err := api.Conn.Begin(ctx)
 	if err != nil {
@@ -16,10 +25,9 @@ func createTestFrontend(api *API) *store.FrontendState {
 	}
 	defer tx.Rollback(ctx) //nolint
 
-	ref := "user23"
 	f := store.InitFrontendState(&store.FrontendState{
 		Frontend: &bbb.Frontend{
-			Key:    "testkey",
+			Key:    "testkey-" + ref,
 			Secret: "testsecret",
 		},
 		Active: true,
@@ -185,3 +193,44 @@ func TestFrontendDestroy(t *testing.T) {
 	}
 	t.Log("destroy:", res.Body())
 }
+
+func TestFrontendSecretRotate(t *testing.T) {
+	api, res := NewTestRequest().
+		Authorize("user23", auth.ScopeUser).
+		Context()
+	defer api.Release()
+
+	f := createTestFrontend(api)
+
+	api.SetParamNames("id")
+	api.SetParamValues(f.ID)
+
+	if err := api.Handle(apiFrontendSecretRotate); err != nil {
+		t.Fatal(err)
+	}
+	if err := res.StatusOK(); err != nil {
+		t.Error(err)
+	}
+
+	data := res.JSON()
+	fe := data["bbb"].(map[string]interface{})
+	if fe["secret"] == f.Frontend.Secret {
+		t.Error("secret was not rotated")
+	}
+}
+
+func TestFrontendSecretRotateOtherAccount(t *testing.T) {
+	api, _ := NewTestRequest().
+		Authorize("user42", auth.ScopeUser).
+		Context()
+	defer api.Release()
+
+	f := createTestFrontend(api)
+
+	api.SetParamNames("id")
+	api.SetParamValues(f.ID)
+
+	if err := api.Handle(apiFrontendSecretRotate); err == nil {
+		t.Error("expected an error for a frontend of another account")
+	}
+}
@@ -0,0 +1,185 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/b3scale/b3scale/pkg/cluster"
+	"github.com/b3scale/b3scale/pkg/http/auth"
+	"github.com/b3scale/b3scale/pkg/store"
+)
+
+// ResourceMaintenanceWindows is a restful group
+// for scheduling maintenance of backends
+var ResourceMaintenanceWindows = &Resource{
+	List: RequireScope(
+		auth.ScopeAdmin,
+	)(apiMaintenanceWindowsList),
+
+	Show: RequireScope(
+		auth.ScopeAdmin,
+	)(apiMaintenanceWindowShow),
+
+	Create: RequireScope(
+		auth.ScopeAdmin,
+	)(apiMaintenanceWindowCreate),
+
+	Destroy: RequireScope(
+		auth.ScopeAdmin,
+	)(apiMaintenanceWindowCancel),
+}
+
+// apiMaintenanceWindowsList returns the maintenance windows,
+// optionally filtered by backend_id and state.
+func apiMaintenanceWindowsList(
+	ctx context.Context,
+	api *API,
+) error {
+	tx, err := api.Conn.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx) //nolint
+
+	q := store.Q()
+	backendID := strings.TrimSpace(api.QueryParam("backend_id"))
+	if backendID != "" {
+		q = q.Where("maintenance_windows.backend_id = ?", backendID)
+	}
+	state := strings.TrimSpace(api.QueryParam("state"))
+	if state != "" {
+		q = q.Where("maintenance_windows.state = ?", state)
+	}
+
+	windows, err := store.GetMaintenanceWindows(ctx, tx, q)
+	if err != nil {
+		return err
+	}
+	return api.JSON(http.StatusOK, windows)
+}
+
+// apiMaintenanceWindowShow returns a single
+// maintenance window by ID
+func apiMaintenanceWindowShow(
+	ctx context.Context,
+	api *API,
+) error {
+	tx, err := api.Conn.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx) //nolint
+
+	id := api.Param("id")
+	window, err := store.GetMaintenanceWindow(ctx, tx, store.Q().
+		Where("maintenance_windows.id = ?", id))
+	if err != nil {
+		return err
+	}
+	if window == nil {
+		return echo.ErrNotFound
+	}
+	return api.JSON(http.StatusOK, window)
+}
+
+// apiMaintenanceWindowCreate schedules a new
+// maintenance window for a backend
+func apiMaintenanceWindowCreate(
+	ctx context.Context,
+	api *API,
+) error {
+	tx, err := api.Conn.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx) //nolint
+
+	window := &store.MaintenanceWindow{}
+	if err := api.Bind(window); err != nil {
+		return err
+	}
+	window = store.InitMaintenanceWindow(&store.MaintenanceWindow{
+		BackendID:  window.BackendID,
+		AdminState: window.AdminState,
+		Comment:    window.Comment,
+		StartsAt:   window.StartsAt,
+		EndsAt:     window.EndsAt,
+	})
+	if err := window.Validate(); err != nil {
+		return err
+	}
+
+	backend, err := store.GetBackendState(ctx, tx, store.Q().
+		Where("id = ?", window.BackendID))
+	if err != nil {
+		return err
+	}
+	if backend == nil {
+		return store.ValidationError{
+			"backend_id": []string{"backend does not exist"},
+		}
+	}
+
+	overlaps, err := window.HasOverlappingMaintenanceWindow(ctx, tx)
+	if err != nil {
+		return err
+	}
+	if overlaps {
+		return store.ValidationError{
+			"starts_at": []string{
+				"overlaps with another maintenance window of the backend",
+			},
+		}
+	}
+
+	if err := window.Save(ctx, tx); err != nil {
+		return err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return err
+	}
+
+	return api.JSON(http.StatusOK, window)
+}
+
+// apiMaintenanceWindowCancel cancels a maintenance window.
+// If the window is active, the backend will be ready again.
+func apiMaintenanceWindowCancel(
+	ctx context.Context,
+	api *API,
+) error {
+	tx, err := api.Conn.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx) //nolint
+
+	id := api.Param("id")
+	window, err := store.GetMaintenanceWindow(ctx, tx, store.Q().
+		Where("maintenance_windows.id = ?", id))
+	if err != nil {
+		return err
+	}
+	if window == nil {
+		return echo.ErrNotFound
+	}
+	if !window.IsOpen() {
+		return store.ValidationError{
+			"state": []string{"maintenance window is already " + window.State},
+		}
+	}
+
+	if err := cluster.EmitBackendStateChange(ctx, tx, window.BackendID, func() error {
+		return window.End(ctx, tx, store.MaintenanceWindowCanceled)
+	}); err != nil {
+		return err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return err
+	}
+
+	return api.JSON(http.StatusOK, window)
+}
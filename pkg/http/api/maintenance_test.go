@@ -0,0 +1,75 @@
+package api
+
+import (
+	"testing"
+	"time"
+
+	"github.com/b3scale/b3scale/pkg/http/auth"
+)
+
+func TestMaintenanceWindowCreateCancel(t *testing.T) {
+	api, _ := NewTestRequest().
+		Authorize("admin42", auth.ScopeAdmin).
+		Context()
+	backend := createTestBackend(api)
+	api.Release()
+
+	now := time.Now().UTC()
+	api, res := NewTestRequest().
+		Authorize("admin42", auth.ScopeAdmin).
+		JSON(map[string]interface{}{
+			"backend_id":  backend.ID,
+			"admin_state": "stopped",
+			"starts_at":   now.Add(time.Hour),
+			"ends_at":     now.Add(2 * time.Hour),
+		}).
+		Context()
+	defer api.Release()
+
+	if err := api.Handle(ResourceMaintenanceWindows.Create); err != nil {
+		t.Fatal(err)
+	}
+	if err := res.StatusOK(); err != nil {
+		t.Fatal(err)
+	}
+	window := res.JSON()
+	if window["state"] != "scheduled" {
+		t.Error("unexpected state:", window["state"])
+	}
+
+	// Cancel the window
+	api, res = NewTestRequest().
+		Authorize("admin42", auth.ScopeAdmin).
+		Context()
+	defer api.Release()
+	api.SetParamNames("id")
+	api.SetParamValues(window["id"].(string))
+
+	if err := api.Handle(ResourceMaintenanceWindows.Destroy); err != nil {
+		t.Fatal(err)
+	}
+	if err := res.StatusOK(); err != nil {
+		t.Fatal(err)
+	}
+	window = res.JSON()
+	if window["state"] != "canceled" {
+		t.Error("unexpected state:", window["state"])
+	}
+}
+
+func TestMaintenanceWindowCreateInvalid(t *testing.T) {
+	now := time.Now().UTC()
+	api, _ := NewTestRequest().
+		Authorize("admin42", auth.ScopeAdmin).
+		JSON(map[string]interface{}{
+			"admin_state": "ready",
+			"starts_at":   now,
+			"ends_at":     now.Add(-time.Hour),
+		}).
+		Context()
+	defer api.Release()
+
+	if err := api.Handle(ResourceMaintenanceWindows.Create); err == nil {
+		t.Error("expected validation error")
+	}
+}
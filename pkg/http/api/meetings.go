@@ -142,6 +142,13 @@ func apiMeetingDestroy(
 		return err
 	}
 
+	// Account the usage, if not already done when
+	// the meeting ended.
+	if err := store.CloseUsageRecord(
+		ctx, tx, meeting.InternalID, meeting.Meeting.Recording,
+	); err != nil {
+		return err
+	}
 	if err := store.DeleteMeetingStateByID(ctx, tx, meeting.ID); err != nil {
 		return err
 	}
@@ -152,3 +159,32 @@ func apiMeetingDestroy(
 
 	return api.JSON(http.StatusOK, meeting)
 }
+
+// apiMeetingRoutingShow will respond with the routing
+// record of the meeting, explaining why the backend
+// was selected.
+func apiMeetingRoutingShow(
+	ctx context.Context,
+	api *API,
+) error {
+	tx, err := api.Conn.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx) //nolint
+
+	meeting, err := MeetingFromRequest(ctx, api, tx)
+	if err != nil {
+		return err
+	}
+
+	record, err := store.GetMeetingRouting(ctx, tx, meeting.ID)
+	if err != nil {
+		return err
+	}
+	if record == nil {
+		return echo.ErrNotFound
+	}
+
+	return api.JSON(http.StatusOK, record)
+}
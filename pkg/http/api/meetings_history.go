@@ -0,0 +1,113 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/b3scale/b3scale/pkg/http/auth"
+	"github.com/b3scale/b3scale/pkg/store"
+)
+
+// MeetingsHistoryResponse contains the archived meetings
+// and the aggregates for a time range.
+type MeetingsHistoryResponse struct {
+	From       time.Time                      `json:"from" doc:"Start of the time range."`
+	To         time.Time                      `json:"to" doc:"End of the time range."`
+	Resolution string                         `json:"resolution" doc:"The resolution of the aggregates." enum:"daily,hourly"`
+	Meetings   []*store.MeetingHistoryEntry   `json:"meetings,omitempty" doc:"The meetings running in the time range. This is omitted in a summary."`
+	Aggregates []*store.MeetingsHistoryBucket `json:"aggregates" doc:"The aggregates per day or hour."`
+}
+
+// MeetingsHistoryMaxRange limits the time range of
+// a meetings history request by resolution.
+var MeetingsHistoryMaxRange = map[string]time.Duration{
+	store.HistoryResolutionHourly: 31 * 24 * time.Hour,
+	store.HistoryResolutionDaily:  366 * 24 * time.Hour,
+}
+
+// ResourceMeetingsHistory is the archive of past meetings
+var ResourceMeetingsHistory = &Resource{
+	List: RequireScope(
+		auth.ScopeAdmin,
+	)(apiMeetingsHistoryList),
+}
+
+// apiMeetingsHistoryList responds with the meetings
+// running in a time range and the daily or hourly
+// aggregates. The meetings can be filtered by
+// frontend and backend.
+func apiMeetingsHistoryList(
+	ctx context.Context,
+	api *API,
+) error {
+	resolution := api.QueryParam("resolution")
+	if resolution == "" {
+		resolution = store.HistoryResolutionDaily
+	}
+	if resolution != store.HistoryResolutionDaily &&
+		resolution != store.HistoryResolutionHourly {
+		return echo.NewHTTPError(
+			http.StatusBadRequest, "resolution must be daily or hourly")
+	}
+
+	// By default, show the last 7 days
+	now := time.Now().UTC()
+	from, to, err := TimeRangeFromQueryParams(api,
+		now.AddDate(0, 0, -7), now)
+	if err != nil {
+		return err
+	}
+	if !from.Before(to) {
+		return echo.NewHTTPError(
+			http.StatusBadRequest, "from must be before to")
+	}
+	if maxRange := MeetingsHistoryMaxRange[resolution]; to.Sub(from) > maxRange {
+		return echo.NewHTTPError(
+			http.StatusBadRequest, fmt.Sprintf(
+				"the time range of the %s resolution must not exceed %d days",
+				resolution, int(maxRange.Hours()/24)))
+	}
+
+	tx, err := api.Conn.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx) //nolint
+
+	q := store.QueryMeetingsHistoryRange(from, to)
+	if api.QueryParam("frontend_id") != "" || api.QueryParam("frontend_key") != "" {
+		fe, err := FrontendFromQueryParams(ctx, api, tx)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusNotFound, err.Error())
+		}
+		q = q.Where("meetings_history.frontend_id = ?", fe.ID)
+	}
+	if id := api.QueryParam("backend_id"); id != "" {
+		q = q.Where("meetings_history.backend_id = ?", id)
+	}
+	if host := api.QueryParam("backend_host"); host != "" {
+		q = q.Where("meetings_history.backend_host = ?", host)
+	}
+
+	meetings, err := store.GetMeetingsHistory(ctx, tx, q)
+	if err != nil {
+		return err
+	}
+
+	res := &MeetingsHistoryResponse{
+		From:       from,
+		To:         to,
+		Resolution: resolution,
+		Meetings:   meetings,
+		Aggregates: store.AggregateMeetingsHistory(
+			meetings, from, to, resolution),
+	}
+	if api.QueryParam("summary") == "true" {
+		res.Meetings = nil
+	}
+	return api.JSON(http.StatusOK, res)
+}
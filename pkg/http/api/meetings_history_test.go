@@ -0,0 +1,51 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/b3scale/b3scale/pkg/http/auth"
+)
+
+func TestMeetingsHistoryList(t *testing.T) {
+	api, res := NewTestRequest().
+		Authorize("admin42", auth.ScopeAdmin).
+		Query("from=2026-10-01&to=2026-10-03&resolution=daily").
+		Context()
+	defer api.Release()
+
+	if err := api.Handle(ResourceMeetingsHistory.List); err != nil {
+		t.Fatal(err)
+	}
+	if err := res.StatusOK(); err != nil {
+		t.Error(err)
+	}
+	data := res.JSON()
+	aggregates := data["aggregates"].([]interface{})
+	if len(aggregates) != 2 {
+		t.Error("unexpected aggregates:", aggregates)
+	}
+}
+
+func TestMeetingsHistoryListInvalidResolution(t *testing.T) {
+	api, _ := NewTestRequest().
+		Authorize("admin42", auth.ScopeAdmin).
+		Query("resolution=weekly").
+		Context()
+	defer api.Release()
+
+	if err := api.Handle(ResourceMeetingsHistory.List); err == nil {
+		t.Error("expected an error for an invalid resolution")
+	}
+}
+
+func TestMeetingsHistoryListRangeTooLarge(t *testing.T) {
+	api, _ := NewTestRequest().
+		Authorize("admin42", auth.ScopeAdmin).
+		Query("from=2000-01-01&to=2026-10-01&resolution=hourly").
+		Context()
+	defer api.Release()
+
+	if err := api.Handle(ResourceMeetingsHistory.List); err == nil {
+		t.Error("expected an error for a too large time range")
+	}
+}
@@ -183,3 +183,50 @@ func TestMeetingUpdate(t *testing.T) {
 	}
 
 }
+
+func TestMeetingRoutingShow(t *testing.T) {
+	api, res := NewTestRequest().
+		Authorize("admin42", auth.ScopeAdmin).
+		Context()
+	defer api.Release()
+
+	backend := createTestBackend(api)
+	meeting := createTestMeeting(api, backend)
+
+	// Store a routing record
+	ctx := api.Ctx()
+	tx, err := api.Conn.Begin(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tx.Rollback(ctx) //nolint
+	record := &store.RoutingRecord{
+		BackendID: &backend.ID,
+		Steps: []*store.RoutingStep{
+			{
+				Middleware: "routing.RequiredTags",
+				Backends:   []string{backend.Backend.Host},
+			},
+		},
+	}
+	if err := store.SetMeetingRouting(ctx, tx, meeting.ID, record); err != nil {
+		t.Fatal(err)
+	}
+	if err := tx.Commit(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	api.SetParamNames("id")
+	api.SetParamValues(meeting.ID)
+
+	if err := api.Handle(apiMeetingRoutingShow); err != nil {
+		t.Fatal(err)
+	}
+	if err := res.StatusOK(); err != nil {
+		t.Error(err)
+	}
+	body := res.Body()
+	if !strings.Contains(body, "routing.RequiredTags") {
+		t.Error("unexpected response:", body)
+	}
+}
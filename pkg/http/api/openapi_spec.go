@@ -2,6 +2,7 @@ package api
 
 import (
 	"github.com/b3scale/b3scale/pkg/bbb"
+	"github.com/b3scale/b3scale/pkg/cluster"
 	oa "github.com/b3scale/b3scale/pkg/openapi"
 	"github.com/b3scale/b3scale/pkg/store"
 	"github.com/b3scale/b3scale/pkg/store/schema"
@@ -100,6 +101,75 @@ func NewFrontendsAPISchema() map[string]oa.Path {
 				},
 			},
 		},
+		"/v1/frontends/{id}/secret": oa.Path{
+			"parameters": []oa.Schema{
+				oa.ParamID(),
+			},
+			"post": oa.Operation{
+				Description: "Replace the secret of the frontend with a new random secret.\n\nThe frontend must use the new secret for all further requests.",
+				OperationID: "frontendsSecretRotate",
+				Summary:     "Rotate Secret",
+				Tags:        []string{"Frontends"},
+				Responses: oa.ResponseRefs{
+					"200": oa.ResponseRef("Frontend"),
+					"400": oa.ResponseRef("BadRequest"),
+					"401": oa.ResponseRef("InvalidJWTError"),
+					"404": oa.ResponseRef("NotFoundError"),
+				},
+			},
+		},
+		"/v1/frontends/{id}/usage": oa.Path{
+			"parameters": []oa.Schema{
+				oa.ParamID(),
+			},
+			"get": oa.Operation{
+				Description: "Fetch the current number of meetings and attendees of the frontend and the attendees limit.",
+				OperationID: "frontendsUsageRead",
+				Summary:     "Usage",
+				Tags:        []string{"Frontends"},
+				Responses: oa.ResponseRefs{
+					"200": oa.ResponseRef("FrontendUsage"),
+					"400": oa.ResponseRef("BadRequest"),
+					"401": oa.ResponseRef("InvalidJWTError"),
+					"404": oa.ResponseRef("NotFoundError"),
+				},
+			},
+		},
+		"/v1/frontends/{id}/meetings": oa.Path{
+			"parameters": []oa.Schema{
+				oa.ParamID(),
+			},
+			"get": oa.Operation{
+				Description: "Fetch all meetings of the frontend including the attendees.",
+				OperationID: "frontendsMeetingsList",
+				Summary:     "List Meetings",
+				Tags:        []string{"Frontends"},
+				Responses: oa.ResponseRefs{
+					"200": oa.ResponseRef("Meetings"),
+					"400": oa.ResponseRef("BadRequest"),
+					"401": oa.ResponseRef("InvalidJWTError"),
+					"404": oa.ResponseRef("NotFoundError"),
+				},
+			},
+		},
+		"/v1/frontends/{id}/meetings/{meeting_id}": oa.Path{
+			"parameters": []oa.Schema{
+				oa.ParamID(),
+				oa.ParamPath("meeting_id", "The ID of the meeting"),
+			},
+			"delete": oa.Operation{
+				Description: "End a meeting of the frontend.\n\nThe meeting is ended on the backend and removed.",
+				OperationID: "frontendsMeetingEnd",
+				Summary:     "End Meeting",
+				Tags:        []string{"Frontends"},
+				Responses: oa.ResponseRefs{
+					"200": oa.ResponseRef("Meeting"),
+					"400": oa.ResponseRef("BadRequest"),
+					"401": oa.ResponseRef("InvalidJWTError"),
+					"404": oa.ResponseRef("NotFoundError"),
+				},
+			},
+		},
 	}
 }
 
@@ -278,6 +348,23 @@ func NewMeetingsAPISchema() map[string]oa.Path {
 				},
 			},
 		},
+		"/v1/meetings/{id}/routing": oa.Path{
+			"parameters": []oa.Schema{
+				oa.ParamID(),
+			},
+			"get": oa.Operation{
+				Description: "Explain why the backend was selected when the meeting was created.",
+				OperationID: "meetingsRoutingRead",
+				Summary:     "Routing",
+				Tags:        []string{"Meetings"},
+				Responses: oa.ResponseRefs{
+					"200": oa.ResponseRef("RoutingRecord"),
+					"400": oa.ResponseRef("BadRequest"),
+					"401": oa.ResponseRef("InvalidJWTError"),
+					"404": oa.ResponseRef("NotFoundError"),
+				},
+			},
+		},
 	}
 }
 
@@ -295,9 +382,17 @@ func NewCommandsAPISchema() map[string]oa.Path {
 					"400": oa.ResponseRef("BadRequest"),
 					"401": oa.ResponseRef("InvalidJWTError"),
 				},
+				Parameters: []oa.Schema{
+					oa.ParamQuery(
+						"state",
+						"Only list commands in this state, e.g. `dead`."),
+					oa.ParamQuery(
+						"action",
+						"Only list commands with this action."),
+				},
 			},
 			"post": oa.Operation{
-				Description: "Insert a new command into the queue.\n\nCurrently only `end_all_meetings` for a given backend is supported.\n\nExample: `{\"action\": \"end_all_meetings\", \"params\": {\"BackendID\": \"b056bc5e-372e-4562-b23a-bd6a92634e7b\"}}`",
+				Description: "Insert a new command into the queue.\n\nCurrently only `end_all_meetings` for a given backend, `reconcile_meetings` and `collect_garbage` are supported. The command is not processed before `not_before`.\n\nExample: `{\"action\": \"end_all_meetings\", \"params\": {\"BackendID\": \"b056bc5e-372e-4562-b23a-bd6a92634e7b\"}}`",
 				OperationID: "commandsCreate",
 				Summary:     "Create",
 				Tags:        []string{"Commands"},
@@ -331,6 +426,100 @@ func NewCommandsAPISchema() map[string]oa.Path {
 					"404": oa.ResponseRef("NotFoundError"),
 				},
 			},
+			"delete": oa.Operation{
+				Description: "Cancel a pending command. A running command is asked to stop and `cancel_requested` is set.",
+				OperationID: "commandsCancel",
+				Summary:     "Cancel",
+				Tags:        []string{"Commands"},
+				Responses: oa.ResponseRefs{
+					"200": oa.ResponseRef("Command"),
+					"400": oa.ResponseRef("BadRequest"),
+					"401": oa.ResponseRef("InvalidJWTError"),
+					"404": oa.ResponseRef("NotFoundError"),
+				},
+			},
+		},
+		"/v1/commands/schedules": oa.Path{
+			"get": oa.Operation{
+				Description: "Fetch all schedules of recurring commands.",
+				OperationID: "commandSchedulesList",
+				Summary:     "List Schedules",
+				Tags:        []string{"Commands"},
+				Responses: oa.ResponseRefs{
+					"200": oa.ResponseRef("CommandSchedules"),
+					"400": oa.ResponseRef("BadRequest"),
+					"401": oa.ResponseRef("InvalidJWTError"),
+				},
+				Parameters: []oa.Schema{
+					oa.ParamQuery(
+						"action",
+						"Only list schedules with this action."),
+				},
+			},
+			"post": oa.Operation{
+				Description: "Queue a command periodically. The schedule is a cron expression like `0 2 * * *`, evaluated in the local time of the server.",
+				OperationID: "commandSchedulesCreate",
+				Summary:     "Create Schedule",
+				Tags:        []string{"Commands"},
+				RequestBody: &oa.Request{
+					Content: map[string]oa.MediaType{
+						oa.ApplicationJSON: oa.MediaType{
+							Schema: oa.SchemaRef("CommandScheduleRequest"),
+						},
+					},
+				},
+				Responses: oa.ResponseRefs{
+					"200": oa.ResponseRef("CommandSchedule"),
+					"400": oa.ResponseRef("BadRequest"),
+					"401": oa.ResponseRef("InvalidJWTError"),
+				},
+			},
+		},
+		"/v1/commands/schedules/{id}": oa.Path{
+			"parameters": []oa.Schema{
+				oa.ParamID(),
+			},
+			"get": oa.Operation{
+				Description: "Fetch a single command schedule identified by ID.",
+				OperationID: "commandSchedulesRead",
+				Summary:     "Read Schedule",
+				Tags:        []string{"Commands"},
+				Responses: oa.ResponseRefs{
+					"200": oa.ResponseRef("CommandSchedule"),
+					"400": oa.ResponseRef("BadRequest"),
+					"401": oa.ResponseRef("InvalidJWTError"),
+					"404": oa.ResponseRef("NotFoundError"),
+				},
+			},
+			"delete": oa.Operation{
+				Description: "Remove a command schedule. Commands already queued are not canceled.",
+				OperationID: "commandSchedulesDestroy",
+				Summary:     "Delete Schedule",
+				Tags:        []string{"Commands"},
+				Responses: oa.ResponseRefs{
+					"200": oa.ResponseRef("CommandSchedule"),
+					"400": oa.ResponseRef("BadRequest"),
+					"401": oa.ResponseRef("InvalidJWTError"),
+					"404": oa.ResponseRef("NotFoundError"),
+				},
+			},
+		},
+		"/v1/commands/{id}/requeue": oa.Path{
+			"parameters": []oa.Schema{
+				oa.ParamID(),
+			},
+			"post": oa.Operation{
+				Description: "Put a failed or dead command back into the queue. The attempts are reset.",
+				OperationID: "commandsRequeue",
+				Summary:     "Requeue",
+				Tags:        []string{"Commands"},
+				Responses: oa.ResponseRefs{
+					"202": oa.ResponseRef("Command"),
+					"400": oa.ResponseRef("BadRequest"),
+					"401": oa.ResponseRef("InvalidJWTError"),
+					"404": oa.ResponseRef("NotFoundError"),
+				},
+			},
 		},
 	}
 }
@@ -437,6 +626,310 @@ func NewRecordingsVisibilityAPISchema() map[string]oa.Path {
 	}
 }
 
+// NewMeetingsHistoryAPISchema creates the schema for
+// the archive of past meetings
+func NewMeetingsHistoryAPISchema() map[string]oa.Path {
+	return map[string]oa.Path{
+		"/v1/meetings-history": oa.Path{
+			"get": oa.Operation{
+				Description: "Fetch the archived meetings running in a time range and the daily or hourly aggregates: the number of meetings started, the peak concurrency and the average duration.",
+				OperationID: "meetingsHistoryList",
+				Summary:     "History",
+				Tags:        []string{"Meetings"},
+				Parameters: []oa.Schema{
+					oa.ParamQuery(
+						"frontend_id",
+						"Filter by frontend-id."),
+					oa.ParamQuery(
+						"frontend_key",
+						"Filter by frontend-key."),
+					oa.ParamQuery(
+						"backend_id",
+						"Filter by backend-id."),
+					oa.ParamQuery(
+						"backend_host",
+						"Filter by backend host."),
+					oa.ParamQuery(
+						"from",
+						"Start of the range as date or RFC3339 timestamp. Defaults to 7 days ago."),
+					oa.ParamQuery(
+						"to",
+						"End of the range as date or RFC3339 timestamp. Defaults to now."),
+					oa.ParamQuery(
+						"resolution",
+						"Either `daily` (default) or `hourly`."),
+					oa.ParamQuery(
+						"summary",
+						"If `true`, only the aggregates are returned."),
+				},
+				Responses: oa.ResponseRefs{
+					"200": oa.ResponseRef("MeetingsHistory"),
+					"400": oa.ResponseRef("BadRequest"),
+					"401": oa.ResponseRef("InvalidJWTError"),
+					"404": oa.ResponseRef("NotFoundError"),
+				},
+			},
+		},
+	}
+}
+
+// NewUsageAPISchema creates the schema for
+// the usage ledger export
+func NewUsageAPISchema() map[string]oa.Path {
+	return map[string]oa.Path{
+		"/v1/usage": oa.Path{
+			"get": oa.Operation{
+				Description: "Export the usage ledger of meetings started in a date range.\n\nUsers can only export the usage of the frontends of their account.",
+				OperationID: "usageList",
+				Summary:     "Export",
+				Tags:        []string{"Usage"},
+				Parameters: []oa.Schema{
+					oa.ParamQuery(
+						"frontend_id",
+						"Filter by frontend-id."),
+					oa.ParamQuery(
+						"frontend_key",
+						"Filter by frontend-key."),
+					oa.ParamQuery(
+						"from",
+						"Start of the range as date or RFC3339 timestamp. Defaults to the start of the current month."),
+					oa.ParamQuery(
+						"to",
+						"End of the range (exclusive) as date or RFC3339 timestamp. Defaults to now."),
+					oa.ParamQuery(
+						"format",
+						"Either `json` (default) or `csv`."),
+				},
+				Responses: oa.ResponseRefs{
+					"200": oa.ResponseRef("UsageRecords"),
+					"400": oa.ResponseRef("BadRequest"),
+					"401": oa.ResponseRef("InvalidJWTError"),
+					"404": oa.ResponseRef("NotFoundError"),
+				},
+			},
+		},
+	}
+}
+
+// NewRoutingAPISchema creates the schema for
+// inspecting the backend selection
+func NewRoutingAPISchema() map[string]oa.Path {
+	return map[string]oa.Path{
+		"/v1/routing/simulate": oa.Path{
+			"post": oa.Operation{
+				Description: "Select a backend for a create request of a frontend, without creating a meeting. The routing middlewares are applied to the current backend states.",
+				OperationID: "routingSimulate",
+				Summary:     "Simulate",
+				Tags:        []string{"Routing"},
+				RequestBody: &oa.Request{
+					Content: map[string]oa.MediaType{
+						oa.ApplicationJSON: oa.MediaType{
+							Schema: oa.SchemaRef("RoutingSimulation"),
+						},
+					},
+				},
+				Responses: oa.ResponseRefs{
+					"200": oa.ResponseRef("RoutingRecord"),
+					"400": oa.ResponseRef("BadRequest"),
+					"401": oa.ResponseRef("InvalidJWTError"),
+					"404": oa.ResponseRef("NotFoundError"),
+				},
+			},
+		},
+	}
+}
+
+// NewMaintenanceWindowsAPISchema generates the endpoints
+// for scheduling maintenance of backends
+func NewMaintenanceWindowsAPISchema() map[string]oa.Path {
+	return map[string]oa.Path{
+		"/v1/maintenance-windows": oa.Path{
+			"get": oa.Operation{
+				Description: "Fetch maintenance windows.",
+				OperationID: "maintenanceWindowsList",
+				Summary:     "List",
+				Tags:        []string{"Maintenance"},
+				Responses: oa.ResponseRefs{
+					"200": oa.ResponseRef("MaintenanceWindows"),
+					"400": oa.ResponseRef("BadRequest"),
+					"401": oa.ResponseRef("InvalidJWTError"),
+				},
+				Parameters: []oa.Schema{
+					oa.ParamQuery(
+						"backend_id",
+						"List maintenance windows of this backend."),
+					oa.ParamQuery(
+						"state",
+						"List maintenance windows in this state."),
+				},
+			},
+			"post": oa.Operation{
+				Description: "Schedule a maintenance window for a backend. At the start the admin state of the backend is set to `stopped` or `draining`, at the end the backend is `ready` again.",
+				OperationID: "maintenanceWindowsCreate",
+				Summary:     "Create",
+				Tags:        []string{"Maintenance"},
+				RequestBody: &oa.Request{
+					Content: map[string]oa.MediaType{
+						oa.ApplicationJSON: oa.MediaType{
+							Schema: oa.SchemaRef("MaintenanceWindowRequest"),
+						},
+					},
+				},
+				Responses: oa.ResponseRefs{
+					"200": oa.ResponseRef("MaintenanceWindow"),
+					"400": oa.ResponseRef("BadRequest"),
+					"401": oa.ResponseRef("InvalidJWTError"),
+				},
+			},
+		},
+		"/v1/maintenance-windows/{id}": oa.Path{
+			"parameters": []oa.Schema{
+				oa.ParamID(),
+			},
+			"get": oa.Operation{
+				Description: "Fetch a single maintenance window identified by ID.",
+				OperationID: "maintenanceWindowsRead",
+				Summary:     "Read",
+				Tags:        []string{"Maintenance"},
+				Responses: oa.ResponseRefs{
+					"200": oa.ResponseRef("MaintenanceWindow"),
+					"400": oa.ResponseRef("BadRequest"),
+					"401": oa.ResponseRef("InvalidJWTError"),
+					"404": oa.ResponseRef("NotFoundError"),
+				},
+			},
+			"delete": oa.Operation{
+				Description: "Cancel a maintenance window. If the window is active, the backend will be `ready` again.",
+				OperationID: "maintenanceWindowsCancel",
+				Summary:     "Cancel",
+				Tags:        []string{"Maintenance"},
+				Responses: oa.ResponseRefs{
+					"200": oa.ResponseRef("MaintenanceWindow"),
+					"400": oa.ResponseRef("BadRequest"),
+					"401": oa.ResponseRef("InvalidJWTError"),
+					"404": oa.ResponseRef("NotFoundError"),
+				},
+			},
+		},
+	}
+}
+
+// NewWebhooksAPISchema generates the endpoints
+// for subscribing to cluster events
+func NewWebhooksAPISchema() map[string]oa.Path {
+	return map[string]oa.Path{
+		"/v1/webhooks": oa.Path{
+			"get": oa.Operation{
+				Description: "Fetch webhooks.",
+				OperationID: "webhooksList",
+				Summary:     "List",
+				Tags:        []string{"Webhooks"},
+				Responses: oa.ResponseRefs{
+					"200": oa.ResponseRef("Webhooks"),
+					"400": oa.ResponseRef("BadRequest"),
+					"401": oa.ResponseRef("InvalidJWTError"),
+				},
+				Parameters: []oa.Schema{
+					oa.ParamQuery(
+						"frontend_id",
+						"List webhooks of this frontend."),
+				},
+			},
+			"post": oa.Operation{
+				Description: "Subscribe to cluster events. The events are posted as JSON to the URL, signed with the secret. If no secret is provided, a random secret is generated.",
+				OperationID: "webhooksCreate",
+				Summary:     "Create",
+				Tags:        []string{"Webhooks"},
+				RequestBody: &oa.Request{
+					Content: map[string]oa.MediaType{
+						oa.ApplicationJSON: oa.MediaType{
+							Schema: oa.SchemaRef("WebhookRequest"),
+						},
+					},
+				},
+				Responses: oa.ResponseRefs{
+					"200": oa.ResponseRef("Webhook"),
+					"400": oa.ResponseRef("BadRequest"),
+					"401": oa.ResponseRef("InvalidJWTError"),
+				},
+			},
+		},
+		"/v1/webhooks/{id}": oa.Path{
+			"parameters": []oa.Schema{
+				oa.ParamID(),
+			},
+			"get": oa.Operation{
+				Description: "Fetch a single webhook identified by ID.",
+				OperationID: "webhooksRead",
+				Summary:     "Read",
+				Tags:        []string{"Webhooks"},
+				Responses: oa.ResponseRefs{
+					"200": oa.ResponseRef("Webhook"),
+					"400": oa.ResponseRef("BadRequest"),
+					"401": oa.ResponseRef("InvalidJWTError"),
+					"404": oa.ResponseRef("NotFoundError"),
+				},
+			},
+			"patch": oa.Operation{
+				Description: "Update parts of a webhook.",
+				OperationID: "webhooksPatch",
+				Summary:     "Update",
+				Tags:        []string{"Webhooks"},
+				RequestBody: &oa.Request{
+					Content: map[string]oa.MediaType{
+						oa.ApplicationJSON: oa.MediaType{
+							Schema: oa.SchemaRef("WebhookRequest"),
+						},
+					},
+				},
+				Responses: oa.ResponseRefs{
+					"200": oa.ResponseRef("Webhook"),
+					"400": oa.ResponseRef("BadRequest"),
+					"401": oa.ResponseRef("InvalidJWTError"),
+					"404": oa.ResponseRef("NotFoundError"),
+				},
+			},
+			"delete": oa.Operation{
+				Description: "Remove a webhook. Pending deliveries are dropped.",
+				OperationID: "webhooksDestroy",
+				Summary:     "Delete",
+				Tags:        []string{"Webhooks"},
+				Responses: oa.ResponseRefs{
+					"200": oa.ResponseRef("Webhook"),
+					"400": oa.ResponseRef("BadRequest"),
+					"401": oa.ResponseRef("InvalidJWTError"),
+					"404": oa.ResponseRef("NotFoundError"),
+				},
+			},
+		},
+	}
+}
+
+// NewEventsAPISchema generates the endpoint
+// for streaming live cluster events
+func NewEventsAPISchema() map[string]oa.Path {
+	return map[string]oa.Path{
+		"/v1/events/stream": oa.Path{
+			"get": oa.Operation{
+				Description: "Stream the events of the cluster as server-sent events. Admins receive all events, users only receive the events of the frontends of their account.\n\nIn addition to the webhook events, the stream includes `backend.heartbeat` and `command.state_changed` events, which are only visible to admins.",
+				OperationID: "eventsStream",
+				Summary:     "Stream",
+				Tags:        []string{"Events"},
+				Responses: oa.ResponseRefs{
+					"200": oa.ResponseRef("EventsStream"),
+					"400": oa.ResponseRef("BadRequest"),
+					"401": oa.ResponseRef("InvalidJWTError"),
+				},
+				Parameters: []oa.Schema{
+					oa.ParamQuery(
+						"types",
+						"Only stream events of these types, separated by comma."),
+				},
+			},
+		},
+	}
+}
+
 // NewAgentAPISchema creates the API schema for the node agent
 func NewAgentAPISchema() map[string]oa.Path {
 	return map[string]oa.Path{
@@ -537,10 +1030,16 @@ func NewAPIEndpointsSchema() map[string]oa.Path {
 		NewFrontendsAPISchema(),
 		NewBackendsAPISchema(),
 		NewMeetingsAPISchema(),
+		NewMeetingsHistoryAPISchema(),
 		NewCommandsAPISchema(),
 		NewRecordingsAPISchema(),
 		NewRecordingsVisibilityAPISchema(),
 		NewRecordingsImportAPISchema(),
+		NewUsageAPISchema(),
+		NewRoutingAPISchema(),
+		NewMaintenanceWindowsAPISchema(),
+		NewWebhooksAPISchema(),
+		NewEventsAPISchema(),
 		NewAgentAPISchema(),
 		NewCtrlEndpointsSchema(),
 	)
@@ -574,6 +1073,33 @@ func NewAPIResponses() map[string]oa.Response {
 				},
 			},
 		},
+		"FrontendUsage": oa.Response{
+			Description: "Frontend Usage",
+			Content: map[string]oa.MediaType{
+				oa.ApplicationJSON: oa.MediaType{
+					Schema: oa.SchemaRef("FrontendUsage"),
+				},
+			},
+		},
+		"MeetingsHistory": oa.Response{
+			Description: "Meetings History",
+			Content: map[string]oa.MediaType{
+				oa.ApplicationJSON: oa.MediaType{
+					Schema: oa.SchemaRef("MeetingsHistory"),
+				},
+			},
+		},
+		"UsageRecords": oa.Response{
+			Description: "Usage Ledger",
+			Content: map[string]oa.MediaType{
+				oa.ApplicationJSON: oa.MediaType{
+					Schema: oa.SchemaRef("UsageRecords"),
+				},
+				"text/csv": oa.MediaType{
+					Schema: oa.Schema{"type": "string"},
+				},
+			},
+		},
 		"Backends": oa.Response{
 			Description: "List of Backends",
 			Content: map[string]oa.MediaType{
@@ -607,7 +1133,55 @@ func NewAPIResponses() map[string]oa.Response {
 				},
 			},
 		},
+		"RoutingRecord": oa.Response{
+			Description: "Routing Record",
+			Content: map[string]oa.MediaType{
+				oa.ApplicationJSON: oa.MediaType{
+					Schema: oa.SchemaRef("RoutingRecord"),
+				},
+			},
+		},
 
+		"MaintenanceWindows": oa.Response{
+			Description: "List of Maintenance Windows",
+			Content: map[string]oa.MediaType{
+				oa.ApplicationJSON: oa.MediaType{
+					Schema: oa.SchemaRef("MaintenanceWindows"),
+				},
+			},
+		},
+		"MaintenanceWindow": oa.Response{
+			Description: "Maintenance Window",
+			Content: map[string]oa.MediaType{
+				oa.ApplicationJSON: oa.MediaType{
+					Schema: oa.SchemaRef("MaintenanceWindow"),
+				},
+			},
+		},
+		"Webhooks": oa.Response{
+			Description: "List of Webhooks",
+			Content: map[string]oa.MediaType{
+				oa.ApplicationJSON: oa.MediaType{
+					Schema: oa.SchemaRef("Webhooks"),
+				},
+			},
+		},
+		"EventsStream": oa.Response{
+			Description: "Stream of Events. The data of each event is encoded as JSON.",
+			Content: map[string]oa.MediaType{
+				"text/event-stream": oa.MediaType{
+					Schema: oa.SchemaRef("Event"),
+				},
+			},
+		},
+		"Webhook": oa.Response{
+			Description: "Webhook",
+			Content: map[string]oa.MediaType{
+				oa.ApplicationJSON: oa.MediaType{
+					Schema: oa.SchemaRef("Webhook"),
+				},
+			},
+		},
 		"Commands": oa.Response{
 			Description: "List of Commands",
 			Content: map[string]oa.MediaType{
@@ -624,6 +1198,22 @@ func NewAPIResponses() map[string]oa.Response {
 				},
 			},
 		},
+		"CommandSchedules": oa.Response{
+			Description: "List of Command Schedules",
+			Content: map[string]oa.MediaType{
+				oa.ApplicationJSON: oa.MediaType{
+					Schema: oa.SchemaRef("CommandSchedules"),
+				},
+			},
+		},
+		"CommandSchedule": oa.Response{
+			Description: "Command Schedule",
+			Content: map[string]oa.MediaType{
+				oa.ApplicationJSON: oa.MediaType{
+					Schema: oa.SchemaRef("CommandSchedule"),
+				},
+			},
+		},
 
 		"Recording": oa.Response{
 			Description: "Recording",
@@ -729,6 +1319,10 @@ func NewAPISchemas() map[string]oa.Schema {
 			"Frontend",
 			store.FrontendState{}).
 			RequireFrom(store.FrontendState{}),
+		"FrontendUsage": oa.ObjectSchema(
+			"Frontend Usage",
+			store.FrontendUsage{}).
+			RequireFrom(store.FrontendUsage{}),
 		"FrontendConfig": oa.ObjectSchema(
 			"A BBB frontend configuration",
 			bbb.Frontend{}).
@@ -752,6 +1346,31 @@ func NewAPISchemas() map[string]oa.Schema {
 			"Recordings Settings",
 			store.RecordingsSettings{}).
 			RequireFrom(store.RecordingsSettings{}),
+		"RoutingHintsSettings": oa.ObjectSchema(
+			"Routing Hints Settings",
+			store.RoutingHintsSettings{}).
+			RequireFrom(store.RoutingHintsSettings{}),
+
+		"MeetingsHistory": oa.ObjectSchema(
+			"Meetings History",
+			MeetingsHistoryResponse{}).
+			Require("from", "to", "resolution", "aggregates"),
+		"MeetingHistoryEntry": oa.ObjectSchema(
+			"Archived Meeting",
+			store.MeetingHistoryEntry{}).
+			RequireFrom(store.MeetingHistoryEntry{}),
+		"MeetingsHistoryBucket": oa.ObjectSchema(
+			"Meetings History Aggregate",
+			store.MeetingsHistoryBucket{}).
+			RequireFrom(store.MeetingsHistoryBucket{}),
+
+		"UsageRecords": oa.ArraySchema(
+			"Usage Ledger",
+			oa.SchemaRef("UsageRecord")),
+		"UsageRecord": oa.ObjectSchema(
+			"Usage of a meeting",
+			store.UsageRecord{}).
+			RequireFrom(store.UsageRecord{}),
 
 		"Backends": oa.ArraySchema(
 			"List of Backends",
@@ -776,6 +1395,13 @@ func NewAPISchemas() map[string]oa.Schema {
 			"Backend Settings ",
 			store.BackendSettings{}).
 			RequireFrom(store.BackendSettings{}),
+		"StressWeights": oa.ObjectSchema(
+			"Stress Weights",
+			store.StressWeights{}),
+		"DrainProgress": oa.ObjectSchema(
+			"Drain Progress",
+			store.DrainProgress{}).
+			RequireFrom(store.DrainProgress{}),
 
 		"Meetings": oa.ArraySchema(
 			"List of Meetings",
@@ -797,6 +1423,27 @@ func NewAPISchemas() map[string]oa.Schema {
 		"MeetingInfoPatch": oa.ObjectSchema(
 			"Meeting Info",
 			bbb.Meeting{}),
+		"RoutingRecord": oa.ObjectSchema(
+			"Routing Record",
+			store.RoutingRecord{}).
+			RequireFrom(store.RoutingRecord{}).
+			Nullable("backend_id"),
+		"RoutingSimulation": oa.ObjectSchema(
+			"Routing Simulation",
+			RoutingSimulation{}).
+			Require("frontend_key"),
+		"RoutingStep": oa.ObjectSchema(
+			"Routing Step",
+			store.RoutingStep{}).
+			RequireFrom(store.RoutingStep{}),
+		"RoutingRemoval": oa.ObjectSchema(
+			"Routing Removal",
+			store.RoutingRemoval{}).
+			RequireFrom(store.RoutingRemoval{}),
+		"RoutingCandidate": oa.ObjectSchema(
+			"Routing Candidate",
+			store.RoutingCandidate{}).
+			RequireFrom(store.RoutingCandidate{}),
 		"Attendee": oa.ObjectSchema(
 			"Meeting Attendee",
 			bbb.Attendee{}).
@@ -806,6 +1453,36 @@ func NewAPISchemas() map[string]oa.Schema {
 			bbb.Breakout{}).
 			RequireFrom(bbb.Breakout{}),
 
+		"MaintenanceWindows": oa.ArraySchema(
+			"List of Maintenance Windows",
+			oa.SchemaRef("MaintenanceWindow")),
+		"MaintenanceWindow": oa.ObjectSchema(
+			"Maintenance Window",
+			store.MaintenanceWindow{}).
+			RequireFrom(store.MaintenanceWindow{}),
+		"MaintenanceWindowRequest": oa.ObjectSchema(
+			"Maintenance Window Request",
+			store.MaintenanceWindow{}).
+			Only("backend_id", "admin_state", "comment", "starts_at", "ends_at").
+			Require("backend_id", "starts_at", "ends_at"),
+		"Webhooks": oa.ArraySchema(
+			"List of Webhooks",
+			oa.SchemaRef("Webhook")),
+		"Webhook": oa.ObjectSchema(
+			"Webhook",
+			store.Webhook{}).
+			RequireFrom(store.Webhook{}).
+			Nullable("frontend_id"),
+		"Event": oa.ObjectSchema(
+			"Event",
+			cluster.Event{}).
+			RequireFrom(cluster.Event{}).
+			Nullable("frontend_id", "backend_id"),
+		"WebhookRequest": oa.ObjectSchema(
+			"Webhook Request",
+			store.Webhook{}).
+			Only("url", "secret", "events", "frontend_id", "active", "comment").
+			Require("url"),
 		"Commands": oa.ArraySchema(
 			"List of Commands",
 			oa.SchemaRef("Command")),
@@ -813,12 +1490,25 @@ func NewAPISchemas() map[string]oa.Schema {
 			"Command",
 			store.Command{}).
 			RequireFrom(store.Command{}).
-			Nullable("result", "started_at", "stopped_at"),
+			Nullable("result", "idempotency_key", "last_error", "started_at", "stopped_at"),
 		"CommandRequest": oa.ObjectSchema(
 			"Command Request",
 			store.Command{}).
-			Only("action", "params").
+			Only("action", "params", "idempotency_key", "not_before").
 			Require("action", "params"),
+		"CommandSchedules": oa.ArraySchema(
+			"List of Command Schedules",
+			oa.SchemaRef("CommandSchedule")),
+		"CommandSchedule": oa.ObjectSchema(
+			"Command Schedule",
+			store.CommandSchedule{}).
+			RequireFrom(store.CommandSchedule{}).
+			Nullable("params", "last_run_at"),
+		"CommandScheduleRequest": oa.ObjectSchema(
+			"Command Schedule Request",
+			store.CommandSchedule{}).
+			Only("action", "params", "schedule", "comment").
+			Require("action", "schedule"),
 
 		"Recording": oa.ObjectSchema(
 			"Recording",
@@ -1048,6 +1738,22 @@ func NewAPISpec() *oa.Spec {
 				Name:        "Commands",
 				Description: "The commands API is used queue asynchronous commands. Currently only `end_all_meetings` for a given backend is supported.",
 			},
+			{
+				Name:        "Routing",
+				Description: "Inspect the selection of backends when meetings are created.",
+			},
+			{
+				Name:        "Maintenance",
+				Description: "Schedule maintenance of backends ahead of time.",
+			},
+			{
+				Name:        "Webhooks",
+				Description: "Subscribe to events in the cluster. The events are posted as signed JSON requests.",
+			},
+			{
+				Name:        "Events",
+				Description: "Follow the state of the cluster live as server-sent events.",
+			},
 			{
 				Name:        "Agent",
 				Description: "This API is used by the agent, running on each node.",
@@ -3,7 +3,9 @@ package api
 import (
 	"context"
 	"fmt"
+	"net/http"
 	"strings"
+	"time"
 
 	"github.com/b3scale/b3scale/pkg/http/auth"
 	"github.com/b3scale/b3scale/pkg/store"
@@ -126,10 +128,74 @@ func FrontendFromQueryParams(
 		}
 	}
 
-	// Check if we could find a frontend
-	if fe == nil {
+	// Check if we could find a frontend. Users may only
+	// access the frontends of their account.
+	if fe == nil || !canAccessFrontend(api, fe) {
 		return nil, fmt.Errorf("a frontend could not be found")
 	}
 
 	return fe, nil
 }
+
+// FrontendFromRequest resolves the frontend identified
+// by the ID in the path. Users may only access the
+// frontends matching their account ref.
+func FrontendFromRequest(
+	ctx context.Context,
+	api *API,
+	tx pgx.Tx,
+) (*store.FrontendState, error) {
+	fe, err := store.GetFrontendStateByID(ctx, tx, api.Param("id"))
+	if err != nil {
+		return nil, err
+	}
+	if fe == nil || !canAccessFrontend(api, fe) {
+		return nil, echo.ErrNotFound
+	}
+	return fe, nil
+}
+
+// canAccessFrontend checks if the frontend is accessible
+// by the account of the request. Admins can access all
+// frontends.
+func canAccessFrontend(api *API, fe *store.FrontendState) bool {
+	if api.HasScope(auth.ScopeAdmin) {
+		return true
+	}
+	return fe.AccountRef != nil && *fe.AccountRef == api.Ref
+}
+
+// parseTimeParam accepts a date or a RFC3339 timestamp
+func parseTimeParam(value string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t.UTC(), nil
+	}
+	return time.Parse("2006-01-02", value)
+}
+
+// TimeRangeFromQueryParams reads the time range from
+// the `from` and `to` query parameters. If a parameter
+// is not present, the default is used.
+func TimeRangeFromQueryParams(
+	api *API,
+	from time.Time,
+	to time.Time,
+) (time.Time, time.Time, error) {
+	if value := api.QueryParam("from"); value != "" {
+		t, err := parseTimeParam(value)
+		if err != nil {
+			return from, to, echo.NewHTTPError(
+				http.StatusBadRequest, "invalid from: "+err.Error())
+		}
+		from = t
+	}
+	if value := api.QueryParam("to"); value != "" {
+		t, err := parseTimeParam(value)
+		if err != nil {
+			return from, to, echo.NewHTTPError(
+				http.StatusBadRequest, "invalid to: "+err.Error())
+		}
+		to = t
+	}
+	return from, to, nil
+}
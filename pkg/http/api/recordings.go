@@ -9,7 +9,10 @@ import (
 	"strings"
 	"time"
 
+	sq "github.com/Masterminds/squirrel"
+
 	"github.com/b3scale/b3scale/pkg/bbb"
+	"github.com/b3scale/b3scale/pkg/cluster"
 	"github.com/b3scale/b3scale/pkg/config"
 	"github.com/b3scale/b3scale/pkg/http/auth"
 	"github.com/b3scale/b3scale/pkg/middlewares/requests"
@@ -227,6 +230,16 @@ func apiRecordingsImport(
 	if err := state.Save(ctx, tx); err != nil {
 		return err
 	}
+	if err := store.MarkUsageRecorded(
+		ctx, tx, state.InternalMeetingID,
+	); err != nil {
+		return err
+	}
+	if err := cluster.EmitEvent(
+		ctx, tx, cluster.NewRecordingEvent(state),
+	); err != nil {
+		return err
+	}
 	if err := tx.Commit(ctx); err != nil {
 		return err
 	}
@@ -420,12 +433,27 @@ func apiProtectedRecordingsAuth(c echo.Context) error {
 var ResourceRecordings = &Resource{
 	List: RequireScope(
 		auth.ScopeAdmin,
+		auth.ScopeUser,
 	)(apiRecordingsList),
 	Show: RequireScope(
 		auth.ScopeAdmin,
+		auth.ScopeUser,
 	)(apiRecordingsShow),
 }
 
+// Internal: Select recordings accessible by the
+// API user. Users may only access the recordings
+// of the frontends of their account.
+func recordingsQuery(api *API) sq.SelectBuilder {
+	q := store.Q()
+	if api.HasScope(auth.ScopeAdmin) {
+		return q
+	}
+	return q.
+		Join("frontends ON frontends.id = recordings.frontend_id").
+		Where("frontends.account_ref = ?", api.Ref)
+}
+
 // API: Recordings list endpoint
 func apiRecordingsList(
 	ctx context.Context,
@@ -466,7 +494,7 @@ func apiRecordingsShow(
 	id := api.Param("id") // RecordID
 
 	// Get recording by ID
-	rec, err := store.GetRecordingState(ctx, tx, store.Q().
+	rec, err := store.GetRecordingState(ctx, tx, recordingsQuery(api).
 		Where("recordings.record_id = ?", id))
 	if err != nil {
 		return err
@@ -484,6 +512,7 @@ func apiRecordingsShow(
 var ResourceRecordingsVisibility = &Resource{
 	Create: RequireScope(
 		auth.ScopeAdmin,
+		auth.ScopeUser,
 	)(apiRecordingsVisibilityUpdate),
 }
 
@@ -516,7 +545,7 @@ func apiRecordingsVisibilityUpdate(
 	}
 
 	// Get recording for update
-	rec, err := store.GetRecordingState(ctx, tx, store.Q().
+	rec, err := store.GetRecordingState(ctx, tx, recordingsQuery(api).
 		Where("recordings.record_id = ?", recID))
 	if err != nil {
 		return err
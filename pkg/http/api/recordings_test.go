@@ -1,6 +1,45 @@
 package api
 
-import "testing"
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+
+	"github.com/b3scale/b3scale/pkg/bbb"
+	"github.com/b3scale/b3scale/pkg/http/auth"
+	"github.com/b3scale/b3scale/pkg/store"
+)
+
+// createTestRecording creates an unpublished
+// recording of the frontend.
+func createTestRecording(
+	api *API,
+	frontend *store.FrontendState,
+) *store.RecordingState {
+	ctx := api.Ctx()
+	tx, err := api.Conn.Begin(ctx)
+	if err != nil {
+		panic(err)
+	}
+	defer tx.Rollback(ctx) //nolint
+
+	rec := store.NewStateFromRecording(&bbb.Recording{
+		RecordID:          uuid.New().String(),
+		MeetingID:         uuid.New().String(),
+		InternalMeetingID: uuid.New().String(),
+		Metadata:          bbb.Metadata{},
+	})
+	rec.FrontendID = frontend.ID
+	if err := rec.Save(ctx, tx); err != nil {
+		panic(err)
+	}
+	if err := tx.Commit(ctx); err != nil {
+		panic(err)
+	}
+	return rec
+}
 
 func TestParseRecordIDPath(t *testing.T) {
 	path := "/playback/presentation/2.3/9b897750e3453b1daa4563788af47ef90e063aa3-1716030289891"
@@ -40,3 +79,84 @@ func TestParseRecordIDPath(t *testing.T) {
 	}
 
 }
+
+func TestRecordingsShow(t *testing.T) {
+	api, res := NewTestRequest().
+		Authorize("user23", auth.ScopeUser).
+		Context()
+	defer api.Release()
+
+	f := createTestFrontend(api)
+	rec := createTestRecording(api, f)
+
+	api.SetParamNames("id")
+	api.SetParamValues(rec.RecordID)
+
+	if err := api.Handle(apiRecordingsShow); err != nil {
+		t.Fatal(err)
+	}
+	if err := res.StatusOK(); err != nil {
+		t.Error(err)
+	}
+	data := res.JSON()
+	if data["record_id"] != rec.RecordID {
+		t.Error("unexpected recording:", data)
+	}
+}
+
+func TestRecordingsShowOtherAccount(t *testing.T) {
+	api, _ := NewTestRequest().
+		Authorize("user23", auth.ScopeUser).
+		Context()
+	defer api.Release()
+
+	other := createTestAccountFrontend(api, "user42")
+	rec := createTestRecording(api, other)
+
+	api.SetParamNames("id")
+	api.SetParamValues(rec.RecordID)
+
+	err := api.Handle(apiRecordingsShow)
+	if !errors.Is(err, echo.ErrNotFound) {
+		t.Error("expected not found for a recording of another account, got:", err)
+	}
+}
+
+func TestRecordingsVisibilityUpdateOtherAccount(t *testing.T) {
+	api, _ := NewTestRequest().Context()
+	defer api.Release()
+
+	other := createTestAccountFrontend(api, "user42")
+	rec := createTestRecording(api, other)
+
+	api, _ = NewTestRequest().
+		KeepState().
+		Authorize("user23", auth.ScopeUser).
+		JSON(&RecordingVisibilityUpdate{
+			RecordID:   rec.RecordID,
+			Visibility: bbb.RecordingVisibilityPublished,
+		}).
+		Context()
+	defer api.Release()
+
+	err := api.Handle(apiRecordingsVisibilityUpdate)
+	if !errors.Is(err, echo.ErrBadRequest) {
+		t.Error("expected bad request for a recording of another account, got:", err)
+	}
+
+	// The recording must not be changed
+	ctx := api.Ctx()
+	tx, err := api.Conn.Begin(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tx.Rollback(ctx) //nolint
+
+	next, err := store.GetRecordingStateByID(ctx, tx, rec.RecordID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if next.Recording.Published {
+		t.Error("recording of another account should not be published")
+	}
+}
@@ -0,0 +1,122 @@
+package api
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/b3scale/b3scale/pkg/bbb"
+	"github.com/b3scale/b3scale/pkg/cluster"
+	"github.com/b3scale/b3scale/pkg/http/auth"
+	"github.com/b3scale/b3scale/pkg/middlewares/requests"
+	"github.com/b3scale/b3scale/pkg/store"
+)
+
+// RoutingSimulation requests selecting a backend
+// for a create request of a frontend, without
+// creating a meeting.
+type RoutingSimulation struct {
+	FrontendKey string     `json:"frontend_key" doc:"The key of the frontend creating the meeting."`
+	Params      bbb.Params `json:"params" doc:"The parameters of the create request, including routing hints passed as meta parameters."`
+}
+
+// Validate checks for presence of required fields.
+func (s *RoutingSimulation) Validate() store.ValidationError {
+	err := store.ValidationError{}
+	if s.FrontendKey == "" {
+		err.Add("frontend_key", store.ErrFieldRequired)
+	}
+	if len(err) > 0 {
+		return err
+	}
+	return nil
+}
+
+// NewResourceRoutingSimulate creates the endpoint for
+// simulating the backend selection with the router.
+func NewResourceRoutingSimulate(router *cluster.Router) *Resource {
+	return &Resource{
+		Create: RequireScope(
+			auth.ScopeAdmin,
+		)(apiRoutingSimulate(router)),
+	}
+}
+
+// simulateCreateRequestMiddlewares wraps the handler with the
+// request middlewares of the gateway, that change the parameters
+// of a create request before the backend is selected.
+func simulateCreateRequestMiddlewares(
+	handler cluster.RequestHandler,
+) cluster.RequestHandler {
+	middlewares := []cluster.RequestMiddleware{
+		requests.SetCreateParams(),
+		requests.SetMetaFrontend(),
+	}
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		handler = middlewares[i](handler)
+	}
+	return handler
+}
+
+// apiRoutingSimulate runs the routing middleware chain
+// against the current backend states and responds
+// with the routing record.
+func apiRoutingSimulate(router *cluster.Router) ResourceHandler {
+	return func(ctx context.Context, api *API) error {
+		sim := &RoutingSimulation{}
+		if err := api.Bind(sim); err != nil {
+			return err
+		}
+		if err := sim.Validate(); err != nil {
+			return err
+		}
+
+		tx, err := api.Conn.Begin(ctx)
+		if err != nil {
+			return err
+		}
+		defer tx.Rollback(ctx) //nolint
+
+		state, err := store.GetFrontendState(ctx, tx, store.Q().
+			Where("key = ?", sim.FrontendKey))
+		if err != nil {
+			return err
+		}
+		if state == nil {
+			return echo.ErrNotFound
+		}
+		if err := tx.Rollback(ctx); err != nil {
+			return err
+		}
+
+		params := sim.Params
+		if params == nil {
+			params = bbb.Params{}
+		}
+		req := bbb.CreateRequest(params, nil).
+			WithFrontend(state.Frontend)
+
+		trace := cluster.NewRoutingSimulationTrace()
+		ctx = store.ContextWithConnection(ctx, api.Conn)
+		ctx = cluster.ContextWithFrontend(ctx, cluster.NewFrontend(state))
+		ctx = cluster.ContextWithRoutingTrace(ctx, trace)
+
+		// The create request is prepared like in the gateway,
+		// so hints from the frontend defaults are applied.
+		selectBackend := simulateCreateRequestMiddlewares(
+			func(ctx context.Context, req *bbb.Request) (bbb.Response, error) {
+				_, err := router.SelectBackend(ctx, req)
+				return nil, err
+			})
+
+		// Routing errors are part of the record
+		record := trace.Record()
+		if _, err := selectBackend(ctx, req); err != nil &&
+			record.Error == "" {
+			return err
+		}
+
+		return api.JSON(http.StatusOK, record)
+	}
+}
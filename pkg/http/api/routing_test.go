@@ -0,0 +1,93 @@
+package api
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/b3scale/b3scale/pkg/bbb"
+	"github.com/b3scale/b3scale/pkg/cluster"
+	"github.com/b3scale/b3scale/pkg/http/auth"
+	"github.com/b3scale/b3scale/pkg/middlewares/routing"
+	"github.com/b3scale/b3scale/pkg/store"
+)
+
+func TestRoutingSimulate(t *testing.T) {
+	api, _ := NewTestRequest().Context()
+	defer api.Release()
+
+	frontend := createTestFrontend(api)
+	createTestBackend(api)
+
+	router := cluster.NewRouter(nil)
+	router.Use(routing.SortLoad)
+	router.Use(routing.RequiredTags)
+
+	api, res := NewTestRequest().
+		KeepState().
+		Authorize("admin42", auth.ScopeAdmin).
+		JSON(&RoutingSimulation{
+			FrontendKey: frontend.Frontend.Key,
+			Params: bbb.Params{
+				bbb.ParamMeetingID: "meeting23",
+			},
+		}).
+		Context()
+	defer api.Release()
+
+	resource := NewResourceRoutingSimulate(router)
+	if err := api.Handle(resource.Create); err != nil {
+		t.Fatal(err)
+	}
+	if err := res.StatusOK(); err != nil {
+		t.Error(err)
+	}
+
+	// The test backend is not ready
+	body := res.Body()
+	if !strings.Contains(body, cluster.ErrNoBackendAvailable.Error()) {
+		t.Error("unexpected response:", body)
+	}
+}
+
+func TestRoutingSimulateValidate(t *testing.T) {
+	api, _ := NewTestRequest().
+		Authorize("admin42", auth.ScopeAdmin).
+		JSON(&RoutingSimulation{}).
+		Context()
+	defer api.Release()
+
+	resource := NewResourceRoutingSimulate(cluster.NewRouter(nil))
+	if err := api.Handle(resource.Create); err == nil {
+		t.Error("expected a validation error")
+	}
+}
+
+func TestSimulateCreateRequestMiddlewares(t *testing.T) {
+	fe := cluster.NewFrontend(&store.FrontendState{
+		Frontend: &bbb.Frontend{Key: "frontend1"},
+		Settings: store.FrontendSettings{
+			CreateDefaultParams: bbb.Params{
+				"meta_b3scale-tags": "premium",
+			},
+		},
+	})
+	ctx := cluster.ContextWithFrontend(context.Background(), fe)
+	req := bbb.CreateRequest(bbb.Params{}, nil)
+
+	var params bbb.Params
+	handler := simulateCreateRequestMiddlewares(
+		func(_ context.Context, req *bbb.Request) (bbb.Response, error) {
+			params = req.Params
+			return nil, nil
+		})
+	if _, err := handler(ctx, req); err != nil {
+		t.Fatal(err)
+	}
+	if params["meta_b3scale-tags"] != "premium" {
+		t.Error("default params should be applied:", params)
+	}
+	if params[bbb.MetaParam("b3s-frontend")] != "frontend1" {
+		t.Error("meta frontend should be set:", params)
+	}
+}
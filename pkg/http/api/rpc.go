@@ -12,6 +12,7 @@ import (
 	"github.com/rs/zerolog/log"
 
 	"github.com/b3scale/b3scale/pkg/bbb"
+	"github.com/b3scale/b3scale/pkg/cluster"
 	"github.com/b3scale/b3scale/pkg/http/auth"
 	"github.com/b3scale/b3scale/pkg/store"
 )
@@ -100,6 +101,7 @@ const (
 	ActionMeetingSetRunning     = "meeting_set_running"
 	ActionMeetingAddAttendee    = "meeting_add_attendee"
 	ActionMeetingRemoveAttendee = "meeting_remove_attendee"
+	ActionMeetingSetVideo       = "meeting_set_video"
 )
 
 // Payloads
@@ -129,6 +131,15 @@ type MeetingRemoveAttendeeRequest struct {
 	InternalUserID    string `json:"internal_user_id"`
 }
 
+// MeetingSetVideoRequest signals that an attendee
+// started or stopped sharing a webcam stream
+type MeetingSetVideoRequest struct {
+	InternalMeetingID string `json:"internal_meeting_id"`
+	InternalUserID    string `json:"internal_user_id"`
+	Stream            string `json:"stream"`
+	Broadcasting      bool   `json:"broadcasting"`
+}
+
 // Action Creators
 
 // RPCMeetingStateReset creates an meeting state reset request
@@ -151,6 +162,11 @@ func RPCMeetingRemoveAttendee(params *MeetingRemoveAttendeeRequest) *RPCRequest
 	return NewRPCRequest(ActionMeetingRemoveAttendee, params)
 }
 
+// RPCMeetingSetVideo creates a set video request
+func RPCMeetingSetVideo(params *MeetingSetVideoRequest) *RPCRequest {
+	return NewRPCRequest(ActionMeetingSetVideo, params)
+}
+
 // Dispatch will invoke the RPC handlers with the decoded
 // request payload.
 func (rpc *RPCRequest) Dispatch(
@@ -189,6 +205,13 @@ func (rpc *RPCRequest) Dispatch(
 		}
 		result, err = handler.MeetingRemoveAttendee(ctx, req)
 
+	case ActionMeetingSetVideo:
+		req := &MeetingSetVideoRequest{}
+		if err := json.Unmarshal(rpc.Payload, &req); err != nil {
+			return RPCError(err)
+		}
+		result, err = handler.MeetingSetVideo(ctx, req)
+
 	default:
 		err = ErrInvalidAction
 	}
@@ -231,6 +254,13 @@ func (rpc *RPCHandler) MeetingStateReset(
 	}
 	defer tx.Rollback(ctx) //nolint
 
+	// The meeting ended, so the usage is accounted
+	if err := store.CloseUsageRecord(
+		ctx, tx, meeting.InternalID, meeting.Meeting.Recording,
+	); err != nil {
+		return nil, err
+	}
+
 	// Update state
 	meeting.Meeting.Running = false
 	meeting.Meeting.Attendees = []*bbb.Attendee{}
@@ -238,6 +268,14 @@ func (rpc *RPCHandler) MeetingStateReset(
 	if err := meeting.Save(ctx, tx); err != nil {
 		return nil, err
 	}
+	if err := meeting.MarkAgentEvent(ctx, tx); err != nil {
+		return nil, err
+	}
+	if err := cluster.EmitEvent(ctx, tx, cluster.NewMeetingEvent(
+		store.EventMeetingEnded, meeting,
+	)); err != nil {
+		return nil, err
+	}
 	if err := tx.Commit(ctx); err != nil {
 		return nil, err
 	}
@@ -266,11 +304,26 @@ func (rpc *RPCHandler) MeetingSetRunning(
 
 	// Update state
 	meeting.Meeting.Running = req.Running
+	if req.Running {
+		if err := store.OpenUsageRecord(ctx, tx, meeting); err != nil {
+			return nil, err
+		}
+		// The agent sets the running flag, when the meeting
+		// was created on the backend.
+		if err := cluster.EmitEvent(ctx, tx, cluster.NewMeetingEvent(
+			store.EventMeetingCreated, meeting,
+		)); err != nil {
+			return nil, err
+		}
+	}
 
 	// Commit changes
 	if err := meeting.Save(ctx, tx); err != nil {
 		return nil, err
 	}
+	if err := meeting.MarkAgentEvent(ctx, tx); err != nil {
+		return nil, err
+	}
 	if err := tx.Commit(ctx); err != nil {
 		return nil, err
 	}
@@ -304,9 +357,34 @@ func (rpc *RPCHandler) MeetingAddAttendee(
 	attendees = append(attendees, req.Attendee)
 	meeting.Meeting.Attendees = attendees
 
+	// Account the attendee. The usage record is opened,
+	// in case the meeting created event was missed.
+	if err := store.OpenUsageRecord(ctx, tx, meeting); err != nil {
+		return nil, err
+	}
+	if err := store.StartUsageSession(
+		ctx, tx,
+		meeting.InternalID,
+		store.UsageSessionAttendee,
+		req.Attendee.InternalUserID,
+	); err != nil {
+		return nil, err
+	}
+
 	if err := meeting.Save(ctx, tx); err != nil {
 		return nil, err
 	}
+	if err := meeting.MarkAgentEvent(ctx, tx); err != nil {
+		return nil, err
+	}
+	if err := cluster.EmitEvent(ctx, tx, cluster.NewAttendeeEvent(
+		store.EventAttendeeJoined,
+		meeting,
+		req.Attendee.InternalUserID,
+		req.Attendee,
+	)); err != nil {
+		return nil, err
+	}
 	if err := tx.Commit(ctx); err != nil {
 		return nil, err
 	}
@@ -332,14 +410,25 @@ func (rpc *RPCHandler) MeetingRemoveAttendee(
 	}
 	defer tx.Rollback(ctx) //nolint
 
+	if err := store.StopUsageSession(
+		ctx, tx,
+		meeting.InternalID,
+		store.UsageSessionAttendee,
+		req.InternalUserID,
+	); err != nil {
+		return nil, err
+	}
+
 	// Update state
 	attendees := meeting.Meeting.Attendees
 	if attendees == nil {
-		return nil, nil // nothing to do here...
+		return nil, tx.Commit(ctx)
 	}
+	var attendee *bbb.Attendee
 	filtered := make([]*bbb.Attendee, 0, len(meeting.Meeting.Attendees))
 	for _, a := range meeting.Meeting.Attendees {
 		if a.InternalUserID == req.InternalUserID {
+			attendee = a
 			continue // The user just left
 		}
 		filtered = append(filtered, a)
@@ -349,6 +438,56 @@ func (rpc *RPCHandler) MeetingRemoveAttendee(
 	if err := meeting.Save(ctx, tx); err != nil {
 		return nil, err
 	}
+	if err := meeting.MarkAgentEvent(ctx, tx); err != nil {
+		return nil, err
+	}
+	if err := cluster.EmitEvent(ctx, tx, cluster.NewAttendeeEvent(
+		store.EventAttendeeLeft,
+		meeting,
+		req.InternalUserID,
+		attendee,
+	)); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+	return nil, nil
+}
+
+// MeetingSetVideo accounts the start or stop of
+// a webcam stream in the meeting.
+func (rpc *RPCHandler) MeetingSetVideo(
+	ctx context.Context,
+	req *MeetingSetVideoRequest,
+) (RPCResult, error) {
+	ctx, cancel := context.WithTimeout(ctx, 15*time.Second)
+	defer cancel()
+
+	meeting, tx, err := store.AwaitMeetingState(ctx, rpc.Conn, store.Q().
+		Where("meetings.backend_id = ?", rpc.Backend.ID).
+		Where("meetings.internal_id = ?", req.InternalMeetingID))
+	if errors.Is(err, context.DeadlineExceeded) {
+		rpc.logMeetingNotFound(req.InternalMeetingID)
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx) //nolint
+
+	if req.Broadcasting {
+		err = store.StartUsageSession(
+			ctx, tx, meeting.InternalID, store.UsageSessionVideo, req.Stream)
+	} else {
+		err = store.StopUsageSession(
+			ctx, tx, meeting.InternalID, store.UsageSessionVideo, req.Stream)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := meeting.MarkAgentEvent(ctx, tx); err != nil {
+		return nil, err
+	}
 	if err := tx.Commit(ctx); err != nil {
 		return nil, err
 	}
@@ -0,0 +1,156 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/b3scale/b3scale/pkg/http/auth"
+	"github.com/b3scale/b3scale/pkg/store"
+)
+
+// Usage export formats
+const (
+	UsageFormatJSON = "json"
+	UsageFormatCSV  = "csv"
+)
+
+// UsageCSVHeader are the columns of the usage export
+var UsageCSVHeader = []string{
+	"id",
+	"meeting_id",
+	"internal_meeting_id",
+	"frontend_id",
+	"frontend_key",
+	"backend_id",
+	"backend_host",
+	"started_at",
+	"ended_at",
+	"peak_attendees",
+	"attendee_minutes",
+	"video_minutes",
+	"recorded",
+}
+
+// ResourceUsage is the usage ledger export
+var ResourceUsage = &Resource{
+	List: RequireScope(
+		auth.ScopeAdmin,
+		auth.ScopeUser,
+	)(apiUsageList),
+}
+
+// apiUsageList exports the usage ledger of meetings
+// started in a date range as JSON or CSV.
+// Users may only export the usage of the frontends
+// of their account.
+func apiUsageList(
+	ctx context.Context,
+	api *API,
+) error {
+	format := api.QueryParam("format")
+	if format == "" {
+		format = UsageFormatJSON
+	}
+	if format != UsageFormatJSON && format != UsageFormatCSV {
+		return echo.NewHTTPError(
+			http.StatusBadRequest, "format must be json or csv")
+	}
+	// By default, export the current month
+	now := time.Now().UTC()
+	from, to, err := TimeRangeFromQueryParams(api,
+		time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC),
+		now)
+	if err != nil {
+		return err
+	}
+
+	tx, err := api.Conn.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx) //nolint
+
+	q := store.Q().
+		Where("usage_meetings.started_at >= ?", from).
+		Where("usage_meetings.started_at < ?", to)
+
+	if api.QueryParam("frontend_id") != "" || api.QueryParam("frontend_key") != "" {
+		fe, err := FrontendFromQueryParams(ctx, api, tx)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusNotFound, err.Error())
+		}
+		q = q.Where("usage_meetings.frontend_id = ?", fe.ID)
+	}
+	if !api.HasScope(auth.ScopeAdmin) {
+		q = q.
+			Join("frontends ON frontends.id = usage_meetings.frontend_id").
+			Where("frontends.account_ref = ?", api.Ref)
+	}
+
+	records, err := store.GetUsageRecords(ctx, tx, q)
+	if err != nil {
+		return err
+	}
+
+	if format == UsageFormatJSON {
+		return api.JSON(http.StatusOK, records)
+	}
+
+	data, err := encodeUsageCSV(records)
+	if err != nil {
+		return err
+	}
+	api.Response().Header().Set(
+		echo.HeaderContentDisposition,
+		fmt.Sprintf("attachment; filename=\"usage-%s-%s.csv\"",
+			from.Format("20060102"), to.Format("20060102")))
+	return api.Blob(http.StatusOK, "text/csv", data)
+}
+
+// encodeUsageCSV writes the usage records as CSV
+func encodeUsageCSV(records []*store.UsageRecord) ([]byte, error) {
+	buf := &bytes.Buffer{}
+	w := csv.NewWriter(buf)
+	if err := w.Write(UsageCSVHeader); err != nil {
+		return nil, err
+	}
+
+	optional := func(s *string) string {
+		if s == nil {
+			return ""
+		}
+		return *s
+	}
+	for _, r := range records {
+		endedAt := ""
+		if r.EndedAt != nil {
+			endedAt = r.EndedAt.Format(time.RFC3339)
+		}
+		if err := w.Write([]string{
+			r.ID,
+			r.MeetingID,
+			r.InternalMeetingID,
+			optional(r.FrontendID),
+			optional(r.FrontendKey),
+			optional(r.BackendID),
+			r.BackendHost,
+			r.StartedAt.Format(time.RFC3339),
+			endedAt,
+			strconv.Itoa(r.PeakAttendees),
+			strconv.FormatFloat(r.AttendeeMinutes, 'f', 2, 64),
+			strconv.FormatFloat(r.VideoMinutes, 'f', 2, 64),
+			strconv.FormatBool(r.Recorded),
+		}); err != nil {
+			return nil, err
+		}
+	}
+	w.Flush()
+	return buf.Bytes(), w.Error()
+}
@@ -0,0 +1,63 @@
+package api
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/b3scale/b3scale/pkg/http/auth"
+	"github.com/b3scale/b3scale/pkg/store"
+)
+
+func TestEncodeUsageCSV(t *testing.T) {
+	key := "frontend1"
+	ended := time.Date(2026, 10, 1, 13, 0, 0, 0, time.UTC)
+	data, err := encodeUsageCSV([]*store.UsageRecord{
+		{
+			ID:              "u1",
+			MeetingID:       "m1",
+			FrontendKey:     &key,
+			BackendHost:     "bbb1.example.org",
+			StartedAt:       time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC),
+			EndedAt:         &ended,
+			PeakAttendees:   3,
+			AttendeeMinutes: 150,
+			VideoMinutes:    42.5,
+			Recorded:        true,
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) != 2 {
+		t.Fatal("unexpected lines:", lines)
+	}
+	if !strings.HasPrefix(lines[0], "id,meeting_id,") {
+		t.Error("unexpected header:", lines[0])
+	}
+	expected := "u1,m1,,,frontend1,,bbb1.example.org," +
+		"2026-10-01T12:00:00Z,2026-10-01T13:00:00Z,3,150.00,42.50,true"
+	if lines[1] != expected {
+		t.Error("unexpected row:", lines[1])
+	}
+}
+
+func TestUsageList(t *testing.T) {
+	api, res := NewTestRequest().
+		Authorize("user23", auth.ScopeUser).
+		Query("from=2026-01-01&format=csv").
+		Context()
+	defer api.Release()
+
+	if err := api.Handle(ResourceUsage.List); err != nil {
+		t.Fatal(err)
+	}
+	if err := res.StatusOK(); err != nil {
+		t.Error(err)
+	}
+	if !strings.HasPrefix(res.Body(), "id,") {
+		t.Error("expected csv header")
+	}
+}
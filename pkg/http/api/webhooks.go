@@ -0,0 +1,231 @@
+package api
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/labstack/echo/v4"
+
+	"github.com/b3scale/b3scale/pkg/http/auth"
+	"github.com/b3scale/b3scale/pkg/store"
+)
+
+// ResourceWebhooks is a restful group for
+// subscriptions to cluster events
+var ResourceWebhooks = &Resource{
+	List: RequireScope(
+		auth.ScopeAdmin,
+	)(apiWebhooksList),
+
+	Show: RequireScope(
+		auth.ScopeAdmin,
+	)(apiWebhookShow),
+
+	Create: RequireScope(
+		auth.ScopeAdmin,
+	)(apiWebhookCreate),
+
+	Update: RequireScope(
+		auth.ScopeAdmin,
+	)(apiWebhookUpdate),
+
+	Destroy: RequireScope(
+		auth.ScopeAdmin,
+	)(apiWebhookDestroy),
+}
+
+// apiWebhooksList returns all webhooks,
+// optionally filtered by frontend.
+func apiWebhooksList(
+	ctx context.Context,
+	api *API,
+) error {
+	tx, err := api.Conn.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx) //nolint
+
+	q := store.Q()
+	if frontendID := api.QueryParam("frontend_id"); frontendID != "" {
+		q = q.Where("webhooks.frontend_id = ?", frontendID)
+	}
+
+	webhooks, err := store.GetWebhooks(ctx, tx, q)
+	if err != nil {
+		return err
+	}
+	return api.JSON(http.StatusOK, webhooks)
+}
+
+// apiWebhookShow returns a single webhook by ID
+func apiWebhookShow(
+	ctx context.Context,
+	api *API,
+) error {
+	tx, err := api.Conn.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx) //nolint
+
+	webhook, err := store.GetWebhook(ctx, tx, store.Q().
+		Where("webhooks.id = ?", api.Param("id")))
+	if err != nil {
+		return err
+	}
+	if webhook == nil {
+		return echo.ErrNotFound
+	}
+	return api.JSON(http.StatusOK, webhook)
+}
+
+// apiWebhookCreate subscribes to events. If no
+// secret is provided, a random secret is generated.
+func apiWebhookCreate(
+	ctx context.Context,
+	api *API,
+) error {
+	req := &store.Webhook{Active: true}
+	if err := api.Bind(req); err != nil {
+		return err
+	}
+	webhook := store.InitWebhook(&store.Webhook{
+		URL:        req.URL,
+		Secret:     req.Secret,
+		Events:     req.Events,
+		FrontendID: req.FrontendID,
+		Active:     req.Active,
+		Comment:    req.Comment,
+	})
+	if webhook.Secret == "" {
+		secret, err := auth.GenerateSecret(20)
+		if err != nil {
+			return err
+		}
+		webhook.Secret = secret
+	}
+	if err := webhook.Validate(); err != nil {
+		return err
+	}
+
+	tx, err := api.Conn.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx) //nolint
+
+	if err := validateWebhookFrontend(ctx, tx, webhook); err != nil {
+		return err
+	}
+	if err := webhook.Save(ctx, tx); err != nil {
+		return err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return err
+	}
+	return api.JSON(http.StatusOK, webhook)
+}
+
+// apiWebhookUpdate changes the subscription
+func apiWebhookUpdate(
+	ctx context.Context,
+	api *API,
+) error {
+	tx, err := api.Conn.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx) //nolint
+
+	q := store.Q().Where("webhooks.id = ?", api.Param("id"))
+	webhook, err := store.GetWebhook(ctx, tx, q)
+	if err != nil {
+		return err
+	}
+	if webhook == nil {
+		return echo.ErrNotFound
+	}
+	update, err := store.GetWebhook(ctx, tx, q)
+	if err != nil {
+		return err
+	}
+	if err := api.Bind(update); err != nil {
+		return err
+	}
+
+	// Update fields
+	webhook.URL = update.URL
+	webhook.Secret = update.Secret
+	webhook.Events = update.Events
+	webhook.FrontendID = update.FrontendID
+	webhook.Active = update.Active
+	webhook.Comment = update.Comment
+
+	if err := webhook.Validate(); err != nil {
+		return err
+	}
+	if err := validateWebhookFrontend(ctx, tx, webhook); err != nil {
+		return err
+	}
+	if err := webhook.Save(ctx, tx); err != nil {
+		return err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return err
+	}
+	return api.JSON(http.StatusOK, webhook)
+}
+
+// apiWebhookDestroy removes a webhook. Pending
+// deliveries are dropped.
+func apiWebhookDestroy(
+	ctx context.Context,
+	api *API,
+) error {
+	tx, err := api.Conn.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx) //nolint
+
+	webhook, err := store.GetWebhook(ctx, tx, store.Q().
+		Where("webhooks.id = ?", api.Param("id")))
+	if err != nil {
+		return err
+	}
+	if webhook == nil {
+		return echo.ErrNotFound
+	}
+	if err := webhook.Delete(ctx, tx); err != nil {
+		return err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return err
+	}
+	return api.JSON(http.StatusOK, webhook)
+}
+
+// validateWebhookFrontend checks that the frontend
+// of the webhook exists.
+func validateWebhookFrontend(
+	ctx context.Context,
+	tx pgx.Tx,
+	webhook *store.Webhook,
+) error {
+	if webhook.FrontendID == nil {
+		return nil
+	}
+	fe, err := store.GetFrontendState(ctx, tx, store.Q().
+		Where("id = ?", *webhook.FrontendID))
+	if err != nil {
+		return err
+	}
+	if fe == nil {
+		return store.ValidationError{
+			"frontend_id": []string{"the frontend does not exist"},
+		}
+	}
+	return nil
+}
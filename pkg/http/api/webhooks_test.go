@@ -0,0 +1,98 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/b3scale/b3scale/pkg/http/auth"
+	"github.com/b3scale/b3scale/pkg/store"
+)
+
+func TestWebhookCreateUpdateDestroy(t *testing.T) {
+	api, res := NewTestRequest().
+		Authorize("admin42", auth.ScopeAdmin).
+		JSON(map[string]interface{}{
+			"url":    "https://example.com/events",
+			"events": []string{store.EventMeetingCreated},
+		}).
+		Context()
+	defer api.Release()
+
+	if err := api.Handle(ResourceWebhooks.Create); err != nil {
+		t.Fatal(err)
+	}
+	if err := res.StatusOK(); err != nil {
+		t.Fatal(err)
+	}
+	webhook := res.JSON()
+	if webhook["secret"] == "" {
+		t.Error("expected a generated secret")
+	}
+	if webhook["active"] != true {
+		t.Error("expected webhook to be active")
+	}
+	id := webhook["id"].(string)
+
+	api, res = NewTestRequest().
+		Authorize("admin42", auth.ScopeAdmin).
+		JSON(map[string]interface{}{
+			"active": false,
+		}).
+		Context()
+	defer api.Release()
+	api.SetParamNames("id")
+	api.SetParamValues(id)
+
+	if err := api.Handle(ResourceWebhooks.Update); err != nil {
+		t.Fatal(err)
+	}
+	if err := res.StatusOK(); err != nil {
+		t.Fatal(err)
+	}
+	webhook = res.JSON()
+	if webhook["active"] != false {
+		t.Error("expected webhook to be inactive")
+	}
+	if webhook["url"] != "https://example.com/events" {
+		t.Error("unexpected url:", webhook["url"])
+	}
+
+	api, res = NewTestRequest().
+		Authorize("admin42", auth.ScopeAdmin).
+		Context()
+	defer api.Release()
+	api.SetParamNames("id")
+	api.SetParamValues(id)
+
+	if err := api.Handle(ResourceWebhooks.Destroy); err != nil {
+		t.Fatal(err)
+	}
+	if err := res.StatusOK(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestWebhookCreateInvalid(t *testing.T) {
+	api, _ := NewTestRequest().
+		Authorize("admin42", auth.ScopeAdmin).
+		JSON(map[string]interface{}{
+			"url":    "https://example.com/events",
+			"events": []string{"meeting.exploded"},
+		}).
+		Context()
+	defer api.Release()
+
+	if err := api.Handle(ResourceWebhooks.Create); err == nil {
+		t.Error("expected unknown event type to be rejected")
+	}
+}
+
+func TestWebhooksListRequiresAdmin(t *testing.T) {
+	api, _ := NewTestRequest().
+		Authorize("user23", auth.ScopeUser).
+		Context()
+	defer api.Release()
+
+	if err := api.Handle(ResourceWebhooks.List); err == nil {
+		t.Error("expected webhooks to require the admin scope")
+	}
+}
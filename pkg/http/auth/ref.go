@@ -1,7 +1,9 @@
 package auth
 
 import (
+	crand "crypto/rand"
 	_ "embed" // required for embedding
+	"encoding/hex"
 	"math/rand"
 
 	"strings"
@@ -33,3 +35,13 @@ func GenerateNonce(n int) string {
 	}
 	return string(nonce)
 }
+
+// GenerateSecret creates a random secret from n
+// random bytes, encoded as hex.
+func GenerateSecret(n int) (string, error) {
+	buf := make([]byte, n)
+	if _, err := crand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
@@ -13,3 +13,17 @@ func TestGenerateNonce(t *testing.T) {
 		t.Log(GenerateNonce(24))
 	}
 }
+
+func TestGenerateSecret(t *testing.T) {
+	s1, err := GenerateSecret(20)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s2, _ := GenerateSecret(20)
+	if len(s1) != 40 {
+		t.Error("unexpected length:", len(s1))
+	}
+	if s1 == s2 {
+		t.Error("secrets should differ")
+	}
+}
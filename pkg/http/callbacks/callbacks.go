@@ -20,6 +20,12 @@ type Callback interface {
 	Encode() string
 }
 
+// ContentTyper can be implemented by a callback,
+// if the body is not encoded as form data.
+type ContentTyper interface {
+	ContentType() string
+}
+
 // SignedBody contains signed parameters posted
 // by the bbb node agent to the callback URL.
 //
@@ -25,6 +25,7 @@ type Request struct {
 	URL      string
 	Method   string
 	Callback Callback
+	Header   http.Header
 }
 
 // Post creates a new POST request.
@@ -62,6 +63,26 @@ func Dispatch(req *Request) {
 	}()
 }
 
+// Invoke makes a single request to the callback URL.
+// Retrying the request is left to the caller, e.g.
+// when the invocation is a queued command.
+func Invoke(ctx context.Context, req *Request) error {
+	t0 := time.Now()
+	err := doCallbackRequest(ctx, req)
+	if err != nil {
+		log.Error().
+			Dur("duration_request", time.Since(t0)).
+			Str("url", req.URL).
+			Err(err).Msg("callback request failed")
+		return err
+	}
+	log.Info().
+		Dur("duration_request", time.Since(t0)).
+		Str("url", req.URL).
+		Msg("callback request successful")
+	return nil
+}
+
 // Make the request to the callback URL.
 // Retry on failure with backoff.
 func runCallback(ctx context.Context, req *Request) error {
@@ -162,7 +183,16 @@ func doCallbackRequest(
 
 	// Set content type when posting a callback
 	if req.Callback != nil {
-		cbReq.Header.Set("Content-Type", "multipart/form-data")
+		contentType := "multipart/form-data"
+		if ct, ok := req.Callback.(ContentTyper); ok {
+			contentType = ct.ContentType()
+		}
+		cbReq.Header.Set("Content-Type", contentType)
+	}
+	for key, values := range req.Header {
+		for _, v := range values {
+			cbReq.Header.Add(key, v)
+		}
 	}
 
 	res, err := client.Do(cbReq)
@@ -0,0 +1,99 @@
+package callbacks
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// Webhook headers
+const (
+	HeaderWebhookEvent     = "X-B3scale-Event"
+	HeaderWebhookDelivery  = "X-B3scale-Delivery"
+	HeaderWebhookTimestamp = "X-B3scale-Timestamp"
+	HeaderWebhookSignature = "X-B3scale-Signature"
+)
+
+// Webhook retry configuration: Webhook deliveries are
+// queued as commands and retried by the command queue,
+// as receivers may be unavailable for a while.
+const (
+	WebhookRetryCount   = 8
+	WebhookRetryWaitMin = 10 * time.Second
+	WebhookRetryWaitMax = 10 * time.Minute
+)
+
+// WebhookSignaturePrefix is prepended to the
+// hex encoded signature.
+const WebhookSignaturePrefix = "sha256="
+
+// A Webhook is a JSON encoded event posted
+// to a subscriber.
+type Webhook struct {
+	Event    string
+	Delivery string
+	Payload  []byte
+}
+
+// Validate checks the webhook
+func (w *Webhook) Validate() error {
+	if w.Event == "" {
+		return fmt.Errorf("event is required")
+	}
+	if len(w.Payload) == 0 {
+		return fmt.Errorf("payload is required")
+	}
+	return nil
+}
+
+// Encode returns the JSON payload
+func (w *Webhook) Encode() string {
+	return string(w.Payload)
+}
+
+// ContentType of the webhook payload
+func (w *Webhook) ContentType() string {
+	return "application/json"
+}
+
+// SignWebhook calculates the HMAC-SHA256 of the timestamp
+// and the payload, separated by a dot, with the secret.
+func SignWebhook(secret string, timestamp int64, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(payload)
+	return WebhookSignaturePrefix + hex.EncodeToString(mac.Sum(nil))
+}
+
+// VerifyWebhookSignature checks the signature of
+// a received webhook.
+func VerifyWebhookSignature(
+	secret string,
+	signature string,
+	timestamp int64,
+	payload []byte,
+) bool {
+	expected := SignWebhook(secret, timestamp, payload)
+	return hmac.Equal([]byte(expected), []byte(signature))
+}
+
+// PostWebhook creates a new signed POST request.
+// The signature includes the current time, so a new
+// request should be created for each attempt.
+func PostWebhook(url, secret string, w *Webhook) *Request {
+	ts := time.Now().Unix()
+	header := http.Header{}
+	header.Set(HeaderWebhookEvent, w.Event)
+	header.Set(HeaderWebhookDelivery, w.Delivery)
+	header.Set(HeaderWebhookTimestamp, strconv.FormatInt(ts, 10))
+	header.Set(HeaderWebhookSignature, SignWebhook(secret, ts, w.Payload))
+
+	req := Post(url, w)
+	req.Header = header
+	return req
+}
@@ -0,0 +1,91 @@
+package callbacks
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+)
+
+func TestSignWebhook(t *testing.T) {
+	payload := []byte(`{"type":"meeting.created"}`)
+	sig := SignWebhook("s3cr37", 1700000000, payload)
+	if sig[:len(WebhookSignaturePrefix)] != WebhookSignaturePrefix {
+		t.Error("unexpected signature:", sig)
+	}
+	if !VerifyWebhookSignature("s3cr37", sig, 1700000000, payload) {
+		t.Error("expected valid signature")
+	}
+	if VerifyWebhookSignature("other", sig, 1700000000, payload) {
+		t.Error("expected invalid signature for other secret")
+	}
+	if VerifyWebhookSignature("s3cr37", sig, 1700000001, payload) {
+		t.Error("expected invalid signature for other timestamp")
+	}
+}
+
+func TestWebhookValidate(t *testing.T) {
+	w := &Webhook{}
+	if err := w.Validate(); err == nil {
+		t.Error("expected error")
+	}
+	w = &Webhook{Event: "meeting.created", Payload: []byte("{}")}
+	if err := w.Validate(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestInvokePostWebhook(t *testing.T) {
+	payload := []byte(`{"type":"meeting.created"}`)
+	srv := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			body, _ := io.ReadAll(r.Body)
+			ts, err := strconv.ParseInt(
+				r.Header.Get(HeaderWebhookTimestamp), 10, 64)
+			if err != nil {
+				t.Error(err)
+			}
+			if r.Header.Get("Content-Type") != "application/json" {
+				t.Error("unexpected content type:", r.Header.Get("Content-Type"))
+			}
+			if r.Header.Get(HeaderWebhookEvent) != "meeting.created" {
+				t.Error("unexpected event:", r.Header.Get(HeaderWebhookEvent))
+			}
+			if r.Header.Get(HeaderWebhookDelivery) != "delivery1" {
+				t.Error("unexpected delivery:", r.Header.Get(HeaderWebhookDelivery))
+			}
+			sig := r.Header.Get(HeaderWebhookSignature)
+			if !VerifyWebhookSignature("s3cr37", sig, ts, body) {
+				t.Error("invalid signature")
+			}
+			w.WriteHeader(http.StatusNoContent)
+		}))
+	defer srv.Close()
+
+	req := PostWebhook(srv.URL, "s3cr37", &Webhook{
+		Event:    "meeting.created",
+		Delivery: "delivery1",
+		Payload:  payload,
+	})
+	if err := Invoke(context.Background(), req); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestInvokeErrorStatus(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}))
+	defer srv.Close()
+
+	req := PostWebhook(srv.URL, "s3cr37", &Webhook{
+		Event:   "meeting.created",
+		Payload: []byte("{}"),
+	})
+	if err := Invoke(context.Background(), req); err == nil {
+		t.Error("expected error")
+	}
+}
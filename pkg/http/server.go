@@ -35,6 +35,7 @@ func NewServer(
 	serviceID string,
 	ctrl *cluster.Controller,
 	gateway *cluster.Gateway,
+	router *cluster.Router,
 ) *Server {
 	// Setup and configure echo framework
 	e := echo.New()
@@ -46,6 +47,7 @@ func NewServer(
 	e.Use(logging.Middleware())
 	e.Use(middleware.ContextTimeoutWithConfig(middleware.ContextTimeoutConfig{
 		Timeout: config.GetHTTPRequestTimeout(),
+		Skipper: api.StreamingSkipper,
 	}))
 
 	// Prometheus Middleware - Find it under /metrics
@@ -53,6 +55,8 @@ func NewServer(
 	e.GET("/metrics", echoprometheus.NewHandler())
 
 	pclient.MustRegister(metrics.Collector{})
+	metrics.RegisterRoutingMetrics(pclient.DefaultRegisterer)
+	metrics.RegisterCommandMetrics(pclient.DefaultRegisterer)
 
 	// We handle BBB requests in a custom middleware
 	e.Use(BBBRequestMiddleware("/bbb", ctrl, gateway))
@@ -70,7 +74,7 @@ func NewServer(
 	e.GET("/static/*", echo.WrapHandler(static.AssetsHTTPHandler("/static")))
 	e.GET("/b3s/retry-join/:req", s.httpRetryJoin)
 
-	if err := api.Init(e); err != nil {
+	if err := api.Init(e, router); err != nil {
 		log.Warn().Err(err).Msg("could not initialize rest API")
 	}
 
@@ -66,9 +66,10 @@ var (
 )
 
 // The Collector will gather metrics from the b3scale
-// cluster about current meetings
+// cluster about current meetings and the command queue
 //   - attendees [ frontend, backend, type, meeting ]
 //   - durations [ meeting ]
+//   - queue depth [ action, state ]
 type Collector struct{}
 
 // Describe the collector
@@ -76,6 +77,9 @@ func (c Collector) Describe(ch chan<- *prometheus.Desc) {
 	ch <- meetingAttendeesDesc
 	ch <- meetingDurationsDesc
 	ch <- frontendMeetingsDesc
+	ch <- commandQueueDepthDesc
+	ch <- commandQueueOldestPendingDesc
+	ch <- commandsExpiredTotalDesc
 }
 
 // Collect metrics from store
@@ -102,6 +106,11 @@ func (c Collector) Collect(ch chan<- prometheus.Metric) {
 		log.Error().Err(err).Msg("could not collect metrics for meetings")
 	}
 
+	// Collect command queue depth
+	if err := c.collectCommandQueueMetrics(ctx, tx, ch); err != nil {
+		log.Error().Err(err).Msg("could not collect metrics for commands")
+	}
+
 }
 
 // Collect attendee metrics
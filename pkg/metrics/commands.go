@@ -0,0 +1,126 @@
+package metrics
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/b3scale/b3scale/pkg/store"
+)
+
+// Command queue metrics are collected from the store
+var (
+	commandQueueDepthDesc = prometheus.NewDesc(
+		"b3scale_command_queue_depth",
+		"Number of commands in the queue",
+		[]string{
+			// The command action, e.g. update_node_state
+			"action",
+			// The state of the command, e.g. requested
+			"state",
+		}, nil)
+
+	commandQueueOldestPendingDesc = prometheus.NewDesc(
+		"b3scale_command_queue_oldest_pending_seconds",
+		"Time since the oldest pending command could have been processed",
+		[]string{
+			// The command action
+			"action",
+		}, nil)
+
+	// Expired commands are counted in the store, as most of
+	// them are removed by the housekeeping of the queue
+	// without being dequeued by a worker.
+	commandsExpiredTotalDesc = prometheus.NewDesc(
+		"b3scale_commands_expired_total",
+		"Number of commands not processed before the deadline",
+		[]string{
+			// The command action
+			"action",
+		}, nil)
+)
+
+// Command metrics are updated when processing commands
+var (
+	// CommandDurationSeconds observes the time spent
+	// processing a command.
+	CommandDurationSeconds = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "b3scale_command_duration_seconds",
+			Help:    "Time spent processing commands",
+			Buckets: prometheus.ExponentialBuckets(0.05, 2, 12),
+		},
+		[]string{
+			// The command action
+			"action",
+			// The state after processing: success, error,
+			// dead or requested, if the command is retried.
+			"state",
+		})
+)
+
+// CommandObserver updates the command metrics
+// when commands are processed.
+type CommandObserver struct{}
+
+// CommandProcessed observes the duration of the command
+func (CommandObserver) CommandProcessed(
+	cmd *store.Command,
+	state string,
+	duration time.Duration,
+) {
+	CommandDurationSeconds.
+		WithLabelValues(cmd.Action, state).
+		Observe(duration.Seconds())
+}
+
+// CommandExpired is a noop, expired commands
+// are counted in the store.
+func (CommandObserver) CommandExpired(_ *store.Command) {}
+
+// RegisterCommandMetrics registers the command metrics
+// with the prometheus registerer and observes the command
+// queue of this instance.
+func RegisterCommandMetrics(r prometheus.Registerer) {
+	r.MustRegister(CommandDurationSeconds)
+	store.SetCommandObserver(CommandObserver{})
+}
+
+// Collect command queue metrics
+func (c Collector) collectCommandQueueMetrics(
+	ctx context.Context,
+	tx pgx.Tx,
+	ch chan<- prometheus.Metric,
+) error {
+	stats, err := store.GetCommandQueueStats(ctx, tx)
+	if err != nil {
+		return err
+	}
+	now := time.Now().UTC()
+	for _, s := range stats {
+		ch <- prometheus.MustNewConstMetric(
+			commandQueueDepthDesc, prometheus.GaugeValue,
+			float64(s.Count), s.Action, s.State,
+		)
+		if s.OldestDue != nil {
+			ch <- prometheus.MustNewConstMetric(
+				commandQueueOldestPendingDesc, prometheus.GaugeValue,
+				now.Sub(*s.OldestDue).Seconds(), s.Action,
+			)
+		}
+	}
+
+	expirations, err := store.GetCommandExpirations(ctx, tx)
+	if err != nil {
+		return err
+	}
+	for action, expired := range expirations {
+		ch <- prometheus.MustNewConstMetric(
+			commandsExpiredTotalDesc, prometheus.CounterValue,
+			float64(expired), action,
+		)
+	}
+	return nil
+}
@@ -0,0 +1,29 @@
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Routing metrics are updated when selecting
+// a backend for a new meeting.
+var (
+	// CrossZoneRoutingTotal counts meetings created on a
+	// backend outside of the zone of the frontend.
+	CrossZoneRoutingTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "b3scale_routing_cross_zone_total",
+			Help: "Number of meetings routed to a backend outside of the frontend zone",
+		},
+		[]string{
+			// Zone of the frontend
+			"frontend_zone",
+			// Zone of the selected backend
+			"backend_zone",
+		})
+)
+
+// RegisterRoutingMetrics registers the routing
+// metrics with the prometheus registerer.
+func RegisterRoutingMetrics(r prometheus.Registerer) {
+	r.MustRegister(CrossZoneRoutingTotal)
+}
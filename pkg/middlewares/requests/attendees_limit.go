@@ -3,6 +3,8 @@ package requests
 import (
 	"context"
 	"net/http"
+	"sync"
+	"time"
 
 	"github.com/jackc/pgx/v4"
 	"github.com/rs/zerolog/log"
@@ -13,6 +15,31 @@ import (
 	"github.com/b3scale/b3scale/pkg/templates"
 )
 
+// AttendeesLimitEventInterval is the minimum time between
+// two frontend.attendees_limit_reached events of a frontend.
+// Rejected joins in between are not emitted.
+const AttendeesLimitEventInterval = 5 * time.Minute
+
+// attendeesLimitEvents remembers when the last event
+// was emitted for a frontend.
+var (
+	attendeesLimitEvents    = map[string]time.Time{}
+	attendeesLimitEventsMtx sync.Mutex
+)
+
+// shouldEmitAttendeesLimitEvent limits the events to one
+// per frontend within the AttendeesLimitEventInterval.
+func shouldEmitAttendeesLimitEvent(frontendID string, now time.Time) bool {
+	attendeesLimitEventsMtx.Lock()
+	defer attendeesLimitEventsMtx.Unlock()
+	last, ok := attendeesLimitEvents[frontendID]
+	if ok && now.Sub(last) < AttendeesLimitEventInterval {
+		return false
+	}
+	attendeesLimitEvents[frontendID] = now
+	return true
+}
+
 // CheckAttendeesLimit produces a middleware for checking
 // wether the limit of overall attendees is reached for a frontend.
 // There is one frontend setting variables:
@@ -35,6 +62,11 @@ func CheckAttendeesLimit() cluster.RequestMiddleware {
 
 			allowed := maybeCheckAttendeesLimit(ctx, tx, req, frontend)
 			if !allowed {
+				// Deliver the event about the rejected join
+				if err := tx.Commit(ctx); err != nil {
+					log.Warn().Str("frontend_key", frontend.Key()).
+						Err(err).Msg("failed to emit attendees limit event")
+				}
 				body := templates.AttendeesLimitReached()
 				res := &bbb.JoinResponse{
 					XMLResponse: &bbb.XMLResponse{
@@ -70,18 +102,27 @@ func maybeCheckAttendeesLimit(ctx context.Context, tx pgx.Tx, req *bbb.Request,
 
 	// Get the sum of the participantCount for all meetings of this meetings frontend
 	var curAt int
-	qry := `
-  select sum((state->>'ParticipantCount')::int) from meetings where frontend_id = $1
-  `
-	err := tx.QueryRow(ctx, qry, fe.ID()).Scan(&curAt)
+	usage, err := store.GetFrontendUsage(ctx, tx, fe.ID())
 	if err != nil {
 		log.Warn().Str("frontend_key", fe.Key()).
 			Err(err).Msg("failed to get 'ParticipantCount' from 'meetings'")
+	} else {
+		curAt = usage.Attendees
 	}
 
 	// If limit was already reached stop request
 	if curAt >= opts.Limit {
 		log.Info().Str("frontend_key", fe.Key()).Int("limit", opts.Limit).Int("current_attendees", curAt).Msg("attendees limit reached")
+
+		if !shouldEmitAttendeesLimitEvent(fe.ID(), time.Now()) {
+			return false
+		}
+		meetingID, _ := req.Params.MeetingID()
+		ev := cluster.NewAttendeesLimitEvent(fe, meetingID, opts.Limit, curAt)
+		if err := cluster.EmitEvent(ctx, tx, ev); err != nil {
+			log.Warn().Str("frontend_key", fe.Key()).
+				Err(err).Msg("failed to emit attendees limit event")
+		}
 		return false
 	}
 	// Otherwise let request go through
@@ -0,0 +1,23 @@
+package requests
+
+import (
+	"testing"
+	"time"
+)
+
+func TestShouldEmitAttendeesLimitEvent(t *testing.T) {
+	now := time.Now()
+	if !shouldEmitAttendeesLimitEvent("fe-limit-1", now) {
+		t.Error("first event should be emitted")
+	}
+	if shouldEmitAttendeesLimitEvent("fe-limit-1", now.Add(time.Minute)) {
+		t.Error("event should be rate limited")
+	}
+	if !shouldEmitAttendeesLimitEvent("fe-limit-2", now.Add(time.Minute)) {
+		t.Error("events of other frontends should be emitted")
+	}
+	if !shouldEmitAttendeesLimitEvent(
+		"fe-limit-1", now.Add(AttendeesLimitEventInterval)) {
+		t.Error("event should be emitted after the interval")
+	}
+}
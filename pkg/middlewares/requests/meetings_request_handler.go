@@ -2,6 +2,7 @@ package requests
 
 import (
 	"context"
+	"errors"
 	"net/http"
 
 	"github.com/b3scale/b3scale/pkg/bbb"
@@ -136,9 +137,15 @@ func (h *MeetingsHandler) Create(
 		return nil, err
 	}
 	// When no backend is found, select a new one.
+	// The routing decision is recorded and stored
+	// with the meeting.
 	if backend == nil {
+		ctx = cluster.ContextWithRoutingTrace(ctx, cluster.NewRoutingTrace())
 		backend, err = h.router.SelectBackend(ctx, req)
 	}
+	if errors.Is(err, cluster.ErrNoBackendCapacity) {
+		return noBackendCapacityResponse(), nil
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -282,6 +289,18 @@ func unknownMeetingResponse() *bbb.XMLResponse {
 	return res
 }
 
+// noBackendCapacityResponse is the error response, when
+// all backends reached their capacity limits.
+func noBackendCapacityResponse() *bbb.XMLResponse {
+	res := &bbb.XMLResponse{
+		Returncode: bbb.RetFailed,
+		Message:    "The meeting could not be created, because all servers are at full capacity.",
+		MessageKey: "maxCapacityReached",
+	}
+	res.SetStatus(http.StatusOK)
+	return res
+}
+
 // The unknownMeetingBrowserResponse renders a human readable 404 template
 // in case the meeting was not found.
 func unknownMeetingBrowserResponse() *bbb.JoinResponse {
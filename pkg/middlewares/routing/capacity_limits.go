@@ -0,0 +1,79 @@
+package routing
+
+import (
+	"context"
+
+	"github.com/b3scale/b3scale/pkg/bbb"
+	"github.com/b3scale/b3scale/pkg/cluster"
+)
+
+// CapacityLimits removes backends which reached the
+// limits defined in the backend settings:
+//
+//	max_meetings = 100
+//	max_attendees = 1200
+//
+// Expected attendees from the routing hints are
+// taken into account.
+//
+// If all backends are saturated, creating a meeting
+// will fail with an ErrNoBackendCapacity.
+func CapacityLimits(next cluster.RouterHandler) cluster.RouterHandler {
+	return func(
+		ctx context.Context,
+		backends []*cluster.Backend,
+		req *bbb.Request,
+	) ([]*cluster.Backend, error) {
+		// This middleware only applies to create meeting requests
+		if req.Resource != bbb.ResourceCreate {
+			return next(ctx, backends, req) // pass
+		}
+
+		hints := cluster.RoutingHintsFromContext(ctx)
+		filtered := filterCapacity(ctx, backends, hints)
+		if len(backends) > 0 && len(filtered) == 0 {
+			return nil, cluster.ErrNoBackendCapacity
+		}
+
+		return next(ctx, filtered, req)
+	}
+}
+
+// saturation checks if a backend reached one
+// of its configured limits or would exceed the attendees
+// limit with the expected attendees. A limit of 0 is ignored.
+// The reason is returned, or an empty string if the
+// backend is not saturated.
+func saturation(be *cluster.Backend, hints *cluster.RoutingHints) string {
+	settings := be.Settings()
+	if settings.MaxMeetings > 0 &&
+		be.MeetingsCount() >= uint(settings.MaxMeetings) {
+		return "max_meetings reached"
+	}
+	if settings.MaxAttendees > 0 &&
+		be.AttendeesCount() >= uint(settings.MaxAttendees) {
+		return "max_attendees reached"
+	}
+	if hints != nil && settings.MaxAttendees > 0 &&
+		be.AttendeesCount()+hints.ExpectedAttendees > uint(settings.MaxAttendees) {
+		return "max_attendees exceeded by expected attendees"
+	}
+	return ""
+}
+
+// filterCapacity removes all saturated backends
+func filterCapacity(
+	ctx context.Context,
+	backends []*cluster.Backend,
+	hints *cluster.RoutingHints,
+) []*cluster.Backend {
+	filtered := make([]*cluster.Backend, 0, len(backends))
+	for _, be := range backends {
+		if reason := saturation(be, hints); reason != "" {
+			cluster.RejectBackend(ctx, be, reason)
+			continue
+		}
+		filtered = append(filtered, be)
+	}
+	return filtered
+}
@@ -0,0 +1,88 @@
+package routing
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/b3scale/b3scale/pkg/bbb"
+	"github.com/b3scale/b3scale/pkg/cluster"
+	"github.com/b3scale/b3scale/pkg/store"
+)
+
+func TestFilterCapacity(t *testing.T) {
+	b1 := cluster.NewBackend(&store.BackendState{
+		ID:            "A",
+		MeetingsCount: 10,
+		Settings: store.BackendSettings{
+			MaxMeetings: 10,
+		},
+	})
+	b2 := cluster.NewBackend(&store.BackendState{
+		ID:             "B",
+		AttendeesCount: 23,
+		Settings: store.BackendSettings{
+			MaxAttendees: 100,
+		},
+	})
+	b3 := cluster.NewBackend(&store.BackendState{
+		ID:             "C",
+		MeetingsCount:  42,
+		AttendeesCount: 500,
+	})
+	b4 := cluster.NewBackend(&store.BackendState{
+		ID:             "D",
+		AttendeesCount: 100,
+		Settings: store.BackendSettings{
+			MaxAttendees: 100,
+		},
+	})
+
+	filtered := filterCapacity(context.Background(), []*cluster.Backend{b1, b2, b3, b4}, nil)
+	if len(filtered) != 2 {
+		t.Fatal("unexpected result:", filtered)
+	}
+	if filtered[0] != b2 || filtered[1] != b3 {
+		t.Error("unexpected result:", filtered)
+	}
+
+	// A large meeting will not fit on B
+	hints := &cluster.RoutingHints{ExpectedAttendees: 80}
+	filtered = filterCapacity(context.Background(), []*cluster.Backend{b1, b2, b3, b4}, hints)
+	if len(filtered) != 1 || filtered[0] != b3 {
+		t.Error("unexpected result:", filtered)
+	}
+}
+
+func TestCapacityLimitsSaturated(t *testing.T) {
+	b1 := cluster.NewBackend(&store.BackendState{
+		ID:            "A",
+		MeetingsCount: 2,
+		Settings: store.BackendSettings{
+			MaxMeetings: 1,
+		},
+	})
+	handler := CapacityLimits(func(
+		ctx context.Context,
+		backends []*cluster.Backend,
+		req *bbb.Request,
+	) ([]*cluster.Backend, error) {
+		return backends, nil
+	})
+
+	req := bbb.CreateRequest(bbb.Params{}, nil)
+	_, err := handler(context.Background(), []*cluster.Backend{b1}, req)
+	if !errors.Is(err, cluster.ErrNoBackendCapacity) {
+		t.Error("unexpected error:", err)
+	}
+
+	// Other requests should pass
+	req = bbb.JoinRequest(bbb.Params{})
+	backends, err := handler(context.Background(), []*cluster.Backend{b1}, req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(backends) != 1 {
+		t.Error("unexpected backends:", backends)
+	}
+}
@@ -0,0 +1,50 @@
+package routing
+
+import (
+	"context"
+
+	"github.com/b3scale/b3scale/pkg/bbb"
+	"github.com/b3scale/b3scale/pkg/cluster"
+)
+
+// Hints reads routing hints from the meta parameters
+// of a create request:
+//
+//	meta_b3scale-expected-attendees = 300
+//	meta_b3scale-tags = sip,premium
+//
+// Only hints allowed in the routing_hints frontend
+// settings are accepted. Requested tags are required
+// from the backends. The hints are passed on in the
+// context, so the expected attendees can be considered
+// when checking capacity limits and sorting by load.
+//
+// This middleware should be used last.
+func Hints(next cluster.RouterHandler) cluster.RouterHandler {
+	return func(
+		ctx context.Context,
+		backends []*cluster.Backend,
+		req *bbb.Request,
+	) ([]*cluster.Backend, error) {
+		// This middleware only applies to create meeting requests
+		if req.Resource != bbb.ResourceCreate {
+			return next(ctx, backends, req) // pass
+		}
+
+		frontend := cluster.FrontendFromContext(ctx)
+		if frontend == nil {
+			return next(ctx, backends, req) // pass
+		}
+
+		hints := cluster.RoutingHintsFromParams(
+			req.Params, frontend.Settings().RoutingHints)
+		if hints == nil {
+			return next(ctx, backends, req) // pass
+		}
+
+		backends = filterRequiredTags(ctx, backends, hints.Tags)
+		ctx = cluster.ContextWithRoutingHints(ctx, hints)
+
+		return next(ctx, backends, req)
+	}
+}
@@ -0,0 +1,73 @@
+package routing
+
+import (
+	"context"
+	"testing"
+
+	"github.com/b3scale/b3scale/pkg/bbb"
+	"github.com/b3scale/b3scale/pkg/cluster"
+	"github.com/b3scale/b3scale/pkg/store"
+)
+
+func TestHints(t *testing.T) {
+	// Ten small meetings
+	b1 := cluster.NewBackend(&store.BackendState{
+		ID:             "A",
+		MeetingsCount:  10,
+		AttendeesCount: 20,
+		LoadFactor:     1,
+		Settings: store.BackendSettings{
+			Tags: []string{"lecture"},
+		},
+	})
+	// A single large meeting
+	b2 := cluster.NewBackend(&store.BackendState{
+		ID:             "B",
+		MeetingsCount:  1,
+		AttendeesCount: 100,
+		LoadFactor:     1,
+		Settings: store.BackendSettings{
+			Tags: []string{"lecture"},
+		},
+	})
+	b3 := cluster.NewBackend(&store.BackendState{
+		ID:         "C",
+		LoadFactor: 1,
+	})
+
+	fe := cluster.NewFrontend(&store.FrontendState{
+		Settings: store.FrontendSettings{
+			RoutingHints: &store.RoutingHintsSettings{
+				Allowed: []string{
+					cluster.HintExpectedAttendees,
+					cluster.HintTags,
+				},
+				AllowedTags: store.Tags{"lecture"},
+			},
+		},
+	})
+	ctx := cluster.ContextWithFrontend(context.Background(), fe)
+
+	handler := Hints(SortLoad(func(
+		ctx context.Context,
+		backends []*cluster.Backend,
+		req *bbb.Request,
+	) ([]*cluster.Backend, error) {
+		return backends, nil
+	}))
+
+	req := bbb.CreateRequest(bbb.Params{
+		cluster.MetaParamExpectedAttendees: "300",
+		cluster.MetaParamTags:              "lecture",
+	}, nil)
+	backends, err := handler(ctx, []*cluster.Backend{b1, b2, b3}, req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(backends) != 2 {
+		t.Fatal("unexpected backends:", backends)
+	}
+	if backends[0] != b1 || backends[1] != b2 {
+		t.Error("unexpected backends:", backends)
+	}
+}
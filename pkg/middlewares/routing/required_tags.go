@@ -30,7 +30,7 @@ func RequiredTags(next cluster.RouterHandler) cluster.RouterHandler {
 		}
 
 		tags := frontend.Settings().RequiredTags
-		backends = filterRequiredTags(backends, tags)
+		backends = filterRequiredTags(ctx, backends, tags)
 
 		return next(ctx, backends, req)
 	}
@@ -40,6 +40,7 @@ func RequiredTags(next cluster.RouterHandler) cluster.RouterHandler {
 // for a frontend from the configuration state and
 // removes backends not providing all of the tags
 func filterRequiredTags(
+	ctx context.Context,
 	backends []*cluster.Backend,
 	required []string,
 ) []*cluster.Backend {
@@ -47,6 +48,8 @@ func filterRequiredTags(
 	for _, be := range backends {
 		if be.HasTags(required) {
 			filtered = append(filtered, be)
+		} else {
+			cluster.RejectBackend(ctx, be, "missing required tags")
 		}
 	}
 	return filtered
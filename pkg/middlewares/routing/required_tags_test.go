@@ -1,6 +1,7 @@
 package routing
 
 import (
+	"context"
 	"testing"
 
 	"github.com/b3scale/b3scale/pkg/cluster"
@@ -9,7 +10,7 @@ import (
 func TestFilterRequiredTags(t *testing.T) {
 	b1 := &cluster.Backend{}
 	backends := []*cluster.Backend{b1}
-	filtered := filterRequiredTags(backends, []string{})
+	filtered := filterRequiredTags(context.Background(), backends, []string{})
 	if filtered[0] != b1 {
 		t.Error("unexpected:", filtered)
 	}
@@ -22,14 +22,24 @@ func (b BackendsByLoad) Less(i, j int) bool {
 	return b[i].Stress() < b[j].Stress()
 }
 
-// SortLoad sorts Backends by load
+// SortLoad sorts Backends by load. When routing hints
+// with expected attendees are present, the projected
+// load after creating the meeting is used.
 func SortLoad(next cluster.RouterHandler) cluster.RouterHandler {
 	return func(
 		ctx context.Context,
 		backends []*cluster.Backend,
 		req *bbb.Request,
 	) ([]*cluster.Backend, error) {
-		sort.Sort(BackendsByLoad(backends))
+		hints := cluster.RoutingHintsFromContext(ctx)
+		if hints == nil {
+			sort.Sort(BackendsByLoad(backends))
+			return next(ctx, backends, req)
+		}
+		sort.Slice(backends, func(i, j int) bool {
+			return backends[i].ProjectedStress(hints) <
+				backends[j].ProjectedStress(hints)
+		})
 		return next(ctx, backends, req)
 	}
 }
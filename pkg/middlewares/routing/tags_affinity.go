@@ -0,0 +1,105 @@
+package routing
+
+import (
+	"context"
+	"sort"
+
+	"github.com/b3scale/b3scale/pkg/bbb"
+	"github.com/b3scale/b3scale/pkg/cluster"
+)
+
+// TagsAffinity applies soft preferences and exclusions
+// defined in the frontend settings by the variables
+//
+//	preferred_tags = ["premium"]
+//	forbidden_tags = ["testing"]
+//
+// Backends with any of the forbidden tags are removed.
+// After the remaining middleware chain was applied, backends
+// are ranked by the number of preferred tags they provide.
+// The order of the backends is otherwise preserved, so
+// backends without preferred tags are used as a fallback.
+//
+// This middleware should be used last, as it needs the
+// result of the sorting middleware.
+func TagsAffinity(next cluster.RouterHandler) cluster.RouterHandler {
+	return func(
+		ctx context.Context,
+		backends []*cluster.Backend,
+		req *bbb.Request,
+	) ([]*cluster.Backend, error) {
+		// This middleware only applies to create meeting requests
+		if req.Resource != bbb.ResourceCreate {
+			return next(ctx, backends, req) // pass
+		}
+
+		frontend := cluster.FrontendFromContext(ctx)
+		if frontend == nil {
+			return next(ctx, backends, req) // pass
+		}
+		settings := frontend.Settings()
+
+		backends = filterForbiddenTags(ctx, backends, settings.ForbiddenTags)
+		backends, err := next(ctx, backends, req)
+		if err != nil {
+			return nil, err
+		}
+
+		return rankPreferredTags(backends, settings.PreferredTags), nil
+	}
+}
+
+// filterForbiddenTags removes all backends
+// providing any of the forbidden tags.
+func filterForbiddenTags(
+	ctx context.Context,
+	backends []*cluster.Backend,
+	forbidden []string,
+) []*cluster.Backend {
+	filtered := make([]*cluster.Backend, 0, len(backends))
+	for _, be := range backends {
+		if hasAnyTag(be, forbidden) {
+			cluster.RejectBackend(ctx, be, "has forbidden tags")
+			continue
+		}
+		filtered = append(filtered, be)
+	}
+	return filtered
+}
+
+// rankPreferredTags moves backends providing more of
+// the preferred tags to the front. The sort is stable, so
+// the order established by the load sorting is kept.
+func rankPreferredTags(
+	backends []*cluster.Backend,
+	preferred []string,
+) []*cluster.Backend {
+	if len(preferred) == 0 {
+		return backends
+	}
+	ranked := make([]*cluster.Backend, len(backends))
+	copy(ranked, backends)
+	sort.SliceStable(ranked, func(i, j int) bool {
+		return countTags(ranked[i], preferred) >
+			countTags(ranked[j], preferred)
+	})
+	return ranked
+}
+
+// hasAnyTag checks if the backend provides
+// at least one of the tags.
+func hasAnyTag(be *cluster.Backend, tags []string) bool {
+	return countTags(be, tags) > 0
+}
+
+// countTags counts the tags provided by the backend.
+// Empty tags are ignored.
+func countTags(be *cluster.Backend, tags []string) int {
+	n := 0
+	for _, tag := range tags {
+		if tag != "" && be.HasTag(tag) {
+			n++
+		}
+	}
+	return n
+}
@@ -0,0 +1,81 @@
+package routing
+
+import (
+	"context"
+	"testing"
+
+	"github.com/b3scale/b3scale/pkg/bbb"
+	"github.com/b3scale/b3scale/pkg/cluster"
+	"github.com/b3scale/b3scale/pkg/store"
+)
+
+func backendWithTags(id string, tags ...string) *cluster.Backend {
+	return cluster.NewBackend(&store.BackendState{
+		ID: id,
+		Settings: store.BackendSettings{
+			Tags: tags,
+		},
+	})
+}
+
+func TestFilterForbiddenTags(t *testing.T) {
+	b1 := backendWithTags("A", "shared")
+	b2 := backendWithTags("B", "shared", "testing")
+	b3 := backendWithTags("C")
+
+	filtered := filterForbiddenTags(context.Background(),
+		[]*cluster.Backend{b1, b2, b3}, []string{"testing", ""})
+	if len(filtered) != 2 {
+		t.Fatal("unexpected result:", filtered)
+	}
+	if filtered[0] != b1 || filtered[1] != b3 {
+		t.Error("unexpected result:", filtered)
+	}
+}
+
+func TestRankPreferredTags(t *testing.T) {
+	b1 := backendWithTags("A", "shared")
+	b2 := backendWithTags("B", "premium")
+	b3 := backendWithTags("C", "premium", "sip")
+	b4 := backendWithTags("D", "premium")
+
+	ranked := rankPreferredTags(
+		[]*cluster.Backend{b1, b2, b3, b4}, []string{"premium", "sip"})
+	if ranked[0] != b3 || ranked[1] != b2 ||
+		ranked[2] != b4 || ranked[3] != b1 {
+		t.Error("unexpected result:", ranked)
+	}
+}
+
+func TestTagsAffinity(t *testing.T) {
+	b1 := backendWithTags("A", "shared")
+	b2 := backendWithTags("B", "premium")
+	b3 := backendWithTags("C", "testing")
+
+	fe := cluster.NewFrontend(&store.FrontendState{
+		Settings: store.FrontendSettings{
+			PreferredTags: []string{"premium"},
+			ForbiddenTags: []string{"testing"},
+		},
+	})
+	ctx := cluster.ContextWithFrontend(context.Background(), fe)
+
+	handler := TagsAffinity(func(
+		ctx context.Context,
+		backends []*cluster.Backend,
+		req *bbb.Request,
+	) ([]*cluster.Backend, error) {
+		return backends, nil
+	})
+	req := bbb.CreateRequest(bbb.Params{}, nil)
+	backends, err := handler(ctx, []*cluster.Backend{b1, b2, b3}, req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(backends) != 2 {
+		t.Fatal("unexpected backends:", backends)
+	}
+	if backends[0] != b2 || backends[1] != b1 {
+		t.Error("unexpected backends:", backends)
+	}
+}
@@ -0,0 +1,76 @@
+package routing
+
+import (
+	"context"
+
+	"github.com/b3scale/b3scale/pkg/bbb"
+	"github.com/b3scale/b3scale/pkg/cluster"
+	"github.com/b3scale/b3scale/pkg/metrics"
+)
+
+// Zone prefers backends in the zone of the frontend
+// defined in the frontend settings by the variable
+//
+//	zone = "dc1"
+//
+// The backends passed to the router are ready and
+// have a live agent heartbeat. Only if none of them is
+// in the zone of the frontend, other zones are considered.
+// Meetings created in another zone are counted in
+// the b3scale_routing_cross_zone_total metric, unless
+// the routing is only simulated.
+func Zone(next cluster.RouterHandler) cluster.RouterHandler {
+	return func(
+		ctx context.Context,
+		backends []*cluster.Backend,
+		req *bbb.Request,
+	) ([]*cluster.Backend, error) {
+		// This middleware only applies to create meeting requests
+		if req.Resource != bbb.ResourceCreate {
+			return next(ctx, backends, req) // pass
+		}
+
+		frontend := cluster.FrontendFromContext(ctx)
+		if frontend == nil {
+			return next(ctx, backends, req) // pass
+		}
+		zone := frontend.Settings().Zone
+		if zone == "" {
+			return next(ctx, backends, req) // pass
+		}
+
+		local := filterZone(ctx, backends, zone)
+		if len(local) > 0 {
+			return next(ctx, local, req)
+		}
+
+		// Fall back to other zones
+		backends, err := next(ctx, backends, req)
+		if err != nil {
+			return nil, err
+		}
+		if len(backends) > 0 && !cluster.IsRoutingSimulation(ctx) {
+			metrics.CrossZoneRoutingTotal.
+				WithLabelValues(zone, backends[0].Settings().Zone).
+				Inc()
+		}
+		return backends, nil
+	}
+}
+
+// filterZone selects all backends in the zone
+func filterZone(
+	ctx context.Context,
+	backends []*cluster.Backend,
+	zone string,
+) []*cluster.Backend {
+	filtered := make([]*cluster.Backend, 0, len(backends))
+	for _, be := range backends {
+		if be.Settings().Zone == zone {
+			filtered = append(filtered, be)
+		} else {
+			cluster.RejectBackend(ctx, be, "not in zone "+zone)
+		}
+	}
+	return filtered
+}
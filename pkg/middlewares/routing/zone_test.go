@@ -0,0 +1,77 @@
+package routing
+
+import (
+	"context"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+
+	"github.com/b3scale/b3scale/pkg/bbb"
+	"github.com/b3scale/b3scale/pkg/cluster"
+	"github.com/b3scale/b3scale/pkg/metrics"
+	"github.com/b3scale/b3scale/pkg/store"
+)
+
+func backendInZone(id, zone string) *cluster.Backend {
+	return cluster.NewBackend(&store.BackendState{
+		ID: id,
+		Settings: store.BackendSettings{
+			Zone: zone,
+		},
+	})
+}
+
+func TestZone(t *testing.T) {
+	b1 := backendInZone("A", "dc1")
+	b2 := backendInZone("B", "dc2")
+	b3 := backendInZone("C", "dc1")
+
+	fe := cluster.NewFrontend(&store.FrontendState{
+		Settings: store.FrontendSettings{
+			Zone: "dc2",
+		},
+	})
+	ctx := cluster.ContextWithFrontend(context.Background(), fe)
+
+	handler := Zone(func(
+		ctx context.Context,
+		backends []*cluster.Backend,
+		req *bbb.Request,
+	) ([]*cluster.Backend, error) {
+		return backends, nil
+	})
+	req := bbb.CreateRequest(bbb.Params{}, nil)
+
+	backends, err := handler(ctx, []*cluster.Backend{b1, b2, b3}, req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(backends) != 1 || backends[0] != b2 {
+		t.Error("unexpected backends:", backends)
+	}
+
+	// Fall back to other zones
+	counter := metrics.CrossZoneRoutingTotal.WithLabelValues("dc2", "dc1")
+	before := testutil.ToFloat64(counter)
+	backends, err = handler(ctx, []*cluster.Backend{b1, b3}, req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(backends) != 2 {
+		t.Error("unexpected backends:", backends)
+	}
+	if testutil.ToFloat64(counter) != before+1 {
+		t.Error("cross zone routing was not counted")
+	}
+
+	// Simulations are not counted
+	simCtx := cluster.ContextWithRoutingTrace(
+		ctx, cluster.NewRoutingSimulationTrace())
+	before = testutil.ToFloat64(counter)
+	if _, err := handler(simCtx, []*cluster.Backend{b1, b3}, req); err != nil {
+		t.Fatal(err)
+	}
+	if testutil.ToFloat64(counter) != before {
+		t.Error("simulated cross zone routing was counted")
+	}
+}
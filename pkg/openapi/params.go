@@ -25,3 +25,16 @@ func ParamQuery(name, description string) Schema {
 		},
 	}
 }
+
+// ParamPath creates a required path parameter
+func ParamPath(name, description string) Schema {
+	return Schema{
+		"name":        name,
+		"in":          "path",
+		"description": description,
+		"required":    true,
+		"schema": Schema{
+			"type": "string",
+		},
+	}
+}
@@ -25,7 +25,7 @@ type BackendState struct {
 	ID string `json:"id"`
 
 	NodeState  string `json:"node_state" doc:"The current state of the node." example:"ready" enum:"init,ready,error,stopped,decommissioned"`
-	AdminState string `json:"admin_state" doc:"The desired state of the node. If none given, it will be assumed 'ready'." example:"ready" enum:"init,ready,stopped,decommissioned"`
+	AdminState string `json:"admin_state" doc:"The desired state of the node. If none given, it will be assumed 'ready'. A draining node will not receive new meetings and empty meetings are ended." example:"ready" enum:"init,ready,stopped,draining,decommissioned"`
 
 	AgentHeartbeat time.Time `json:"agent_heartbeat" doc:"The last time we heared from the node agent."`
 	AgentRef       *string   `json:"agent_ref" doc:"The identifier of the agent running on the backend. Used for backend authorization and agent authentication."`
@@ -36,17 +36,31 @@ type BackendState struct {
 	MeetingsCount  uint          `json:"meetings_count" doc:"Number of meetings on the backend."`
 	AttendeesCount uint          `json:"attendees_count" doc:"Number of participants in meetings on the backend."`
 
+	VideoCount            uint `json:"video_count" doc:"Number of video streams in meetings on the backend."`
+	VoiceParticipantCount uint `json:"voice_participant_count" doc:"Number of participants who joined with audio in meetings on the backend."`
+
 	LoadFactor float64 `json:"load_factor" doc:"The load factor influences the probability of selecting this backend when a meeting is created. The amount of meetings and attendees on the node will be multiplied with the load factor, when calculating the backend stress."`
 
 	Backend *bbb.Backend `json:"bbb" api:"BackendConfig"`
 
 	Settings BackendSettings `json:"settings"`
 
+	Drain *DrainProgress `json:"drain" doc:"The progress of draining the backend. Only present while the backend is draining or decommissioned."`
+
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
 	SyncedAt  time.Time `json:"synced_at"`
 }
 
+// DrainProgress reports how many meetings were
+// ended since the backend started draining.
+type DrainProgress struct {
+	StartedAt         time.Time `json:"started_at"`
+	MeetingsTotal     int       `json:"meetings_total" doc:"Number of meetings on the backend when draining started."`
+	MeetingsEnded     int       `json:"meetings_ended" doc:"Number of empty meetings ended while draining."`
+	MeetingsRemaining int       `json:"meetings_remaining" doc:"Number of meetings still on the backend."`
+}
+
 // AgentHeartbeat is a short api response
 type AgentHeartbeat struct {
 	BackendID string    `json:"backend_id"`
@@ -88,10 +102,15 @@ func GetBackendStates(
 		"backends.latency",
 		"backends.meetings_count",
 		"backends.attendees_count",
+		"backends.video_count",
+		"backends.voice_participant_count",
 		"backends.load_factor",
 		"backends.host",
 		"backends.secret",
 		"backends.settings",
+		"backends.drain_started_at",
+		"backends.drain_meetings_total",
+		"backends.drain_meetings_ended",
 		"backends.created_at",
 		"backends.updated_at",
 		"backends.synced_at").
@@ -106,6 +125,8 @@ func GetBackendStates(
 	results := make([]*BackendState, 0, cmd.RowsAffected())
 	for rows.Next() {
 		state := InitBackendState(&BackendState{})
+		drain := &DrainProgress{}
+		var drainStartedAt *time.Time
 		err := rows.Scan(
 			&state.ID,
 			&state.NodeState,
@@ -116,16 +137,26 @@ func GetBackendStates(
 			&state.Latency,
 			&state.MeetingsCount,
 			&state.AttendeesCount,
+			&state.VideoCount,
+			&state.VoiceParticipantCount,
 			&state.LoadFactor,
 			&state.Backend.Host,
 			&state.Backend.Secret,
 			&state.Settings,
+			&drainStartedAt,
+			&drain.MeetingsTotal,
+			&drain.MeetingsEnded,
 			&state.CreatedAt,
 			&state.UpdatedAt,
 			&state.SyncedAt)
 		if err != nil {
 			return nil, err
 		}
+		if drainStartedAt != nil {
+			drain.StartedAt = *drainStartedAt
+			drain.MeetingsRemaining = int(state.MeetingsCount)
+			state.Drain = drain
+		}
 		results = append(results, state)
 	}
 
@@ -319,6 +350,62 @@ func (s *BackendState) UpdateAgentHeartbeat(
 	return heartbeat, nil
 }
 
+// StartDrain marks the beginning of draining the backend.
+// The result is true if the drain was not started before.
+func (s *BackendState) StartDrain(
+	ctx context.Context,
+	tx pgx.Tx,
+) (bool, error) {
+	qry := `
+		UPDATE backends
+		   SET drain_started_at     = $2,
+		       drain_meetings_total = meetings_count,
+		       drain_meetings_ended = 0
+		 WHERE id = $1
+		   AND drain_started_at IS NULL
+	`
+	cmd, err := tx.Exec(ctx, qry, s.ID, time.Now().UTC())
+	if err != nil {
+		return false, err
+	}
+	return cmd.RowsAffected() > 0, nil
+}
+
+// AddDrainMeetingsEnded increments the number of
+// meetings ended while draining the backend.
+func (s *BackendState) AddDrainMeetingsEnded(
+	ctx context.Context,
+	tx pgx.Tx,
+	n int,
+) error {
+	qry := `
+		UPDATE backends
+		   SET drain_meetings_ended = drain_meetings_ended + $2
+		 WHERE id = $1
+	`
+	_, err := tx.Exec(ctx, qry, s.ID, n)
+	return err
+}
+
+// ResetBackendDrains clears the drain progress of all
+// backends, which are neither draining nor decommissioned.
+// This is the case, when a drain was canceled.
+func ResetBackendDrains(
+	ctx context.Context,
+	tx pgx.Tx,
+) error {
+	qry := `
+		UPDATE backends
+		   SET drain_started_at     = NULL,
+		       drain_meetings_total = 0,
+		       drain_meetings_ended = 0
+		 WHERE drain_started_at IS NOT NULL
+		   AND admin_state NOT IN ('draining', 'decommissioned')
+	`
+	_, err := tx.Exec(ctx, qry)
+	return err
+}
+
 // IsAgentAlive checks if the heartbeat is older
 // than the threshold
 func (s *BackendState) IsAgentAlive() bool {
@@ -349,6 +436,30 @@ func (s *BackendState) ClearMeetings(
 	return err
 }
 
+// DetachMeetings removes the association of all meetings
+// with the backend. The meetings are kept, so a subsequent
+// create with the same meeting ID can select a new backend.
+// The number of detached meetings is returned.
+func (s *BackendState) DetachMeetings(
+	ctx context.Context,
+	tx pgx.Tx,
+) (int64, error) {
+	qry := `
+		UPDATE meetings
+		   SET backend_id = NULL,
+		       updated_at = $2
+		 WHERE backend_id = $1
+	`
+	cmd, err := tx.Exec(ctx, qry, s.ID, time.Now().UTC())
+	if err != nil {
+		return 0, err
+	}
+	if err := updateBackendStatCounters(ctx, tx, s.ID); err != nil {
+		return 0, err
+	}
+	return cmd.RowsAffected(), nil
+}
+
 // Internal: updateBackendStatCounters counts meetings
 // and attendees for a given backendID
 func updateBackendStatCounters(
@@ -363,20 +474,28 @@ func updateBackendStatCounters(
 		return err
 	}
 
-	// Meeting and attendees counter
+	// Meeting, attendees and media counter
 	mcount := len(mstates)
 	acount := 0
+	vcount := 0
+	vpcount := 0
 	for _, m := range mstates {
 		acount += len(m.Meeting.Attendees)
+		vcount += m.Meeting.VideoCount
+		vpcount += m.Meeting.VoiceParticipantCount
 	}
 
 	qry := `
 		UPDATE backends
 		   SET meetings_count = $2,
-		       attendees_count = $3
+		       attendees_count = $3,
+		       video_count = $4,
+		       voice_participant_count = $5
 		 WHERE backends.id = $1
 	`
-	if _, err := tx.Exec(ctx, qry, backendID, mcount, acount); err != nil {
+	if _, err := tx.Exec(
+		ctx, qry, backendID, mcount, acount, vcount, vpcount,
+	); err != nil {
 		return err
 	}
 
@@ -120,6 +120,45 @@ func TestCreateMeeting(t *testing.T) {
 	t.Log(mstate.ID)
 }
 
+func TestBackendStateDetachMeetings(t *testing.T) {
+	ctx := context.Background()
+	tx := beginTest(ctx, t)
+	defer tx.Rollback(ctx) //nolint
+
+	bstate := backendStateFactory()
+	if err := bstate.Save(ctx, tx); err != nil {
+		t.Fatal(err)
+	}
+	mstate, err := bstate.CreateMeetingState(ctx, tx, nil, &bbb.Meeting{
+		MeetingID:         uuid.New().String(),
+		InternalMeetingID: uuid.New().String(),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	count, err := bstate.DetachMeetings(ctx, tx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if count != 1 {
+		t.Error("unexpected count:", count)
+	}
+
+	if err := mstate.Refresh(ctx, tx); err != nil {
+		t.Fatal(err)
+	}
+	if mstate.BackendID != nil {
+		t.Error("meeting should be detached:", *mstate.BackendID)
+	}
+	if err := bstate.Refresh(ctx, tx); err != nil {
+		t.Fatal(err)
+	}
+	if bstate.MeetingsCount != 0 {
+		t.Error("unexpected meetings count:", bstate.MeetingsCount)
+	}
+}
+
 func TestBackendStateAgentHeartbeat(t *testing.T) {
 	ctx := context.Background()
 	tx := beginTest(ctx, t)
@@ -146,6 +185,64 @@ func TestBackendStateAgentHeartbeat(t *testing.T) {
 	}
 }
 
+func TestBackendStateDrain(t *testing.T) {
+	ctx := context.Background()
+	tx := beginTest(ctx, t)
+	defer tx.Rollback(ctx) //nolint
+
+	state := backendStateFactory()
+	state.AdminState = "draining"
+	if err := state.Save(ctx, tx); err != nil {
+		t.Fatal(err)
+	}
+	if state.Drain != nil {
+		t.Error("drain should not have started:", state.Drain)
+	}
+
+	started, err := state.StartDrain(ctx, tx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !started {
+		t.Error("expected drain to start")
+	}
+	started, err = state.StartDrain(ctx, tx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if started {
+		t.Error("drain should be started only once")
+	}
+
+	if err := state.AddDrainMeetingsEnded(ctx, tx, 2); err != nil {
+		t.Fatal(err)
+	}
+	if err := state.Refresh(ctx, tx); err != nil {
+		t.Fatal(err)
+	}
+	if state.Drain == nil {
+		t.Fatal("expected drain progress")
+	}
+	if state.Drain.MeetingsEnded != 2 {
+		t.Error("unexpected meetings ended:", state.Drain.MeetingsEnded)
+	}
+
+	// Cancel the drain
+	state.AdminState = "ready"
+	if err := state.Save(ctx, tx); err != nil {
+		t.Fatal(err)
+	}
+	if err := ResetBackendDrains(ctx, tx); err != nil {
+		t.Fatal(err)
+	}
+	if err := state.Refresh(ctx, tx); err != nil {
+		t.Fatal(err)
+	}
+	if state.Drain != nil {
+		t.Error("drain should be reset:", state.Drain)
+	}
+}
+
 func TestBackendStateValidate(t *testing.T) {
 	valid := &BackendState{
 		Backend: &bbb.Backend{
@@ -0,0 +1,72 @@
+package store
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v4"
+)
+
+// A CommandObserver is notified about processed
+// commands, e.g. for collecting metrics.
+type CommandObserver interface {
+	// CommandProcessed is called after an attempt with
+	// the new state of the command.
+	CommandProcessed(cmd *Command, state string, duration time.Duration)
+
+	// CommandExpired is called when the deadline of the
+	// command was reached before it was processed.
+	CommandExpired(cmd *Command)
+}
+
+var (
+	commandObserver    CommandObserver
+	commandObserverMtx sync.RWMutex
+)
+
+// SetCommandObserver registers an observer for
+// processed commands.
+func SetCommandObserver(o CommandObserver) {
+	commandObserverMtx.Lock()
+	defer commandObserverMtx.Unlock()
+	commandObserver = o
+}
+
+// getCommandObserver returns the registered observer
+func getCommandObserver() CommandObserver {
+	commandObserverMtx.RLock()
+	defer commandObserverMtx.RUnlock()
+	return commandObserver
+}
+
+// A CommandPublisher is called with the transaction of
+// a processed command, before it is committed. Expired
+// commands are published with the error state.
+type CommandPublisher func(
+	ctx context.Context,
+	tx pgx.Tx,
+	cmd *Command,
+	state string,
+	duration time.Duration,
+) error
+
+var (
+	commandPublisher    CommandPublisher
+	commandPublisherMtx sync.RWMutex
+)
+
+// SetCommandPublisher registers a publisher for
+// processed commands.
+func SetCommandPublisher(p CommandPublisher) {
+	commandPublisherMtx.Lock()
+	defer commandPublisherMtx.Unlock()
+	commandPublisher = p
+}
+
+// getCommandPublisher returns the registered publisher
+func getCommandPublisher() CommandPublisher {
+	commandPublisherMtx.RLock()
+	defer commandPublisherMtx.RUnlock()
+	return commandPublisher
+}
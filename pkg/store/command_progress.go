@@ -0,0 +1,101 @@
+package store
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// ErrCommandCanceled is returned by a command handler,
+// when the command was canceled while running.
+var ErrCommandCanceled = errors.New("command canceled")
+
+// CommandProgress is the result of a command processing
+// multiple items. Failures are collected per item.
+type CommandProgress struct {
+	Total    int               `json:"total"`
+	Done     int               `json:"done"`
+	Failed   int               `json:"failed"`
+	Failures map[string]string `json:"failures,omitempty"`
+	Message  string            `json:"message"`
+
+	unit string
+	mtx  sync.Mutex
+}
+
+// NewCommandProgress creates a progress for a number
+// of items. The unit describes the done items, e.g.
+// "meetings ended" results in "12/40 meetings ended".
+func NewCommandProgress(total int, unit string) *CommandProgress {
+	p := &CommandProgress{
+		Total:    total,
+		Failures: map[string]string{},
+		unit:     unit,
+	}
+	p.update()
+	return p
+}
+
+// update the message
+func (p *CommandProgress) update() {
+	p.Message = fmt.Sprintf("%d/%d %s", p.Done, p.Total, p.unit)
+	if p.Failed > 0 {
+		p.Message += fmt.Sprintf(", %d failed", p.Failed)
+	}
+}
+
+// Succeed marks an item as done
+func (p *CommandProgress) Succeed() {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+	p.Done++
+	p.update()
+}
+
+// Fail records the error for an item
+func (p *CommandProgress) Fail(item string, err error) {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+	p.Failed++
+	p.Failures[item] = err.Error()
+	p.update()
+}
+
+// ReportProgress stores the progress of a running command,
+// so it can be retrieved while the command is processed.
+// The result is true if the command should be canceled.
+//
+// The progress is written on the connection of the command
+// handler from the context, not in the transaction of the
+// command. It must not be reported while the handler has a
+// transaction open on the connection.
+func (cmd *Command) ReportProgress(
+	ctx context.Context,
+	progress interface{},
+) (bool, error) {
+	conn := ConnectionFromContext(ctx)
+	data, err := json.Marshal(progress)
+	if err != nil {
+		return false, err
+	}
+	qry := `
+		INSERT INTO command_progress (
+			command_id,
+			progress_result
+		) VALUES ($1, $2)
+		ON CONFLICT (command_id) DO UPDATE
+		   SET progress_result = EXCLUDED.progress_result,
+		       updated_at      = $3
+		RETURNING cancel_requested`
+	var cancel bool
+	if err := conn.QueryRow(ctx, qry,
+		cmd.ID,
+		data,
+		time.Now().UTC()).Scan(&cancel); err != nil {
+		return false, err
+	}
+	return cancel, nil
+}
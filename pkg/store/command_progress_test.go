@@ -0,0 +1,92 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestCommandProgress(t *testing.T) {
+	p := NewCommandProgress(3, "meetings ended")
+	if p.Message != "0/3 meetings ended" {
+		t.Error("unexpected message:", p.Message)
+	}
+	p.Succeed()
+	p.Fail("meeting2", errors.New("notFound"))
+	if p.Done != 1 || p.Failed != 1 {
+		t.Error("unexpected progress:", p)
+	}
+	if p.Failures["meeting2"] != "notFound" {
+		t.Error("unexpected failures:", p.Failures)
+	}
+	if p.Message != "1/3 meetings ended, 1 failed" {
+		t.Error("unexpected message:", p.Message)
+	}
+}
+
+func TestCancelRunningCommand(t *testing.T) {
+	ctx := context.Background()
+	tx, err := begin(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tx.Rollback(ctx) //nolint
+	cmd := &Command{Action: "test_cancel_running"}
+	if err := QueueCommand(ctx, tx, cmd); err != nil {
+		t.Fatal(err)
+	}
+	if err := tx.Commit(ctx); err != nil {
+		t.Fatal(err)
+	}
+	defer pool.Exec(ctx, "DELETE FROM commands WHERE id = $1", cmd.ID)                 //nolint
+	defer pool.Exec(ctx, "DELETE FROM command_progress WHERE command_id = $1", cmd.ID) //nolint
+
+	// Lock the command as if it was running
+	running, err := begin(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer running.Rollback(ctx) //nolint
+	qry := `SELECT id FROM commands WHERE id = $1 FOR UPDATE`
+	if _, err := running.Exec(ctx, qry, cmd.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	// Progress is reported on the connection of the handler
+	conn, err := Acquire(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Release()
+	handlerCtx := ContextWithConnection(ctx, conn)
+
+	progress := NewCommandProgress(2, "items")
+	cancel, err := cmd.ReportProgress(handlerCtx, progress)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cancel {
+		t.Error("command should not be canceled yet")
+	}
+
+	tx, err = begin(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tx.Rollback(ctx) //nolint
+	if err := CancelCommand(ctx, tx, cmd.ID); err != nil {
+		t.Fatal(err)
+	}
+	if err := tx.Commit(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	progress.Succeed()
+	cancel, err = cmd.ReportProgress(handlerCtx, progress)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !cancel {
+		t.Error("expected cancel request")
+	}
+}
@@ -11,6 +11,7 @@ package store
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"time"
 
@@ -22,6 +23,46 @@ import (
 	"github.com/jackc/pgx/v4/pgxpool"
 )
 
+const (
+	// CommandsQueueChannel is notified, when a
+	// new command was queued.
+	CommandsQueueChannel = "commands_queue"
+
+	// CommandsPollInterval is the interval for checking the
+	// queue, in case a notification was missed.
+	CommandsPollInterval = 5 * time.Second
+
+	// DefaultCommandTimeout is used for calculating the
+	// deadline of commands without an explicit deadline.
+	DefaultCommandTimeout = 120 * time.Second
+
+	// commandsListenRetryInterval is the time to wait before
+	// listening again after the connection was lost.
+	commandsListenRetryInterval = 5 * time.Second
+)
+
+var (
+	// ErrCommandNotRequeueable is returned when a command
+	// does not exist, did not fail or a command with the same
+	// idempotency key is pending.
+	ErrCommandNotRequeueable = errors.New("command not found, not failed or already pending")
+
+	// ErrCommandNotPending is returned when a command
+	// can not be canceled, because it does not exist or
+	// was already processed.
+	ErrCommandNotPending = errors.New("command not found or not pending")
+)
+
+// Command states
+const (
+	CommandStateRequested = "requested"
+	CommandStateRunning   = "running"
+	CommandStateSuccess   = "success"
+	CommandStateError     = "error"
+	CommandStateDead      = "dead"
+	CommandStateCanceled  = "canceled"
+)
+
 // CommandHandler is a callback function for handling
 // commands. The command was successful if no error was
 // returned.
@@ -32,12 +73,21 @@ type Command struct {
 	ID  string `json:"id"`
 	Seq int    `json:"seq"`
 
-	State string `json:"state" doc:"The current state of the command." enum:"requested,success,error"`
+	State string `json:"state" doc:"The current state of the command. Commands with a retry policy are dead, when all attempts failed." enum:"requested,success,error,dead,canceled"`
 
 	Action string      `json:"action" doc:"The operation to perform." enum:"end_all_meetings"`
 	Params interface{} `json:"params" doc:"Key value options for the command. See example above."`
-	Result interface{} `json:"result" doc:"The result of the command. as key value object."`
+	Result interface{} `json:"result" doc:"The result of the command. as key value object. While the command is running, this is the reported progress."`
+
+	CancelRequested bool `json:"cancel_requested" doc:"The command is running and was asked to stop."`
 
+	IdempotencyKey *string `json:"idempotency_key" doc:"Optional. While a command with the same key is pending and not yet started, queuing the command is a no-op."`
+
+	Attempts    int     `json:"attempts" doc:"The number of times the command was executed."`
+	MaxAttempts int     `json:"max_attempts" doc:"The maximum number of attempts, defined by the retry policy of the action."`
+	LastError   *string `json:"last_error" doc:"The error of the last failed attempt."`
+
+	NotBefore time.Time  `json:"not_before" doc:"The command will not be processed before this time. Failed attempts are retried with an increasing delay."`
 	Deadline  time.Time  `json:"deadline" doc:"The commands need to be processed before the deadline is reached. The deadline is optional."`
 	StartedAt *time.Time `json:"started_at"`
 	StoppedAt *time.Time `json:"stopped_at"`
@@ -67,54 +117,225 @@ func NewCommandQueue() *CommandQueue {
 
 // QueueCommand adds a new command to the queue
 func QueueCommand(ctx context.Context, tx pgx.Tx, cmd *Command) error {
-	// Our command will always expire. For now 2 minutes.
-	deadline := time.Now().UTC().Add(120 * time.Second)
+	now := time.Now().UTC()
+
+	notBefore := cmd.NotBefore
+	if notBefore.Before(now) {
+		notBefore = now
+	}
+
+	// Our command will always expire. If no deadline
+	// was given, the default timeout is used. For delayed
+	// commands, the timeout starts at not_before.
+	timeout := DefaultCommandTimeout
+	if !cmd.Deadline.IsZero() {
+		timeout = cmd.Deadline.Sub(now)
+	}
+	deadline := notBefore.Add(timeout)
+	policy := GetCommandRetryPolicy(cmd.Action)
+
 	// Marshal payload
 	params, err := json.Marshal(cmd.Params)
 	if err != nil {
 		return err
 	}
 
-	// Add command to queue and notify instances
+	// When a command with the same idempotency key is
+	// pending, nothing is inserted.
+	if cmd.IdempotencyKey != nil {
+		coalesced, err := coalesceCommand(ctx, tx, cmd, notBefore)
+		if err != nil || coalesced {
+			return err
+		}
+	}
+
+	// Add command to queue and notify instances.
 	qry := `
 	  INSERT INTO commands (
 	  	action,
 		params,
-		deadline
+		deadline,
+		not_before,
+		max_attempts,
+		idempotency_key
 	  ) VALUES (
-		$1, $2, $3
+		$1, $2, $3, $4, $5, $6
 	  )
 	  RETURNING id`
 	var cmdID string
-	err = tx.QueryRow(ctx, qry, cmd.Action, params, deadline).
-		Scan(&cmdID)
+	err = tx.QueryRow(ctx, qry,
+		cmd.Action,
+		params,
+		deadline,
+		notBefore,
+		policy.MaxAttempts,
+		cmd.IdempotencyKey).Scan(&cmdID)
 	if err != nil {
 		return err
 	}
 
+	if err := notifyCommandQueued(ctx, tx, cmd.Action); err != nil {
+		return err
+	}
+
 	// Update command
 	cmd.ID = cmdID
-	cmd.CreatedAt = time.Now().UTC()
+	cmd.State = CommandStateRequested
+	cmd.Deadline = deadline
+	cmd.NotBefore = notBefore
+	cmd.MaxAttempts = policy.MaxAttempts
+	cmd.CreatedAt = now
 	return nil
 }
 
-// StartReceive spawns command queue workers and triggers periodical
-// polling of the event queue.
+// coalesceCommand updates the command with a pending
+// command sharing the idempotency key. Only commands that
+// were not started and are due before the new command are
+// considered: a running command or a command waiting for
+// a retry might miss the change the new command is for.
+// Running commands are locked and skipped.
+func coalesceCommand(
+	ctx context.Context,
+	tx pgx.Tx,
+	cmd *Command,
+	notBefore time.Time,
+) (bool, error) {
+	qry := `
+		SELECT id
+		  FROM commands
+		 WHERE idempotency_key = $1
+		   AND state = 'requested'
+		   AND attempts = 0
+		   AND not_before <= $2
+		 ORDER BY seq ASC
+		 LIMIT 1
+		   FOR UPDATE SKIP LOCKED`
+	var id string
+	err := tx.QueryRow(ctx, qry, *cmd.IdempotencyKey, notBefore).Scan(&id)
+	if err == pgx.ErrNoRows {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	pending, err := GetCommand(ctx, tx, Q().Where("id = ?", id))
+	if err != nil {
+		return false, err
+	}
+	log.Debug().
+		Str("action", cmd.Action).
+		Str("key", *cmd.IdempotencyKey).
+		Str("id", pending.ID).
+		Msg("coalesced command with pending command")
+	*cmd = *pending
+	return true, nil
+}
+
+// notifyCommandQueued wakes up receivers on all instances.
+// The notification is delivered when the transaction
+// is committed.
+func notifyCommandQueued(ctx context.Context, tx pgx.Tx, action string) error {
+	qry := `SELECT pg_notify($1, $2)`
+	_, err := tx.Exec(ctx, qry, CommandsQueueChannel, action)
+	return err
+}
+
+// CancelCommand cancels a pending command. A running
+// command is asked to stop; the command handler
+// has to check for the cancellation.
+func CancelCommand(ctx context.Context, tx pgx.Tx, id string) error {
+	// Commands are locked while running
+	qry := `
+		UPDATE commands
+		   SET state      = 'canceled',
+		       stopped_at = $2
+		 WHERE id IN (
+		       SELECT id FROM commands
+		        WHERE id = $1
+		          AND state = 'requested'
+		          FOR UPDATE SKIP LOCKED)`
+	tag, err := tx.Exec(ctx, qry, id, time.Now().UTC())
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() > 0 {
+		return nil
+	}
+
+	// Check if the command is running
+	var running bool
+	qry = `
+		SELECT EXISTS (
+			SELECT 1 FROM commands
+			 WHERE id = $1
+			   AND state = 'requested')`
+	if err := tx.QueryRow(ctx, qry, id).Scan(&running); err != nil {
+		return err
+	}
+	if !running {
+		return ErrCommandNotPending
+	}
+	qry = `
+		INSERT INTO command_progress (
+			command_id,
+			cancel_requested
+		) VALUES ($1, true)
+		ON CONFLICT (command_id) DO UPDATE
+		   SET cancel_requested = true,
+		       updated_at       = $2`
+	_, err = tx.Exec(ctx, qry, id, time.Now().UTC())
+	return err
+}
+
+// RequeueCommand puts a failed or dead command back
+// into the queue. The attempts are reset, the last error
+// is kept until the next attempt.
+func RequeueCommand(ctx context.Context, tx pgx.Tx, id string) error {
+	now := time.Now().UTC()
+	qry := `
+		UPDATE commands
+		   SET state      = 'requested',
+		       attempts   = 0,
+		       not_before = $2,
+		       deadline   = $2::timestamp + (deadline - created_at),
+		       started_at = NULL,
+		       stopped_at = NULL
+		 WHERE id = $1
+		   AND state IN ('error', 'dead')
+		   AND NOT EXISTS (
+		         SELECT 1 FROM commands AS pending
+		          WHERE pending.state = 'requested'
+		            AND pending.idempotency_key = commands.idempotency_key)
+		RETURNING action`
+	var action string
+	if err := tx.QueryRow(ctx, qry, id, now).Scan(&action); err != nil {
+		if err == pgx.ErrNoRows {
+			return ErrCommandNotRequeueable
+		}
+		return err
+	}
+	return notifyCommandQueued(ctx, tx, action)
+}
+
+// StartReceive spawns command queue workers and listens
+// for notifications about queued commands.
 //
-// Each worker awaits a polling event and will then try to process new command
-// within a given deadline.
+// A notification wakes a worker, which will process commands
+// until the queue is empty. In case a notification was missed,
+// the queue is polled in a slow interval.
 func (q *CommandQueue) StartReceive(ctx context.Context, handler CommandHandler) {
-	// Limit the polling interval to 15 Hz
-	ticker := time.NewTicker(66 * time.Millisecond)
+	ticker := time.NewTicker(CommandsPollInterval)
 	events := ticker.C
 
 	// Spawn workers
 	poolSize := config.GetCmdWorkerPoolSize()
 	log.Info().Int("pool_size", poolSize).Msg("starting command queue workers")
 
+	wake := make(chan struct{}, poolSize)
 	for range poolSize {
-		go q.startReceiveWorker(ctx, events, handler)
+		go q.startReceiveWorker(ctx, wake, events, handler)
 	}
+	go q.listen(ctx, wake)
 
 	<-ctx.Done()
 	ticker.Stop()
@@ -122,18 +343,100 @@ func (q *CommandQueue) StartReceive(ctx context.Context, handler CommandHandler)
 
 func (q *CommandQueue) startReceiveWorker(
 	ctx context.Context,
+	wake chan struct{},
 	poll <-chan time.Time,
 	handler CommandHandler,
 ) {
 	for {
 		select {
+		case <-wake:
 		case <-poll:
-			if err := q.receive(ctx, handler); err != nil {
-				log.Error().Err(err).Msg("command queue receive failed")
-			}
 		case <-ctx.Done():
 			return
 		}
+		q.receiveAll(ctx, wake, handler)
+	}
+}
+
+// receiveAll processes commands until the queue is empty.
+// As long as there are commands, another worker is woken up
+// to help processing the queue.
+func (q *CommandQueue) receiveAll(
+	ctx context.Context,
+	wake chan struct{},
+	handler CommandHandler,
+) {
+	for {
+		ok, err := q.receive(ctx, handler)
+		if err != nil {
+			log.Error().Err(err).Msg("command queue receive failed")
+			return
+		}
+		if !ok {
+			return
+		}
+		wakeWorker(wake)
+	}
+}
+
+// wakeWorker signals a waiting worker without blocking
+func wakeWorker(wake chan<- struct{}) {
+	select {
+	case wake <- struct{}{}:
+	default: // all workers are busy or already signaled
+	}
+}
+
+// listen waits for notifications about queued commands
+// and wakes the workers. When the connection is lost,
+// listening is retried.
+func (q *CommandQueue) listen(ctx context.Context, wake chan<- struct{}) {
+	for {
+		err := q.waitForNotifications(ctx, wake)
+		if ctx.Err() != nil {
+			return
+		}
+		log.Error().Err(err).Msg("listening for commands failed, retrying")
+
+		select {
+		case <-time.After(commandsListenRetryInterval):
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// waitForNotifications uses a dedicated connection
+// for listening on the commands queue channel.
+func (q *CommandQueue) waitForNotifications(
+	ctx context.Context,
+	wake chan<- struct{},
+) error {
+	pconn, err := Acquire(ctx)
+	if err != nil {
+		return err
+	}
+	// The connection is in listening state and
+	// should not be returned to the pool.
+	conn := pconn.Hijack()
+	defer conn.Close(context.Background()) //nolint
+
+	if _, err := conn.Exec(ctx, "LISTEN "+CommandsQueueChannel); err != nil {
+		return err
+	}
+	log.Debug().
+		Str("channel", CommandsQueueChannel).
+		Msg("listening for queued commands")
+
+	// Commands might have been queued while we
+	// were not listening.
+	wakeWorker(wake)
+
+	for {
+		if _, err := conn.WaitForNotification(ctx); err != nil {
+			return err
+		}
+		wakeWorker(wake)
 	}
 }
 
@@ -197,12 +500,19 @@ func GetCommands(
 		"state",
 		"action",
 		"params",
-		"result",
+		"COALESCE(result, progress_result)",
+		"COALESCE(cancel_requested, false)",
+		"idempotency_key",
+		"attempts",
+		"max_attempts",
+		"last_error",
+		"not_before",
 		"deadline",
 		"created_at",
 		"started_at",
 		"stopped_at").
 		From("commands").
+		LeftJoin("command_progress ON command_progress.command_id = commands.id").
 		ToSql()
 	rows, err := tx.Query(ctx, qry, params...)
 	if err != nil {
@@ -221,6 +531,12 @@ func GetCommands(
 			&cmd.Action,
 			&cmd.Params,
 			&cmd.Result,
+			&cmd.CancelRequested,
+			&cmd.IdempotencyKey,
+			&cmd.Attempts,
+			&cmd.MaxAttempts,
+			&cmd.LastError,
+			&cmd.NotBefore,
 			&cmd.Deadline,
 			&cmd.CreatedAt,
 			&cmd.StartedAt,
@@ -236,7 +552,10 @@ func GetCommands(
 // Receive will dequeue a command and apply the
 // handler function to it. If not command was dequeued 'false'
 // will be returned.
-func (q *CommandQueue) receive(ctx context.Context, handler CommandHandler) error {
+func (q *CommandQueue) receive(
+	ctx context.Context,
+	handler CommandHandler,
+) (bool, error) {
 	// Begin with a total timelimit of X seconds for the entire command. The
 	// safeExecHandler  will instanciate a child context with a stricter
 	// timelimit of Y < X seconds for the job to complete.
@@ -246,7 +565,7 @@ func (q *CommandQueue) receive(ctx context.Context, handler CommandHandler) erro
 	startedAt := time.Now().UTC()
 	tx, err := begin(ctx) // Command TX
 	if err != nil {
-		return err
+		return false, err
 	}
 	defer tx.Rollback(ctx) //nolint
 
@@ -257,48 +576,72 @@ func (q *CommandQueue) receive(ctx context.Context, handler CommandHandler) erro
 			id,
 			seq,
 			action,
+			attempts,
+			max_attempts,
 			deadline,
 			created_at
 		  FROM commands
 		 WHERE state = 'requested'
+		   AND not_before <= $1
 		 ORDER BY seq ASC
 		 LIMIT 1
 		   FOR UPDATE SKIP LOCKED`
 
 	// Select command
 	cmd := &Command{}
-	err = tx.QueryRow(ctx, qry).Scan(
+	err = tx.QueryRow(ctx, qry, startedAt).Scan(
 		&cmd.ID,
 		&cmd.Seq,
 		&cmd.Action,
+		&cmd.Attempts,
+		&cmd.MaxAttempts,
 		&cmd.Deadline,
 		&cmd.CreatedAt)
 	if err != nil && err == pgx.ErrNoRows {
-		return nil // Ok. There was just nothing to do.
+		return false, nil // Ok. There was just nothing to do.
 	} else if err != nil {
-		return err
+		return false, err
 	}
 
 	cmd.tx = tx
 
 	// Check deadline
-	state := "success"
-	var result interface{}
-	if cmd.Deadline.Before(time.Now().UTC()) {
+	state := CommandStateSuccess
+	var (
+		result    interface{}
+		lastError *string
+	)
+	notBefore := startedAt
+	deadline := cmd.Deadline
+	expired := cmd.Deadline.Before(time.Now().UTC())
+	if expired {
 		// Timeout
-		state = "error"
+		state = CommandStateError
 		result = "timedout"
 	} else {
 		// Apply command handler
+		cmd.Attempts++
 		result, err = safeExecHandler(ctx, cmd, handler)
-		if err != nil {
+		if errors.Is(err, ErrCommandCanceled) {
+			// Keep the partial result
+			log.Info().
+				Int("seq", cmd.Seq).
+				Str("action", cmd.Action).
+				Msg("command canceled")
+			state = CommandStateCanceled
+		} else if err != nil {
 			log.Error().
 				Err(err).
 				Int("seq", cmd.Seq).
 				Str("action", cmd.Action).
+				Int("attempt", cmd.Attempts).
 				Msg("exec command handler error")
-			state = "error"
-			result = fmt.Sprintf("%s", err)
+			errMsg := fmt.Sprintf("%s", err)
+			result = errMsg
+			lastError = &errMsg
+			state, notBefore = nextCommandAttempt(cmd, time.Now().UTC())
+			// The deadline is shifted by the backoff
+			deadline = deadline.Add(notBefore.Sub(startedAt))
 		}
 	}
 
@@ -306,7 +649,7 @@ func (q *CommandQueue) receive(ctx context.Context, handler CommandHandler) erro
 	stoppedAt := time.Now().UTC()
 	data, err := json.Marshal(result)
 	if err != nil {
-		return err
+		return false, err
 	}
 
 	// Write result
@@ -315,24 +658,74 @@ func (q *CommandQueue) receive(ctx context.Context, handler CommandHandler) erro
 		   SET state      = $2,
 		       result     = $3,
 			   started_at = $4,
-			   stopped_at = $5
+			   stopped_at = $5,
+			   attempts   = $6,
+			   last_error = COALESCE($7, last_error),
+			   not_before = $8,
+			   deadline   = $9
 
 		 WHERE id = $1`
 	_, err = tx.Exec(ctx, qry, cmd.ID,
 		state,
 		data,
 		startedAt,
-		stoppedAt)
+		stoppedAt,
+		cmd.Attempts,
+		lastError,
+		notBefore,
+		deadline)
 	if err != nil {
-		return err
+		return false, err
+	}
+
+	if expired {
+		if err := countCommandExpired(ctx, tx, cmd.Action); err != nil {
+			return false, err
+		}
+	}
+
+	// The progress is now part of the result
+	qry = `DELETE FROM command_progress WHERE command_id = $1`
+	if _, err := tx.Exec(ctx, qry, cmd.ID); err != nil {
+		return false, err
+	}
+
+	// Publish the new state with the result
+	if publish := getCommandPublisher(); publish != nil {
+		duration := stoppedAt.Sub(startedAt)
+		if err := publish(ctx, tx, cmd, state, duration); err != nil {
+			return false, err
+		}
 	}
 
 	// End transaction
 	err = tx.Commit(ctx)
 	if err != nil {
-		return err
+		return false, err
 	}
-	return nil
+
+	if observer := getCommandObserver(); observer != nil {
+		if expired {
+			observer.CommandExpired(cmd)
+		} else {
+			observer.CommandProcessed(cmd, state, stoppedAt.Sub(startedAt))
+		}
+	}
+	return true, nil
+}
+
+// nextCommandAttempt decides if a failed command is
+// attempted again, and when. Commands without retries end
+// in the error state, commands exhausting their retries are dead.
+func nextCommandAttempt(cmd *Command, now time.Time) (string, time.Time) {
+	policy := GetCommandRetryPolicy(cmd.Action)
+	if cmd.Attempts < cmd.MaxAttempts {
+		return CommandStateRequested, now.Add(policy.Delay(cmd.Attempts))
+	}
+	if cmd.MaxAttempts > 1 {
+		return CommandStateDead, now
+	}
+	return CommandStateError, now
 }
 
 // NextDeadline calculates the deadline for a
@@ -341,6 +734,98 @@ func NextDeadline(dt time.Duration) time.Time {
 	return time.Now().UTC().Add(dt)
 }
 
+// CommandQueueStats summarize the commands in
+// the queue with the same action and state.
+type CommandQueueStats struct {
+	Action string
+	State  string
+	Count  int
+
+	// OldestDue is the time since when the oldest
+	// requested command could have been processed.
+	OldestDue *time.Time
+}
+
+// GetCommandQueueStats counts the commands in the
+// queue by action and state.
+func GetCommandQueueStats(
+	ctx context.Context,
+	tx pgx.Tx,
+) ([]*CommandQueueStats, error) {
+	qry := `
+		SELECT action,
+		       state,
+		       COUNT(1),
+		       MIN(GREATEST(created_at, not_before))
+		         FILTER (WHERE state = 'requested'
+		                   AND not_before <= $1)
+		  FROM commands
+		 GROUP BY action, state`
+	rows, err := tx.Query(ctx, qry, time.Now().UTC())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	stats := []*CommandQueueStats{}
+	for rows.Next() {
+		s := &CommandQueueStats{}
+		if err := rows.Scan(
+			&s.Action,
+			&s.State,
+			&s.Count,
+			&s.OldestDue); err != nil {
+			return nil, err
+		}
+		stats = append(stats, s)
+	}
+	return stats, rows.Err()
+}
+
+// countCommandExpired increments the number of expired
+// commands for the action. Commands expiring while pending
+// are counted by the housekeeping when they are removed.
+func countCommandExpired(
+	ctx context.Context,
+	tx pgx.Tx,
+	action string,
+) error {
+	qry := `
+		INSERT INTO command_expirations (action, expired)
+		     VALUES ($1, 1)
+		ON CONFLICT (action) DO UPDATE
+		        SET expired = command_expirations.expired + 1`
+	_, err := tx.Exec(ctx, qry, action)
+	return err
+}
+
+// GetCommandExpirations retrieves the number of commands
+// not processed before their deadline by action.
+func GetCommandExpirations(
+	ctx context.Context,
+	tx pgx.Tx,
+) (map[string]int64, error) {
+	qry := `SELECT action, expired FROM command_expirations`
+	rows, err := tx.Query(ctx, qry)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	expirations := map[string]int64{}
+	for rows.Next() {
+		var (
+			action  string
+			expired int64
+		)
+		if err := rows.Scan(&action, &expired); err != nil {
+			return nil, err
+		}
+		expirations[action] = expired
+	}
+	return expirations, rows.Err()
+}
+
 // CountCommandsWithState retrievs the number of commands
 // in the queue with a given state. e.g. requested, error,
 // etc.
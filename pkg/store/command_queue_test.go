@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"testing"
+	"time"
 )
 
 func TestSafeExecHandler(t *testing.T) {
@@ -85,3 +86,264 @@ func TestCountCommandsRequested(t *testing.T) {
 	}
 
 }
+
+func TestQueueCommandNotify(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	conn, err := Acquire(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Release()
+	if _, err := conn.Exec(ctx, "LISTEN "+CommandsQueueChannel); err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Exec(context.Background(), "UNLISTEN *") //nolint
+
+	tx, err := begin(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tx.Rollback(ctx) //nolint
+	if err := QueueCommand(ctx, tx, &Command{Action: "test_notify"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := tx.Commit(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	n, err := conn.Conn().WaitForNotification(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n.Payload != "test_notify" {
+		t.Error("unexpected payload:", n.Payload)
+	}
+}
+
+func TestWakeWorker(t *testing.T) {
+	wake := make(chan struct{}, 1)
+	wakeWorker(wake)
+	wakeWorker(wake) // must not block
+	if len(wake) != 1 {
+		t.Error("expected one signal, got:", len(wake))
+	}
+}
+
+func TestRequeueCommand(t *testing.T) {
+	ctx := context.Background()
+	tx := beginTest(ctx, t)
+	defer tx.Rollback(ctx) //nolint
+
+	cmd := &Command{Action: "test_requeue"}
+	if err := QueueCommand(ctx, tx, cmd); err != nil {
+		t.Fatal(err)
+	}
+
+	// Only failed commands can be requeued
+	if err := RequeueCommand(ctx, tx, cmd.ID); err != ErrCommandNotRequeueable {
+		t.Error("expected ErrCommandNotRequeueable, got:", err)
+	}
+
+	qry := `
+		UPDATE commands
+		   SET state = 'dead', attempts = 3, last_error = 'failed'
+		 WHERE id = $1`
+	if _, err := tx.Exec(ctx, qry, cmd.ID); err != nil {
+		t.Fatal(err)
+	}
+	if err := RequeueCommand(ctx, tx, cmd.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	cmd, err := GetCommand(ctx, tx, Q().Where("id = ?", cmd.ID))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cmd.State != CommandStateRequested {
+		t.Error("unexpected state:", cmd.State)
+	}
+	if cmd.Attempts != 0 {
+		t.Error("unexpected attempts:", cmd.Attempts)
+	}
+	if cmd.LastError == nil || *cmd.LastError != "failed" {
+		t.Error("expected last error to be kept")
+	}
+}
+
+func TestQueueCommandIdempotencyKey(t *testing.T) {
+	ctx := context.Background()
+	tx := beginTest(ctx, t)
+	defer tx.Rollback(ctx) //nolint
+
+	key := "test_key:42"
+	cmd1 := &Command{Action: "test_key", IdempotencyKey: &key}
+	if err := QueueCommand(ctx, tx, cmd1); err != nil {
+		t.Fatal(err)
+	}
+	cmd2 := &Command{Action: "test_key", IdempotencyKey: &key}
+	if err := QueueCommand(ctx, tx, cmd2); err != nil {
+		t.Fatal(err)
+	}
+	if cmd1.ID != cmd2.ID {
+		t.Error("expected command to be coalesced")
+	}
+
+	cmds, err := GetCommands(ctx, tx, Q().Where("idempotency_key = ?", key))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cmds) != 1 {
+		t.Error("unexpected commands:", len(cmds))
+	}
+
+	// Once the command is no longer pending, it can be queued again
+	qry := `UPDATE commands SET state = 'success' WHERE id = $1`
+	if _, err := tx.Exec(ctx, qry, cmd1.ID); err != nil {
+		t.Fatal(err)
+	}
+	cmd3 := &Command{Action: "test_key", IdempotencyKey: &key}
+	if err := QueueCommand(ctx, tx, cmd3); err != nil {
+		t.Fatal(err)
+	}
+	if cmd3.ID == cmd1.ID {
+		t.Error("expected a new command")
+	}
+}
+
+func TestCancelCommand(t *testing.T) {
+	ctx := context.Background()
+	tx := beginTest(ctx, t)
+	defer tx.Rollback(ctx) //nolint
+
+	cmd := &Command{
+		Action:    "test_cancel",
+		NotBefore: time.Now().Add(time.Hour),
+	}
+	if err := QueueCommand(ctx, tx, cmd); err != nil {
+		t.Fatal(err)
+	}
+	if err := CancelCommand(ctx, tx, cmd.ID); err != nil {
+		t.Fatal(err)
+	}
+	if err := CancelCommand(ctx, tx, cmd.ID); err != ErrCommandNotPending {
+		t.Error("expected ErrCommandNotPending, got:", err)
+	}
+
+	cmd, err := GetCommand(ctx, tx, Q().Where("id = ?", cmd.ID))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cmd.State != CommandStateCanceled {
+		t.Error("unexpected state:", cmd.State)
+	}
+}
+
+func TestGetCommandQueueStats(t *testing.T) {
+	ctx := context.Background()
+	tx := beginTest(ctx, t)
+	defer tx.Rollback(ctx) //nolint
+
+	if _, err := tx.Exec(ctx, "DELETE FROM commands"); err != nil {
+		t.Fatal(err)
+	}
+	for range 2 {
+		if err := QueueCommand(ctx, tx, &Command{Action: "test_stats"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// Not yet due
+	delayed := &Command{
+		Action:    "test_stats_delayed",
+		NotBefore: time.Now().Add(time.Hour),
+	}
+	if err := QueueCommand(ctx, tx, delayed); err != nil {
+		t.Fatal(err)
+	}
+
+	stats, err := GetCommandQueueStats(ctx, tx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(stats) != 2 {
+		t.Fatal("unexpected stats:", stats)
+	}
+	for _, s := range stats {
+		switch s.Action {
+		case "test_stats":
+			if s.Count != 2 || s.OldestDue == nil {
+				t.Error("unexpected stats:", s)
+			}
+		case "test_stats_delayed":
+			if s.Count != 1 || s.OldestDue != nil {
+				t.Error("unexpected stats:", s)
+			}
+		}
+	}
+}
+
+func TestQueueCommandIdempotencyKeyStarted(t *testing.T) {
+	ctx := context.Background()
+	tx := beginTest(ctx, t)
+	defer tx.Rollback(ctx) //nolint
+
+	key := "test_key_started:42"
+	cmd1 := &Command{Action: "test_key", IdempotencyKey: &key}
+	if err := QueueCommand(ctx, tx, cmd1); err != nil {
+		t.Fatal(err)
+	}
+
+	// The command failed and waits for a retry
+	qry := `UPDATE commands
+	           SET attempts = 1, not_before = NOW() + '1 hour'::interval
+	         WHERE id = $1`
+	if _, err := tx.Exec(ctx, qry, cmd1.ID); err != nil {
+		t.Fatal(err)
+	}
+	cmd2 := &Command{Action: "test_key", IdempotencyKey: &key}
+	if err := QueueCommand(ctx, tx, cmd2); err != nil {
+		t.Fatal(err)
+	}
+	if cmd2.ID == cmd1.ID {
+		t.Error("expected a new command")
+	}
+
+	// The new command is not started and due
+	cmd3 := &Command{Action: "test_key", IdempotencyKey: &key}
+	if err := QueueCommand(ctx, tx, cmd3); err != nil {
+		t.Fatal(err)
+	}
+	if cmd3.ID != cmd2.ID {
+		t.Error("expected command to be coalesced")
+	}
+}
+
+func TestCommandExpirationsHousekeeping(t *testing.T) {
+	ctx := context.Background()
+	tx := beginTest(ctx, t)
+	defer tx.Rollback(ctx) //nolint
+
+	before, err := GetCommandExpirations(ctx, tx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The command is removed by the housekeeping
+	// right after it was inserted.
+	cmd := &Command{
+		Action:   "test_expired",
+		Deadline: time.Now().UTC().Add(-2 * time.Minute),
+	}
+	if err := QueueCommand(ctx, tx, cmd); err != nil {
+		t.Fatal(err)
+	}
+
+	after, err := GetCommandExpirations(ctx, tx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if after["test_expired"] != before["test_expired"]+1 {
+		t.Error("expired command was not counted:", after)
+	}
+}
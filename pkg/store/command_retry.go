@@ -0,0 +1,74 @@
+package store
+
+import (
+	"sync"
+	"time"
+)
+
+// A RetryPolicy defines how often a failed command is
+// attempted and how long to wait between the attempts.
+// The wait time doubles with every attempt, starting with
+// the Backoff, limited by the MaxBackoff.
+type RetryPolicy struct {
+	MaxAttempts int
+	Backoff     time.Duration
+	MaxBackoff  time.Duration
+}
+
+// DefaultRetryPolicy runs a command only once
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts: 1,
+}
+
+// Delay calculates the time to wait before the next
+// attempt after the given number of attempts.
+func (p RetryPolicy) Delay(attempts int) time.Duration {
+	if attempts < 1 {
+		return 0
+	}
+	delay := p.Backoff
+	for i := 1; i < attempts; i++ {
+		delay *= 2
+		if p.MaxBackoff > 0 && delay >= p.MaxBackoff {
+			return p.MaxBackoff
+		}
+	}
+	if p.MaxBackoff > 0 && delay > p.MaxBackoff {
+		return p.MaxBackoff
+	}
+	return delay
+}
+
+// Retries is true if a command is attempted
+// more than once.
+func (p RetryPolicy) Retries() bool {
+	return p.MaxAttempts > 1
+}
+
+var (
+	retryPolicies    = map[string]RetryPolicy{}
+	retryPoliciesMtx sync.RWMutex
+)
+
+// SetCommandRetryPolicies configures the retry
+// policies by command action.
+func SetCommandRetryPolicies(policies map[string]RetryPolicy) {
+	retryPoliciesMtx.Lock()
+	defer retryPoliciesMtx.Unlock()
+	retryPolicies = map[string]RetryPolicy{}
+	for action, p := range policies {
+		retryPolicies[action] = p
+	}
+}
+
+// GetCommandRetryPolicy returns the retry policy
+// for a command action.
+func GetCommandRetryPolicy(action string) RetryPolicy {
+	retryPoliciesMtx.RLock()
+	defer retryPoliciesMtx.RUnlock()
+	p, ok := retryPolicies[action]
+	if !ok || p.MaxAttempts < 1 {
+		return DefaultRetryPolicy
+	}
+	return p
+}
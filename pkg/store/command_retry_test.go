@@ -0,0 +1,68 @@
+package store
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRetryPolicyDelay(t *testing.T) {
+	p := RetryPolicy{
+		MaxAttempts: 5,
+		Backoff:     10 * time.Second,
+		MaxBackoff:  time.Minute,
+	}
+	expected := []time.Duration{
+		0,
+		10 * time.Second,
+		20 * time.Second,
+		40 * time.Second,
+		time.Minute,
+		time.Minute,
+	}
+	for attempts, d := range expected {
+		if delay := p.Delay(attempts); delay != d {
+			t.Error("unexpected delay after", attempts, "attempts:", delay)
+		}
+	}
+}
+
+func TestGetCommandRetryPolicy(t *testing.T) {
+	SetCommandRetryPolicies(map[string]RetryPolicy{
+		"retry_me": {MaxAttempts: 3, Backoff: time.Second},
+	})
+	defer SetCommandRetryPolicies(nil)
+
+	if p := GetCommandRetryPolicy("retry_me"); !p.Retries() {
+		t.Error("expected retries for retry_me")
+	}
+	if p := GetCommandRetryPolicy("once"); p.Retries() {
+		t.Error("unexpected retries:", p)
+	}
+}
+
+func TestNextCommandAttempt(t *testing.T) {
+	SetCommandRetryPolicies(map[string]RetryPolicy{
+		"retry_me": {MaxAttempts: 3, Backoff: time.Second},
+	})
+	defer SetCommandRetryPolicies(nil)
+
+	now := time.Now().UTC()
+	cmd := &Command{Action: "retry_me", Attempts: 2, MaxAttempts: 3}
+	state, notBefore := nextCommandAttempt(cmd, now)
+	if state != CommandStateRequested {
+		t.Error("unexpected state:", state)
+	}
+	if notBefore.Sub(now) != 2*time.Second {
+		t.Error("unexpected backoff:", notBefore.Sub(now))
+	}
+
+	cmd.Attempts = 3
+	if state, _ := nextCommandAttempt(cmd, now); state != CommandStateDead {
+		t.Error("unexpected state:", state)
+	}
+
+	cmd = &Command{Action: "once", Attempts: 1, MaxAttempts: 1}
+	if state, _ := nextCommandAttempt(cmd, now); state != CommandStateError {
+		t.Error("unexpected state:", state)
+	}
+}
@@ -0,0 +1,202 @@
+package store
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/jackc/pgx/v4"
+
+	"github.com/b3scale/b3scale/pkg/cron"
+)
+
+// A CommandSchedule queues a command periodically.
+// The schedule is a cron expression, evaluated in the
+// local time of the b3scale instance.
+type CommandSchedule struct {
+	ID string `json:"id"`
+
+	Action string      `json:"action" doc:"The operation to perform." enum:"end_all_meetings,collect_garbage"`
+	Params interface{} `json:"params" doc:"Key value options for the command."`
+
+	Schedule string `json:"schedule" doc:"A cron expression with minute, hour, day of month, month and day of week, or a shortcut like @daily." example:"0 2 * * *"`
+	Comment  string `json:"comment" doc:"A freeform note about the schedule."`
+
+	NextRunAt time.Time  `json:"next_run_at" doc:"The next time the command is queued."`
+	LastRunAt *time.Time `json:"last_run_at" doc:"The last time the command was queued."`
+
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// GetCommandSchedules retrieves all command schedules
+// matching the query.
+func GetCommandSchedules(
+	ctx context.Context,
+	tx pgx.Tx,
+	q sq.SelectBuilder,
+) ([]*CommandSchedule, error) {
+	qry, params, _ := q.Columns(
+		"command_schedules.id",
+		"command_schedules.action",
+		"command_schedules.params",
+		"command_schedules.schedule",
+		"command_schedules.comment",
+		"command_schedules.next_run_at",
+		"command_schedules.last_run_at",
+		"command_schedules.created_at",
+		"command_schedules.updated_at").
+		From("command_schedules").
+		OrderBy("command_schedules.next_run_at ASC").
+		ToSql()
+	rows, err := tx.Query(ctx, qry, params...)
+	if err != nil {
+		return nil, err
+	}
+	cmd := rows.CommandTag()
+	results := make([]*CommandSchedule, 0, cmd.RowsAffected())
+	for rows.Next() {
+		s := &CommandSchedule{}
+		if err := rows.Scan(
+			&s.ID,
+			&s.Action,
+			&s.Params,
+			&s.Schedule,
+			&s.Comment,
+			&s.NextRunAt,
+			&s.LastRunAt,
+			&s.CreatedAt,
+			&s.UpdatedAt); err != nil {
+			return nil, err
+		}
+		results = append(results, s)
+	}
+	return results, nil
+}
+
+// GetCommandSchedule retrieves a single command
+// schedule. This may return nil without an error.
+func GetCommandSchedule(
+	ctx context.Context,
+	tx pgx.Tx,
+	q sq.SelectBuilder,
+) (*CommandSchedule, error) {
+	schedules, err := GetCommandSchedules(ctx, tx, q)
+	if err != nil {
+		return nil, err
+	}
+	if len(schedules) == 0 {
+		return nil, nil
+	}
+	return schedules[0], nil
+}
+
+// Validate checks the action and the schedule
+func (s *CommandSchedule) Validate() ValidationError {
+	err := ValidationError{}
+	if s.Action == "" {
+		err.Add("action", ErrFieldRequired)
+	}
+	if s.Schedule == "" {
+		err.Add("schedule", ErrFieldRequired)
+	} else if _, perr := cron.Parse(s.Schedule); perr != nil {
+		err.Add("schedule", perr.Error())
+	}
+	if len(err) > 0 {
+		return err
+	}
+	return nil
+}
+
+// Advance calculates the next run after now
+func (s *CommandSchedule) Advance(now time.Time) error {
+	sched, err := cron.Parse(s.Schedule)
+	if err != nil {
+		return err
+	}
+	s.NextRunAt = sched.Next(now).UTC()
+	return nil
+}
+
+// Command creates the next command to queue. The
+// command is not processed before the scheduled time.
+// While it is pending, the command is not queued again.
+func (s *CommandSchedule) Command() *Command {
+	key := "schedule:" + s.ID
+	return &Command{
+		Action:         s.Action,
+		Params:         s.Params,
+		NotBefore:      s.NextRunAt,
+		IdempotencyKey: &key,
+	}
+}
+
+// Save will create or update the command schedule
+func (s *CommandSchedule) Save(
+	ctx context.Context,
+	tx pgx.Tx,
+) error {
+	if s.CreatedAt.IsZero() {
+		return s.insert(ctx, tx)
+	}
+	return s.update(ctx, tx)
+}
+
+// insert creates a new row for the command schedule
+func (s *CommandSchedule) insert(
+	ctx context.Context,
+	tx pgx.Tx,
+) error {
+	params, err := json.Marshal(s.Params)
+	if err != nil {
+		return err
+	}
+	qry := `
+		INSERT INTO command_schedules (
+			action,
+			params,
+			schedule,
+			comment,
+			next_run_at
+		) VALUES ($1, $2, $3, $4, $5)
+		RETURNING id, created_at, updated_at`
+	return tx.QueryRow(ctx, qry,
+		s.Action,
+		params,
+		s.Schedule,
+		s.Comment,
+		s.NextRunAt.UTC()).Scan(&s.ID, &s.CreatedAt, &s.UpdatedAt)
+}
+
+// update the next and last run of the schedule
+func (s *CommandSchedule) update(
+	ctx context.Context,
+	tx pgx.Tx,
+) error {
+	s.UpdatedAt = time.Now().UTC()
+	qry := `
+		UPDATE command_schedules
+		   SET next_run_at = $2,
+		       last_run_at = $3,
+		       comment     = $4,
+		       updated_at  = $5
+		 WHERE id = $1`
+	_, err := tx.Exec(ctx, qry,
+		s.ID,
+		s.NextRunAt.UTC(),
+		s.LastRunAt,
+		s.Comment,
+		s.UpdatedAt)
+	return err
+}
+
+// Delete removes the command schedule
+func (s *CommandSchedule) Delete(
+	ctx context.Context,
+	tx pgx.Tx,
+) error {
+	qry := `DELETE FROM command_schedules WHERE id = $1`
+	_, err := tx.Exec(ctx, qry, s.ID)
+	return err
+}
@@ -0,0 +1,89 @@
+package store
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestCommandScheduleValidate(t *testing.T) {
+	s := &CommandSchedule{}
+	err := s.Validate()
+	if err == nil {
+		t.Fatal("expected validation error")
+	}
+	if _, ok := err["schedule"]; !ok {
+		t.Error("expected schedule error")
+	}
+
+	s = &CommandSchedule{Action: "collect_garbage", Schedule: "61 * * * *"}
+	if err := s.Validate(); err == nil {
+		t.Error("expected invalid schedule")
+	}
+
+	s.Schedule = "@daily"
+	if err := s.Validate(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestCommandScheduleSave(t *testing.T) {
+	ctx := context.Background()
+	tx := beginTest(ctx, t)
+	defer tx.Rollback(ctx) //nolint
+
+	s := &CommandSchedule{
+		Action:   "collect_garbage",
+		Schedule: "0 2 * * *",
+	}
+	now := time.Now()
+	if err := s.Advance(now); err != nil {
+		t.Fatal(err)
+	}
+	if !s.NextRunAt.After(now) {
+		t.Error("next run should be in the future")
+	}
+	if err := s.Save(ctx, tx); err != nil {
+		t.Fatal(err)
+	}
+
+	// Queue the command
+	cmd := s.Command()
+	if err := QueueCommand(ctx, tx, cmd); err != nil {
+		t.Fatal(err)
+	}
+	if !cmd.NotBefore.Equal(s.NextRunAt) {
+		t.Error("unexpected not_before:", cmd.NotBefore)
+	}
+	if !cmd.Deadline.After(cmd.NotBefore) {
+		t.Error("deadline should be after not_before")
+	}
+
+	// Queue the command again while it is pending
+	again := s.Command()
+	if err := QueueCommand(ctx, tx, again); err != nil {
+		t.Fatal(err)
+	}
+	if again.ID != cmd.ID {
+		t.Error("expected the pending command")
+	}
+
+	lastRun := now.UTC()
+	s.LastRunAt = &lastRun
+	if err := s.Save(ctx, tx); err != nil {
+		t.Fatal(err)
+	}
+
+	s, err := GetCommandSchedule(ctx, tx, Q().
+		Where("command_schedules.id = ?", s.ID))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.LastRunAt == nil {
+		t.Error("expected last run")
+	}
+
+	if err := s.Delete(ctx, tx); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,85 @@
+package store
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	// EventsChannel is the notification channel
+	// for publishing events to all instances.
+	EventsChannel = "b3scale_events"
+
+	// eventsListenRetryInterval is the time to wait before
+	// listening again after the connection was lost.
+	eventsListenRetryInterval = 5 * time.Second
+)
+
+// NotifyEvent publishes an encoded event on the events
+// channel. The notification is sent when the transaction
+// is committed.
+func NotifyEvent(
+	ctx context.Context,
+	tx pgx.Tx,
+	payload []byte,
+) error {
+	_, err := tx.Exec(ctx,
+		"SELECT pg_notify($1, $2)", EventsChannel, string(payload))
+	return err
+}
+
+// ListenEvents calls the handler with the payload of each
+// event published on the events channel, until the context
+// is done. When the connection is lost, listening is retried.
+func ListenEvents(
+	ctx context.Context,
+	handler func(payload []byte),
+) {
+	for {
+		err := waitForEvents(ctx, handler)
+		if ctx.Err() != nil {
+			return
+		}
+		log.Error().Err(err).Msg("listening for events failed, retrying")
+
+		select {
+		case <-time.After(eventsListenRetryInterval):
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// waitForEvents uses a dedicated connection
+// for listening on the events channel.
+func waitForEvents(
+	ctx context.Context,
+	handler func(payload []byte),
+) error {
+	pconn, err := Acquire(ctx)
+	if err != nil {
+		return err
+	}
+	// The connection is in listening state and
+	// should not be returned to the pool.
+	conn := pconn.Hijack()
+	defer conn.Close(context.Background()) //nolint
+
+	if _, err := conn.Exec(ctx, "LISTEN "+EventsChannel); err != nil {
+		return err
+	}
+	log.Debug().
+		Str("channel", EventsChannel).
+		Msg("listening for events")
+
+	for {
+		n, err := conn.WaitForNotification(ctx)
+		if err != nil {
+			return err
+		}
+		handler([]byte(n.Payload))
+	}
+}
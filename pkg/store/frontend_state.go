@@ -118,6 +118,35 @@ func GetFrontendStateByID(
 	return GetFrontendState(ctx, tx, Q().Where("frontends.id = ?", id))
 }
 
+// FrontendUsage is the current usage of a frontend
+// in all meetings.
+type FrontendUsage struct {
+	Meetings       int `json:"meetings" doc:"Number of meetings of the frontend."`
+	Attendees      int `json:"attendees" doc:"Number of participants in all meetings of the frontend."`
+	AttendeesLimit int `json:"attendees_limit" doc:"Limit of overall attendees for the frontend. There is no limit if 0."`
+}
+
+// GetFrontendUsage counts the meetings and attendees
+// of a frontend.
+func GetFrontendUsage(
+	ctx context.Context,
+	tx pgx.Tx,
+	frontendID string,
+) (*FrontendUsage, error) {
+	qry := `
+		SELECT COUNT(*),
+		       COALESCE(SUM((state->>'ParticipantCount')::int), 0)
+		  FROM meetings
+		 WHERE frontend_id = $1`
+	usage := &FrontendUsage{}
+	if err := tx.QueryRow(ctx, qry, frontendID).Scan(
+		&usage.Meetings,
+		&usage.Attendees); err != nil {
+		return nil, err
+	}
+	return usage, nil
+}
+
 // Save will create or update a frontend state
 func (s *FrontendState) Save(
 	ctx context.Context,
@@ -134,9 +163,11 @@ func (s *FrontendState) Save(
 func (s *FrontendState) insert(ctx context.Context, tx pgx.Tx) error {
 	qry := `
 		INSERT INTO frontends (
-			key, secret, active, settings, account_ref
+			key, secret, active, settings, account_ref,
+			inactive_since
 		) VALUES (
-			$1, $2, $3, $4, $5
+			$1, $2, $3, $4, $5,
+			CASE WHEN $3 THEN NULL ELSE CURRENT_TIMESTAMP END
 		)
 		RETURNING id, created_at`
 
@@ -168,7 +199,11 @@ func (s *FrontendState) update(ctx context.Context, tx pgx.Tx) error {
 			   active      = $4,
 			   settings    = $5,
 			   account_ref = $6,
-			   updated_at  = $7
+			   updated_at  = $7,
+			   inactive_since = CASE
+				   WHEN $4 THEN NULL
+				   ELSE COALESCE(inactive_since, $7)
+			   END
 		 WHERE id = $1`
 	if _, err := tx.Exec(ctx, qry,
 		s.ID,
@@ -243,18 +278,3 @@ func LookupFrontendIDByMeetingID(
 
 	return frontendID, true, nil
 }
-
-// RemoveStaleFrontendMeetings removes all frontend
-// meetings older than a threshold.
-func RemoveStaleFrontendMeetings(
-	ctx context.Context,
-	tx pgx.Tx,
-	t time.Time,
-) error {
-	qry := `
-		DELETE FROM frontend_meetings
-		 WHERE seen_at < $1
-	`
-	_, err := tx.Exec(ctx, qry, t)
-	return err
-}
@@ -0,0 +1,138 @@
+package store
+
+import (
+	"context"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/jackc/pgx/v4"
+)
+
+// collectGarbage removes all rows of a table matching the
+// condition. In a dry run, the rows are only counted.
+// The result is the number of (affected) rows.
+func collectGarbage(
+	ctx context.Context,
+	tx pgx.Tx,
+	table string,
+	cond sq.Sqlizer,
+	dryRun bool,
+) (int64, error) {
+	if dryRun {
+		qry, params, err := Q().
+			Columns("count(*)").
+			From(table).
+			Where(cond).
+			ToSql()
+		if err != nil {
+			return 0, err
+		}
+		var count int64
+		err = tx.QueryRow(ctx, qry, params...).Scan(&count)
+		return count, err
+	}
+
+	qry, params, err := NewDelete().
+		From(table).
+		Where(cond).
+		ToSql()
+	if err != nil {
+		return 0, err
+	}
+	res, err := tx.Exec(ctx, qry, params...)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected(), nil
+}
+
+// CollectStaleFrontendMeetings removes frontend meeting
+// mappings not seen since t.
+func CollectStaleFrontendMeetings(
+	ctx context.Context,
+	tx pgx.Tx,
+	t time.Time,
+	dryRun bool,
+) (int64, error) {
+	return collectGarbage(ctx, tx, "frontend_meetings",
+		sq.Lt{"seen_at": t}, dryRun)
+}
+
+// CollectFinishedCommands removes commands, which
+// were finished before t. This includes dead commands.
+func CollectFinishedCommands(
+	ctx context.Context,
+	tx pgx.Tx,
+	t time.Time,
+	dryRun bool,
+) (int64, error) {
+	return collectGarbage(ctx, tx, "commands", sq.And{
+		sq.Eq{"state": []string{
+			CommandStateSuccess,
+			CommandStateError,
+			CommandStateDead,
+			CommandStateCanceled,
+		}},
+		sq.Expr("COALESCE(stopped_at, created_at) < ?", t),
+	}, dryRun)
+}
+
+// CollectOrphanMeetings removes meetings without a backend,
+// which were not updated since t. The meetings are archived
+// in the meetings history.
+func CollectOrphanMeetings(
+	ctx context.Context,
+	tx pgx.Tx,
+	t time.Time,
+	dryRun bool,
+) (int64, error) {
+	cond := sq.And{
+		sq.Eq{"backend_id": nil},
+		sq.Lt{"updated_at": t},
+	}
+	if dryRun {
+		return collectGarbage(ctx, tx, "meetings", cond, dryRun)
+	}
+	count, _, err := archiveMeetings(ctx, tx, cond)
+	return count, err
+}
+
+// CollectMeetingsHistory removes archived meetings,
+// which ended before t.
+func CollectMeetingsHistory(
+	ctx context.Context,
+	tx pgx.Tx,
+	t time.Time,
+	dryRun bool,
+) (int64, error) {
+	return collectGarbage(ctx, tx, "meetings_history",
+		sq.Lt{"ended_at": t}, dryRun)
+}
+
+// CollectInactiveFrontends removes frontends, which are
+// inactive since before t. Frontends still having recordings
+// are kept, as removing them would leave the recording
+// files behind.
+func CollectInactiveFrontends(
+	ctx context.Context,
+	tx pgx.Tx,
+	t time.Time,
+	dryRun bool,
+) (int64, error) {
+	return collectGarbage(ctx, tx, "frontends", sq.And{
+		sq.Eq{"active": false},
+		sq.Lt{"inactive_since": t},
+		sq.Expr(`NOT EXISTS (
+			SELECT 1 FROM recordings
+			 WHERE recordings.frontend_id = frontends.id)`),
+	}, dryRun)
+}
+
+// QueryRecordingsOfInactiveFrontends selects all
+// recordings of frontends inactive since before t.
+func QueryRecordingsOfInactiveFrontends(t time.Time) sq.SelectBuilder {
+	return Q().
+		Join("frontends ON frontends.id = recordings.frontend_id").
+		Where("frontends.active = ?", false).
+		Where("frontends.inactive_since < ?", t)
+}
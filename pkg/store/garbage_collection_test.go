@@ -0,0 +1,80 @@
+package store
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestCollectOrphanMeetings(t *testing.T) {
+	ctx := context.Background()
+	tx := beginTest(ctx, t)
+	defer tx.Rollback(ctx) //nolint
+
+	m, err := meetingStateFactory(ctx, tx, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Save(ctx, tx); err != nil {
+		t.Fatal(err)
+	}
+	qry := `UPDATE meetings
+			   SET backend_id = NULL,
+				   updated_at = now() - interval '2 days'
+			 WHERE id = $1`
+	if _, err := tx.Exec(ctx, qry, m.ID); err != nil {
+		t.Fatal(err)
+	}
+	th := time.Now().UTC().Add(-24 * time.Hour)
+
+	// Dry run should only count
+	count, err := CollectOrphanMeetings(ctx, tx, th, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if count < 1 {
+		t.Error("expected at least one orphan meeting")
+	}
+	if s, _ := GetMeetingStateByID(ctx, tx, m.ID); s == nil {
+		t.Error("meeting should not be removed in a dry run")
+	}
+
+	if _, err := CollectOrphanMeetings(ctx, tx, th, false); err != nil {
+		t.Fatal(err)
+	}
+	if s, _ := GetMeetingStateByID(ctx, tx, m.ID); s != nil {
+		t.Error("meeting should be removed")
+	}
+}
+
+func TestCollectInactiveFrontends(t *testing.T) {
+	ctx := context.Background()
+	tx := beginTest(ctx, t)
+	defer tx.Rollback(ctx) //nolint
+
+	f := frontendStateFactory()
+	if err := f.Save(ctx, tx); err != nil {
+		t.Fatal(err)
+	}
+	f.Active = false
+	if err := f.Save(ctx, tx); err != nil {
+		t.Fatal(err)
+	}
+
+	// The frontend was deactivated just now
+	th := time.Now().UTC().Add(-time.Hour)
+	if _, err := CollectInactiveFrontends(ctx, tx, th, false); err != nil {
+		t.Fatal(err)
+	}
+	if s, _ := GetFrontendStateByID(ctx, tx, f.ID); s == nil {
+		t.Fatal("frontend should not be removed yet")
+	}
+
+	th = time.Now().UTC().Add(time.Hour)
+	if _, err := CollectInactiveFrontends(ctx, tx, th, false); err != nil {
+		t.Fatal(err)
+	}
+	if s, _ := GetFrontendStateByID(ctx, tx, f.ID); s != nil {
+		t.Error("frontend should be removed")
+	}
+}
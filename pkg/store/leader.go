@@ -0,0 +1,132 @@
+package store
+
+import (
+	"context"
+	"sync"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/rs/zerolog/log"
+)
+
+// LeaderLockID is the key of the postgres advisory lock
+// held by the cluster leader.
+const LeaderLockID int64 = 0x62337363 // "b3sc"
+
+// A LeaderLock is used for electing a single instance
+// in the cluster for running the periodic background tasks.
+//
+// The leader holds a session level advisory lock on a
+// dedicated connection. When the connection is lost, the
+// lock is released and another instance can take over.
+type LeaderLock struct {
+	// Instance identifies this instance in the cluster
+	Instance string
+
+	conn *pgx.Conn
+	mtx  sync.Mutex
+}
+
+// NewLeaderLock creates a new leader lock for an instance
+func NewLeaderLock(instance string) *LeaderLock {
+	return &LeaderLock{
+		Instance: instance,
+	}
+}
+
+// Acquire tries to become the leader. If the lock is
+// already held, the connection is checked. The result is
+// true if this instance is the leader.
+func (l *LeaderLock) Acquire(ctx context.Context) (bool, error) {
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+
+	if l.conn != nil {
+		if _, err := l.conn.Exec(ctx, "SELECT 1"); err == nil {
+			return true, nil // We are still the leader
+		}
+		log.Warn().
+			Str("instance", l.Instance).
+			Msg("lost connection holding the leader lock")
+		l.conn.Close(context.Background()) //nolint
+		l.conn = nil
+	}
+
+	pconn, err := Acquire(ctx)
+	if err != nil {
+		return false, err
+	}
+	var locked bool
+	qry := `SELECT pg_try_advisory_lock($1)`
+	if err := pconn.QueryRow(ctx, qry, LeaderLockID).Scan(&locked); err != nil {
+		pconn.Release()
+		return false, err
+	}
+	if !locked {
+		pconn.Release()
+		return false, nil
+	}
+
+	// The connection holds the lock and must not
+	// be returned to the pool.
+	conn := pconn.Hijack()
+
+	// Make the leader visible to other instances
+	qry = `SELECT set_config('application_name', $1, false)`
+	if _, err := conn.Exec(ctx, qry, l.Instance); err != nil {
+		conn.Close(context.Background()) //nolint
+		return false, err
+	}
+
+	log.Info().
+		Str("instance", l.Instance).
+		Msg("elected as cluster leader")
+	l.conn = conn
+	return true, nil
+}
+
+// IsLeader is true if this instance holds the lock.
+func (l *LeaderLock) IsLeader() bool {
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+	return l.conn != nil
+}
+
+// Release gives up the leadership by closing
+// the connection holding the lock.
+func (l *LeaderLock) Release(ctx context.Context) {
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+	if l.conn == nil {
+		return
+	}
+	if err := l.conn.Close(ctx); err != nil {
+		log.Error().Err(err).Msg("closing leader connection")
+	}
+	l.conn = nil
+	log.Info().
+		Str("instance", l.Instance).
+		Msg("released cluster leadership")
+}
+
+// GetClusterLeader returns the instance holding the
+// leader lock. The result is empty if there is no leader.
+func GetClusterLeader(ctx context.Context, tx pgx.Tx) (string, error) {
+	qry := `
+		SELECT a.application_name
+		  FROM pg_locks AS l
+		  JOIN pg_stat_activity AS a ON a.pid = l.pid
+		 WHERE l.locktype = 'advisory'
+		   AND l.granted
+		   AND l.classid = 0
+		   AND l.objid::bigint = $1
+		   AND l.objsubid = 1`
+	var instance string
+	err := tx.QueryRow(ctx, qry, LeaderLockID).Scan(&instance)
+	if err == pgx.ErrNoRows {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	return instance, nil
+}
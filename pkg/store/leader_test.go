@@ -0,0 +1,53 @@
+package store
+
+import (
+	"context"
+	"testing"
+)
+
+func TestLeaderLock(t *testing.T) {
+	ctx := context.Background()
+
+	l1 := NewLeaderLock("instance1")
+	l2 := NewLeaderLock("instance2")
+	defer l1.Release(ctx)
+	defer l2.Release(ctx)
+
+	ok, err := l1.Acquire(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !ok {
+		t.Fatal("expected instance1 to be the leader")
+	}
+	ok, err = l2.Acquire(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ok {
+		t.Error("instance2 must not be the leader")
+	}
+
+	tx := beginTest(ctx, t)
+	defer tx.Rollback(ctx) //nolint
+	leader, err := GetClusterLeader(ctx, tx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if leader != "instance1" {
+		t.Error("unexpected leader:", leader)
+	}
+
+	// Take over
+	l1.Release(ctx)
+	if l1.IsLeader() {
+		t.Error("instance1 should not be the leader anymore")
+	}
+	ok, err = l2.Acquire(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !ok {
+		t.Error("expected instance2 to take over")
+	}
+}
@@ -0,0 +1,269 @@
+package store
+
+import (
+	"context"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/jackc/pgx/v4"
+)
+
+// Maintenance window states
+const (
+	MaintenanceWindowScheduled = "scheduled"
+	MaintenanceWindowActive    = "active"
+	MaintenanceWindowFinished  = "finished"
+	MaintenanceWindowCanceled  = "canceled"
+)
+
+// A MaintenanceWindow schedules a change of the admin
+// state of a backend ahead of time. At the start of the
+// window the admin state of the backend is set, at the
+// end the backend is ready again.
+type MaintenanceWindow struct {
+	ID        string `json:"id"`
+	BackendID string `json:"backend_id"`
+
+	AdminState string `json:"admin_state" doc:"The admin state of the backend during the maintenance." example:"draining" enum:"stopped,draining"`
+	State      string `json:"state" doc:"The state of the maintenance window." example:"scheduled" enum:"scheduled,active,finished,canceled"`
+
+	Comment string `json:"comment" doc:"A freeform note about the maintenance." example:"Upgrade to BBB 3.0"`
+
+	StartsAt time.Time `json:"starts_at"`
+	EndsAt   time.Time `json:"ends_at"`
+
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// InitMaintenanceWindow initializes a maintenance
+// window with default values.
+func InitMaintenanceWindow(init *MaintenanceWindow) *MaintenanceWindow {
+	if init.AdminState == "" {
+		init.AdminState = "draining"
+	}
+	if init.State == "" {
+		init.State = MaintenanceWindowScheduled
+	}
+	return init
+}
+
+// GetMaintenanceWindows retrieves all maintenance windows
+// matching the query.
+func GetMaintenanceWindows(
+	ctx context.Context,
+	tx pgx.Tx,
+	q sq.SelectBuilder,
+) ([]*MaintenanceWindow, error) {
+	qry, params, _ := q.Columns(
+		"maintenance_windows.id",
+		"maintenance_windows.backend_id",
+		"maintenance_windows.admin_state",
+		"maintenance_windows.state",
+		"maintenance_windows.comment",
+		"maintenance_windows.starts_at",
+		"maintenance_windows.ends_at",
+		"maintenance_windows.created_at",
+		"maintenance_windows.updated_at").
+		From("maintenance_windows").
+		OrderBy("maintenance_windows.starts_at ASC").
+		ToSql()
+	rows, err := tx.Query(ctx, qry, params...)
+	if err != nil {
+		return nil, err
+	}
+	cmd := rows.CommandTag()
+	results := make([]*MaintenanceWindow, 0, cmd.RowsAffected())
+	for rows.Next() {
+		w := &MaintenanceWindow{}
+		if err := rows.Scan(
+			&w.ID,
+			&w.BackendID,
+			&w.AdminState,
+			&w.State,
+			&w.Comment,
+			&w.StartsAt,
+			&w.EndsAt,
+			&w.CreatedAt,
+			&w.UpdatedAt); err != nil {
+			return nil, err
+		}
+		results = append(results, w)
+	}
+	return results, nil
+}
+
+// GetMaintenanceWindow retrieves a single maintenance
+// window. This may return nil without an error.
+func GetMaintenanceWindow(
+	ctx context.Context,
+	tx pgx.Tx,
+	q sq.SelectBuilder,
+) (*MaintenanceWindow, error) {
+	windows, err := GetMaintenanceWindows(ctx, tx, q)
+	if err != nil {
+		return nil, err
+	}
+	if len(windows) == 0 {
+		return nil, nil
+	}
+	return windows[0], nil
+}
+
+// Save will create or update the maintenance window
+func (w *MaintenanceWindow) Save(
+	ctx context.Context,
+	tx pgx.Tx,
+) error {
+	if w.CreatedAt.IsZero() {
+		return w.insert(ctx, tx)
+	}
+	return w.update(ctx, tx)
+}
+
+// insert creates a new row for the maintenance window
+func (w *MaintenanceWindow) insert(
+	ctx context.Context,
+	tx pgx.Tx,
+) error {
+	qry := `
+		INSERT INTO maintenance_windows (
+			backend_id,
+			admin_state,
+			state,
+			comment,
+			starts_at,
+			ends_at
+		) VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id, created_at, updated_at`
+	return tx.QueryRow(ctx, qry,
+		w.BackendID,
+		w.AdminState,
+		w.State,
+		w.Comment,
+		w.StartsAt.UTC(),
+		w.EndsAt.UTC()).Scan(&w.ID, &w.CreatedAt, &w.UpdatedAt)
+}
+
+// update the state of the maintenance window
+func (w *MaintenanceWindow) update(
+	ctx context.Context,
+	tx pgx.Tx,
+) error {
+	w.UpdatedAt = time.Now().UTC()
+	qry := `
+		UPDATE maintenance_windows
+		   SET state      = $2,
+		       comment    = $3,
+		       updated_at = $4
+		 WHERE id = $1`
+	_, err := tx.Exec(ctx, qry,
+		w.ID,
+		w.State,
+		w.Comment,
+		w.UpdatedAt)
+	return err
+}
+
+// Begin activates the maintenance window and sets the
+// admin state of the backend. Decommissioned backends
+// are not touched.
+func (w *MaintenanceWindow) Begin(
+	ctx context.Context,
+	tx pgx.Tx,
+) error {
+	qry := `
+		UPDATE backends
+		   SET admin_state = $2,
+		       updated_at  = $3
+		 WHERE id = $1
+		   AND admin_state <> 'decommissioned'`
+	if _, err := tx.Exec(ctx, qry,
+		w.BackendID,
+		w.AdminState,
+		time.Now().UTC()); err != nil {
+		return err
+	}
+	w.State = MaintenanceWindowActive
+	return w.Save(ctx, tx)
+}
+
+// End closes the maintenance window with the final state
+// (finished or canceled). If the window was active, the
+// backend is ready again - unless the admin state was
+// changed in the meantime.
+func (w *MaintenanceWindow) End(
+	ctx context.Context,
+	tx pgx.Tx,
+	state string,
+) error {
+	if w.State == MaintenanceWindowActive {
+		qry := `
+			UPDATE backends
+			   SET admin_state = 'ready',
+			       updated_at  = $3
+			 WHERE id = $1
+			   AND admin_state = $2`
+		if _, err := tx.Exec(ctx, qry,
+			w.BackendID,
+			w.AdminState,
+			time.Now().UTC()); err != nil {
+			return err
+		}
+	}
+	w.State = state
+	return w.Save(ctx, tx)
+}
+
+// IsOpen is true if the window is scheduled or active
+func (w *MaintenanceWindow) IsOpen() bool {
+	return w.State == MaintenanceWindowScheduled ||
+		w.State == MaintenanceWindowActive
+}
+
+// HasOverlappingMaintenanceWindow checks if there is an
+// open maintenance window for the backend within the
+// time range of the window.
+func (w *MaintenanceWindow) HasOverlappingMaintenanceWindow(
+	ctx context.Context,
+	tx pgx.Tx,
+) (bool, error) {
+	windows, err := GetMaintenanceWindows(ctx, tx, Q().
+		Where("maintenance_windows.backend_id = ?", w.BackendID).
+		Where(sq.Eq{"maintenance_windows.state": []string{
+			MaintenanceWindowScheduled,
+			MaintenanceWindowActive,
+		}}).
+		Where("maintenance_windows.starts_at < ?", w.EndsAt.UTC()).
+		Where("maintenance_windows.ends_at > ?", w.StartsAt.UTC()))
+	if err != nil {
+		return false, err
+	}
+	return len(windows) > 0, nil
+}
+
+// Validate checks the maintenance window
+func (w *MaintenanceWindow) Validate() ValidationError {
+	err := ValidationError{}
+
+	if w.BackendID == "" {
+		err.Add("backend_id", ErrFieldRequired)
+	}
+	if w.AdminState != "stopped" && w.AdminState != "draining" {
+		err.Add("admin_state", "must be either stopped or draining")
+	}
+	if w.StartsAt.IsZero() {
+		err.Add("starts_at", ErrFieldRequired)
+	}
+	if w.EndsAt.IsZero() {
+		err.Add("ends_at", ErrFieldRequired)
+	}
+	if !w.StartsAt.IsZero() && !w.EndsAt.After(w.StartsAt) {
+		err.Add("ends_at", "must be after starts_at")
+	}
+
+	if len(err) > 0 {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,95 @@
+package store
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func maintenanceWindowFactory(backendID string) *MaintenanceWindow {
+	now := time.Now().UTC()
+	return InitMaintenanceWindow(&MaintenanceWindow{
+		BackendID: backendID,
+		StartsAt:  now.Add(-time.Minute),
+		EndsAt:    now.Add(time.Hour),
+	})
+}
+
+func TestMaintenanceWindowBeginEnd(t *testing.T) {
+	ctx := context.Background()
+	tx := beginTest(ctx, t)
+	defer tx.Rollback(ctx) //nolint
+
+	bstate := backendStateFactory()
+	if err := bstate.Save(ctx, tx); err != nil {
+		t.Fatal(err)
+	}
+
+	w := maintenanceWindowFactory(bstate.ID)
+	if err := w.Save(ctx, tx); err != nil {
+		t.Fatal(err)
+	}
+	if w.ID == "" {
+		t.Error("expected id to be set")
+	}
+
+	overlaps, err := maintenanceWindowFactory(bstate.ID).
+		HasOverlappingMaintenanceWindow(ctx, tx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !overlaps {
+		t.Error("expected overlapping window")
+	}
+
+	if err := w.Begin(ctx, tx); err != nil {
+		t.Fatal(err)
+	}
+	if err := bstate.Refresh(ctx, tx); err != nil {
+		t.Fatal(err)
+	}
+	if bstate.AdminState != "draining" {
+		t.Error("unexpected admin state:", bstate.AdminState)
+	}
+
+	if err := w.End(ctx, tx, MaintenanceWindowFinished); err != nil {
+		t.Fatal(err)
+	}
+	if err := bstate.Refresh(ctx, tx); err != nil {
+		t.Fatal(err)
+	}
+	if bstate.AdminState != "ready" {
+		t.Error("unexpected admin state:", bstate.AdminState)
+	}
+
+	w, err = GetMaintenanceWindow(ctx, tx, Q().
+		Where("maintenance_windows.id = ?", w.ID))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if w.State != MaintenanceWindowFinished {
+		t.Error("unexpected state:", w.State)
+	}
+}
+
+func TestMaintenanceWindowValidate(t *testing.T) {
+	valid := maintenanceWindowFactory("backend")
+	if err := valid.Validate(); err != nil {
+		t.Error(err)
+	}
+
+	invalid := InitMaintenanceWindow(&MaintenanceWindow{
+		AdminState: "ready",
+		StartsAt:   time.Now(),
+		EndsAt:     time.Now().Add(-time.Hour),
+	})
+	err := invalid.Validate()
+	if err == nil {
+		t.Fatal("expected validation error")
+	}
+	for _, field := range []string{"backend_id", "admin_state", "ends_at"} {
+		if _, ok := err[field]; !ok {
+			t.Error("expected error for field:", field)
+		}
+	}
+}
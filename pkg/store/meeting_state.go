@@ -214,111 +214,113 @@ func (s *MeetingState) GetFrontendState(
 }
 
 // DeleteMeetingStateByID will remove a meeting state.
+// The meeting is archived in the meetings history.
 // It will succeed, even if no such meeting was present.
-// TODO: merge with DeleteMeetingStateByInternalID
 func DeleteMeetingStateByID(
 	ctx context.Context,
 	tx pgx.Tx,
 	id string,
 ) error {
-	// Get affected backend
-	var backendID *string
-	qry := `
-		SELECT backend_id FROM meetings WHERE id = $1
-	`
-	if err := tx.
-		QueryRow(ctx, qry, id).
-		Scan(&backendID); err != nil && !errors.Is(err, pgx.ErrNoRows) {
-		return err
-	}
-	qry = `
-		DELETE FROM meetings WHERE id = $1
-	`
-	if _, err := tx.Exec(ctx, qry, id); err != nil {
-		return err
-	}
-
-	if backendID == nil {
-		return nil // Meeting is not associated with a backend
-	}
-
-	// Update stat counters
-	if err := updateBackendStatCounters(ctx, tx, *backendID); err != nil {
-		return err
-	}
-
-	return nil
+	return deleteMeetingStates(ctx, tx, sq.Eq{"id": id})
 }
 
 // DeleteMeetingStateByInternalID will remove a meeting state.
+// The meeting is archived in the meetings history.
 // It will succeed, even if no such meeting was present.
 func DeleteMeetingStateByInternalID(
 	ctx context.Context,
 	tx pgx.Tx,
 	id string,
 ) error {
-	// Get affected backend
-	var backendID *string
-	qry := `
-		SELECT backend_id FROM meetings WHERE id = $1
-	`
-	if err := tx.
-		QueryRow(ctx, qry, id).
-		Scan(&backendID); err != nil && !errors.Is(err, pgx.ErrNoRows) {
-		return err
-	}
+	return deleteMeetingStates(ctx, tx, sq.Eq{"internal_id": id})
+}
 
-	qry = `
-		DELETE FROM meetings WHERE internal_id = $1
-	`
-	if _, err := tx.Exec(ctx, qry, id); err != nil {
+// deleteMeetingStates archives the meetings matching
+// the condition and updates the stat counters of the
+// affected backends.
+func deleteMeetingStates(
+	ctx context.Context,
+	tx pgx.Tx,
+	cond sq.Sqlizer,
+) error {
+	_, backendIDs, err := archiveMeetings(ctx, tx, cond)
+	if err != nil {
 		return err
 	}
-
-	if backendID == nil {
-		return nil // Meeting is not associated with a backend
-	}
-
-	// Update stat counters
-	if err := updateBackendStatCounters(ctx, tx, *backendID); err != nil {
-		return err
+	for _, backendID := range backendIDs {
+		if err := updateBackendStatCounters(ctx, tx, backendID); err != nil {
+			return err
+		}
 	}
-
 	return nil
 }
 
 // DeleteOrphanMeetings will remove all meetings not
 // in a list of (internal) meeting IDs, but associated
-// with a backend
+// with a backend. The meetings are archived in the
+// meetings history.
 func DeleteOrphanMeetings(
 	ctx context.Context,
 	tx pgx.Tx,
 	backendID string,
 	backendMeetings []string,
 ) (int64, error) {
-	// Okay. I tried to do this the nice way... however
-	// trying to construct something with 'NOT IN' failed,
-	// and the only workaround I found was constructing
-	// programmatically a long list of 'AND NOT internal_id = ...'
-	//
-	// I guess this can be optimized.
-	q := NewDelete().
-		From("meetings").
-		Where("backend_id = ?", backendID)
-
-	for _, id := range backendMeetings {
-		q = q.Where("internal_id <> ?", id)
+	cond := sq.And{sq.Eq{"backend_id": backendID}}
+	if len(backendMeetings) > 0 {
+		cond = append(cond, sq.NotEq{"internal_id": backendMeetings})
 	}
-	qry, params, err := q.ToSql()
+	count, _, err := archiveMeetings(ctx, tx, cond)
+	return count, err
+}
+
+// GetStaleMeetingIDs selects up to limit meetings, which were
+// not synced within the threshold, from backends that are ready.
+//
+// Meetings without recent agent events come first, as their
+// state is not kept up to date by the node agent. The selection is spread evenly across the backends
+// by taking the stalest meeting of each backend in turns.
+func GetStaleMeetingIDs(
+	ctx context.Context,
+	tx pgx.Tx,
+	threshold time.Duration,
+	limit int,
+) ([]string, error) {
+	qry := `
+		SELECT id FROM (
+			SELECT meetings.id,
+				   meetings.agent_event_at,
+				   meetings.synced_at,
+				   ROW_NUMBER() OVER (
+					   PARTITION BY meetings.backend_id
+					   ORDER BY meetings.agent_event_at ASC NULLS FIRST,
+								meetings.synced_at ASC
+				   ) AS turn
+			  FROM meetings
+			  JOIN backends ON backends.id = meetings.backend_id
+			 WHERE backends.node_state = 'ready'
+			   AND now() - COALESCE(
+					   meetings.synced_at,
+					   TIMESTAMP '0001-01-01 00:00:00') > $1
+		) AS stale
+		ORDER BY turn ASC,
+				 agent_event_at ASC NULLS FIRST,
+				 synced_at ASC
+		LIMIT $2`
+	rows, err := tx.Query(ctx, qry, threshold, limit)
 	if err != nil {
-		return 0, err
+		return nil, err
 	}
+	defer rows.Close()
 
-	cmd, err := tx.Exec(ctx, qry, params...)
-	if err != nil {
-		return 0, err
+	ids := []string{}
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
 	}
-	return cmd.RowsAffected(), nil
+	return ids, rows.Err()
 }
 
 // Refresh the backend state from the database
@@ -393,6 +395,17 @@ func (s *MeetingState) insert(ctx context.Context, tx pgx.Tx) (string, error) {
 	return s.ID, nil
 }
 
+// MarkAgentEvent records that the meeting state was
+// updated by an event of the node agent.
+func (s *MeetingState) MarkAgentEvent(ctx context.Context, tx pgx.Tx) error {
+	qry := `
+		UPDATE meetings
+		   SET agent_event_at = now()
+		 WHERE id = $1`
+	_, err := tx.Exec(ctx, qry, s.ID)
+	return err
+}
+
 // Update the meeting state
 func (s *MeetingState) update(ctx context.Context, tx pgx.Tx) error {
 	s.UpdatedAt = time.Now().UTC()
@@ -412,3 +412,90 @@ func TestMeetingStateUpdateFrontendMeetingMapping(t *testing.T) {
 		t.Error(err)
 	}
 }
+
+func TestGetStaleMeetingIDs(t *testing.T) {
+	ctx := context.Background()
+	tx := beginTest(ctx, t)
+	defer tx.Rollback(ctx) //nolint
+
+	// Create two backends with two stale meetings each
+	turns := map[string]int{}
+	for b := 0; b < 2; b++ {
+		backend := backendStateFactory()
+		backend.NodeState = "ready"
+		if err := backend.Save(ctx, tx); err != nil {
+			t.Fatal(err)
+		}
+		for i := 1; i <= 2; i++ {
+			m, err := meetingStateFactory(ctx, tx, &MeetingState{
+				ID:         uuid.New().String(),
+				InternalID: uuid.New().String(),
+				backend:    backend,
+				BackendID:  &backend.ID,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := m.Save(ctx, tx); err != nil {
+				t.Fatal(err)
+			}
+			// The second meeting received an agent event, while
+			// the first one was updated more recently by a save.
+			if i == 2 {
+				if err := m.MarkAgentEvent(ctx, tx); err != nil {
+					t.Fatal(err)
+				}
+			}
+			qry := `UPDATE meetings
+					   SET synced_at = now() - interval '1 hour',
+						   updated_at = now() - $2::interval
+					 WHERE id = $1`
+			age := time.Duration(i) * 10 * time.Minute
+			if _, err := tx.Exec(ctx, qry, m.ID, age); err != nil {
+				t.Fatal(err)
+			}
+			turns[m.ID] = i
+		}
+	}
+
+	// A fresh meeting must not be selected
+	fresh, err := meetingStateFactory(ctx, tx, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := fresh.Save(ctx, tx); err != nil {
+		t.Fatal(err)
+	}
+
+	ids, err := GetStaleMeetingIDs(ctx, tx, time.Minute, 10000)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The first meetings of both backends come before
+	// the second ones.
+	seen := []int{}
+	for _, id := range ids {
+		if id == fresh.ID {
+			t.Error("fresh meeting should not be stale")
+		}
+		if turn, ok := turns[id]; ok {
+			seen = append(seen, turn)
+		}
+	}
+	if len(seen) != 4 {
+		t.Fatal("expected 4 stale meetings, got:", seen)
+	}
+	if seen[0] != 1 || seen[1] != 1 || seen[2] != 2 || seen[3] != 2 {
+		t.Error("unexpected order:", seen)
+	}
+
+	// Limit the selection
+	ids, err = GetStaleMeetingIDs(ctx, tx, time.Minute, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ids) != 1 {
+		t.Error("unexpected result:", ids)
+	}
+}
@@ -0,0 +1,262 @@
+package store
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/jackc/pgx/v4"
+)
+
+// Resolutions of the meetings history aggregates
+const (
+	HistoryResolutionHourly = "hourly"
+	HistoryResolutionDaily  = "daily"
+)
+
+// A MeetingHistoryEntry is an archived meeting
+type MeetingHistoryEntry struct {
+	ID                string  `json:"id"`
+	MeetingID         string  `json:"meeting_id" doc:"The BBB meeting ID."`
+	InternalMeetingID *string `json:"internal_meeting_id" doc:"The internal BBB meeting ID."`
+	MeetingName       string  `json:"meeting_name" doc:"The name of the meeting."`
+	FrontendID        *string `json:"frontend_id" doc:"The ID of the frontend."`
+	FrontendKey       *string `json:"frontend_key" doc:"The key of the frontend."`
+	BackendID         *string `json:"backend_id" doc:"The ID of the backend."`
+	BackendHost       string  `json:"backend_host" doc:"The host of the backend."`
+	PeakAttendees     int     `json:"peak_attendees" doc:"The maximum number of attendees at the same time."`
+
+	StartedAt time.Time `json:"started_at" doc:"The meeting was created."`
+	EndedAt   time.Time `json:"ended_at" doc:"The meeting was removed."`
+}
+
+// Duration of the meeting
+func (e *MeetingHistoryEntry) Duration() time.Duration {
+	return e.EndedAt.Sub(e.StartedAt)
+}
+
+// archiveMeetings removes all meetings matching the
+// condition and inserts them into the meetings history.
+// The number of removed meetings and the IDs of the
+// affected backends are returned.
+func archiveMeetings(
+	ctx context.Context,
+	tx pgx.Tx,
+	cond sq.Sqlizer,
+) (int64, []string, error) {
+	del, params, err := NewDelete().
+		From("meetings").
+		Where(cond).
+		Suffix(`RETURNING id, internal_id, state,
+			frontend_id, backend_id, created_at`).
+		ToSql()
+	if err != nil {
+		return 0, nil, err
+	}
+	qry := `
+		WITH deleted AS (` + del + `)
+		INSERT INTO meetings_history (
+			meeting_id,
+			internal_meeting_id,
+			meeting_name,
+			frontend_id,
+			frontend_key,
+			backend_id,
+			backend_host,
+			peak_attendees,
+			started_at
+		)
+		SELECT deleted.id,
+		       deleted.internal_id,
+		       COALESCE(deleted.state->>'MeetingName', ''),
+		       deleted.frontend_id,
+		       frontends.key,
+		       deleted.backend_id,
+		       COALESCE(backends.host, ''),
+		       COALESCE(usage_meetings.peak_attendees, 0),
+		       deleted.created_at
+		  FROM deleted
+		  LEFT JOIN frontends ON frontends.id = deleted.frontend_id
+		  LEFT JOIN backends  ON backends.id = deleted.backend_id
+		  LEFT JOIN usage_meetings
+		         ON usage_meetings.internal_meeting_id = deleted.internal_id
+		RETURNING backend_id`
+	rows, err := tx.Query(ctx, qry, params...)
+	if err != nil {
+		return 0, nil, err
+	}
+	defer rows.Close()
+
+	count := int64(0)
+	seen := map[string]bool{}
+	backendIDs := []string{}
+	for rows.Next() {
+		var backendID *string
+		if err := rows.Scan(&backendID); err != nil {
+			return 0, nil, err
+		}
+		count++
+		if backendID == nil || seen[*backendID] {
+			continue
+		}
+		seen[*backendID] = true
+		backendIDs = append(backendIDs, *backendID)
+	}
+	return count, backendIDs, rows.Err()
+}
+
+// GetMeetingsHistory retrieves the archived meetings
+// matching the query.
+func GetMeetingsHistory(
+	ctx context.Context,
+	tx pgx.Tx,
+	q sq.SelectBuilder,
+) ([]*MeetingHistoryEntry, error) {
+	qry, params, _ := q.Columns(
+		"meetings_history.id",
+		"meetings_history.meeting_id",
+		"meetings_history.internal_meeting_id",
+		"meetings_history.meeting_name",
+		"meetings_history.frontend_id",
+		"meetings_history.frontend_key",
+		"meetings_history.backend_id",
+		"meetings_history.backend_host",
+		"meetings_history.peak_attendees",
+		"meetings_history.started_at",
+		"meetings_history.ended_at").
+		From("meetings_history").
+		OrderBy("meetings_history.started_at ASC").
+		ToSql()
+	rows, err := tx.Query(ctx, qry, params...)
+	if err != nil {
+		return nil, err
+	}
+	cmd := rows.CommandTag()
+	results := make([]*MeetingHistoryEntry, 0, cmd.RowsAffected())
+	for rows.Next() {
+		e := &MeetingHistoryEntry{}
+		if err := rows.Scan(
+			&e.ID,
+			&e.MeetingID,
+			&e.InternalMeetingID,
+			&e.MeetingName,
+			&e.FrontendID,
+			&e.FrontendKey,
+			&e.BackendID,
+			&e.BackendHost,
+			&e.PeakAttendees,
+			&e.StartedAt,
+			&e.EndedAt); err != nil {
+			return nil, err
+		}
+		results = append(results, e)
+	}
+	return results, nil
+}
+
+// QueryMeetingsHistoryRange selects the archived meetings
+// running at some point between from and to.
+func QueryMeetingsHistoryRange(from, to time.Time) sq.SelectBuilder {
+	return Q().
+		Where("meetings_history.ended_at > ?", from).
+		Where("meetings_history.started_at < ?", to)
+}
+
+// A MeetingsHistoryBucket aggregates the meetings
+// of an hour or a day.
+type MeetingsHistoryBucket struct {
+	Time            time.Time `json:"time" doc:"The start of the hour or day."`
+	Meetings        int       `json:"meetings" doc:"The number of meetings started."`
+	PeakConcurrency int       `json:"peak_concurrency" doc:"The maximum number of meetings running at the same time."`
+	AverageDuration float64   `json:"average_duration_minutes" doc:"The average duration in minutes of the meetings started."`
+}
+
+// truncateHistoryTime returns the start of the
+// hour or day of t in UTC.
+func truncateHistoryTime(t time.Time, resolution string) time.Time {
+	t = t.UTC()
+	if resolution == HistoryResolutionHourly {
+		return t.Truncate(time.Hour)
+	}
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// nextHistoryTime returns the start of the
+// following hour or day.
+func nextHistoryTime(t time.Time, resolution string) time.Time {
+	if resolution == HistoryResolutionHourly {
+		return t.Add(time.Hour)
+	}
+	return t.AddDate(0, 0, 1)
+}
+
+// AggregateMeetingsHistory calculates the number of
+// meetings started, the peak concurrency and the average
+// duration per hour or day between from and to.
+func AggregateMeetingsHistory(
+	entries []*MeetingHistoryEntry,
+	from time.Time,
+	to time.Time,
+	resolution string,
+) []*MeetingsHistoryBucket {
+	buckets := []*MeetingsHistoryBucket{}
+	for start := truncateHistoryTime(from, resolution); start.Before(to); {
+		end := nextHistoryTime(start, resolution)
+		buckets = append(buckets, aggregateMeetingsHistoryBucket(
+			entries, start, end))
+		start = end
+	}
+	return buckets
+}
+
+// aggregateMeetingsHistoryBucket aggregates the entries
+// running between start and end.
+func aggregateMeetingsHistoryBucket(
+	entries []*MeetingHistoryEntry,
+	start time.Time,
+	end time.Time,
+) *MeetingsHistoryBucket {
+	type event struct {
+		t     time.Time
+		delta int
+	}
+	bucket := &MeetingsHistoryBucket{
+		Time: start,
+	}
+	events := []event{}
+	total := time.Duration(0)
+	for _, e := range entries {
+		if !e.StartedAt.Before(end) || !e.EndedAt.After(start) {
+			continue // Not running in this bucket
+		}
+		if !e.StartedAt.Before(start) {
+			bucket.Meetings++
+			total += e.Duration()
+		}
+		events = append(events, event{t: e.StartedAt, delta: 1})
+		if e.EndedAt.Before(end) {
+			events = append(events, event{t: e.EndedAt, delta: -1})
+		}
+	}
+	if bucket.Meetings > 0 {
+		bucket.AverageDuration = total.Minutes() / float64(bucket.Meetings)
+	}
+
+	// Meetings ending are counted before meetings
+	// starting at the same time.
+	sort.Slice(events, func(i, j int) bool {
+		if events[i].t.Equal(events[j].t) {
+			return events[i].delta < events[j].delta
+		}
+		return events[i].t.Before(events[j].t)
+	})
+	running := 0
+	for _, ev := range events {
+		running += ev.delta
+		if running > bucket.PeakConcurrency {
+			bucket.PeakConcurrency = running
+		}
+	}
+	return bucket
+}
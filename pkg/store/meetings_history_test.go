@@ -0,0 +1,96 @@
+package store
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestDeleteMeetingStateArchives(t *testing.T) {
+	ctx := context.Background()
+	tx := beginTest(ctx, t)
+	defer tx.Rollback(ctx) //nolint
+
+	m, err := meetingStateFactory(ctx, tx, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Save(ctx, tx); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := DeleteMeetingStateByID(ctx, tx, m.ID); err != nil {
+		t.Fatal(err)
+	}
+	mstate, err := GetMeetingStateByID(ctx, tx, m.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if mstate != nil {
+		t.Error("meeting should be deleted")
+	}
+
+	entries, err := GetMeetingsHistory(ctx, tx, Q().
+		Where("meetings_history.meeting_id = ?", m.ID))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 {
+		t.Fatal("expected an archived meeting, got:", len(entries))
+	}
+	e := entries[0]
+	if e.MeetingName != m.Meeting.MeetingName {
+		t.Error("unexpected meeting name:", e.MeetingName)
+	}
+	if e.BackendHost != m.backend.Backend.Host {
+		t.Error("unexpected backend host:", e.BackendHost)
+	}
+	if e.FrontendKey == nil || *e.FrontendKey != m.frontend.Frontend.Key {
+		t.Error("unexpected frontend key:", e.FrontendKey)
+	}
+}
+
+func TestAggregateMeetingsHistory(t *testing.T) {
+	day := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	at := func(h, m int) time.Time {
+		return day.Add(time.Duration(h)*time.Hour + time.Duration(m)*time.Minute)
+	}
+	entries := []*MeetingHistoryEntry{
+		// Started the day before
+		{StartedAt: at(-1, 0), EndedAt: at(0, 30)},
+		{StartedAt: at(0, 10), EndedAt: at(0, 40)},
+		{StartedAt: at(0, 40), EndedAt: at(1, 40)},
+		{StartedAt: at(2, 0), EndedAt: at(2, 30)},
+	}
+
+	buckets := AggregateMeetingsHistory(
+		entries, at(0, 0), at(3, 0), HistoryResolutionHourly)
+	if len(buckets) != 3 {
+		t.Fatal("unexpected buckets:", len(buckets))
+	}
+
+	b := buckets[0]
+	if b.Meetings != 2 {
+		t.Error("unexpected meetings:", b.Meetings)
+	}
+	if b.PeakConcurrency != 2 {
+		t.Error("unexpected peak concurrency:", b.PeakConcurrency)
+	}
+	if b.AverageDuration != 45 {
+		t.Error("unexpected average duration:", b.AverageDuration)
+	}
+
+	b = buckets[1]
+	if b.Meetings != 0 || b.PeakConcurrency != 1 {
+		t.Error("unexpected bucket:", b)
+	}
+
+	buckets = AggregateMeetingsHistory(
+		entries, at(0, 0), at(3, 0), HistoryResolutionDaily)
+	if len(buckets) != 1 {
+		t.Fatal("unexpected buckets:", len(buckets))
+	}
+	if buckets[0].Meetings != 3 {
+		t.Error("unexpected meetings:", buckets[0].Meetings)
+	}
+}
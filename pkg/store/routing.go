@@ -0,0 +1,77 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v4"
+)
+
+// A RoutingRecord describes how a backend was
+// selected when a meeting was created.
+type RoutingRecord struct {
+	CreatedAt time.Time `json:"created_at" doc:"Time of the backend selection."`
+
+	Steps      []*RoutingStep      `json:"steps" doc:"The routing middlewares in order of execution."`
+	Candidates []*RoutingCandidate `json:"candidates" doc:"The remaining backends in order of preference."`
+
+	BackendID *string `json:"backend_id" doc:"The ID of the selected backend."`
+	Error     string  `json:"error,omitempty" doc:"The reason why no backend could be selected."`
+}
+
+// A RoutingStep records the backends entering
+// a routing middleware and those it removed.
+type RoutingStep struct {
+	Middleware string            `json:"middleware" doc:"The name of the routing middleware." example:"routing.RequiredTags"`
+	Backends   []string          `json:"backends" doc:"Hosts of the backends entering the middleware."`
+	Removed    []*RoutingRemoval `json:"removed" doc:"The backends removed by the middleware."`
+	Error      string            `json:"error,omitempty" doc:"An error returned by the middleware."`
+}
+
+// A RoutingRemoval explains why a backend was removed
+type RoutingRemoval struct {
+	Backend string `json:"backend" doc:"The host of the backend."`
+	Reason  string `json:"reason" doc:"Why the backend was removed." example:"max_meetings reached"`
+}
+
+// A RoutingCandidate is a backend remaining
+// after all routing middlewares were applied.
+type RoutingCandidate struct {
+	BackendID string  `json:"backend_id" doc:"The ID of the backend."`
+	Host      string  `json:"host" doc:"The host of the backend."`
+	Stress    float64 `json:"stress" doc:"The stress of the backend, including the expected attendees if hinted."`
+}
+
+// SetMeetingRouting stores the routing record
+// with the meeting state.
+func SetMeetingRouting(
+	ctx context.Context,
+	tx pgx.Tx,
+	meetingID string,
+	record *RoutingRecord,
+) error {
+	qry := `UPDATE meetings SET routing = $2
+			WHERE id = $1`
+	_, err := tx.Exec(ctx, qry, meetingID, record)
+	return err
+}
+
+// GetMeetingRouting retrieves the routing record of
+// a meeting. If there is no record, nil is returned.
+func GetMeetingRouting(
+	ctx context.Context,
+	tx pgx.Tx,
+	meetingID string,
+) (*RoutingRecord, error) {
+	var record *RoutingRecord
+	qry := `SELECT routing FROM meetings WHERE id = $1`
+	err := tx.QueryRow(ctx, qry, meetingID).Scan(&record)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return record, nil
+}
@@ -0,0 +1,12 @@
+--
+-- Backend Stress Counters
+--
+-- %% Date: 2026-10-17
+-- %% Description: Track video streams and voice participants
+--                  per backend for calculating the stress.
+--
+
+ALTER TABLE backends
+  ADD video_count             INTEGER NOT NULL DEFAULT 0,
+  ADD voice_participant_count INTEGER NOT NULL DEFAULT 0;
+
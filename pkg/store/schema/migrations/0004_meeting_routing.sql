@@ -0,0 +1,11 @@
+--
+-- Meeting Routing
+--
+-- %% Date: 2026-10-17
+-- %% Description: Record how a backend was selected
+--                  when the meeting was created.
+--
+
+ALTER TABLE meetings
+  ADD routing JSONB NULL;
+
@@ -0,0 +1,12 @@
+--
+-- Backend Drain
+--
+-- %% Date: 2026-10-17
+-- %% Description: Track the progress of draining
+--                  a backend.
+--
+
+ALTER TABLE backends
+  ADD drain_started_at     TIMESTAMP NULL,
+  ADD drain_meetings_total INTEGER NOT NULL DEFAULT 0,
+  ADD drain_meetings_ended INTEGER NOT NULL DEFAULT 0;
@@ -0,0 +1,34 @@
+--
+-- Maintenance Windows
+--
+-- %% Date: 2026-10-17
+-- %% Description: Schedule maintenance of backends ahead
+--                  of time.
+--
+
+CREATE TABLE maintenance_windows (
+    id          uuid DEFAULT uuid_generate_v4() PRIMARY KEY,
+
+    backend_id  uuid       NOT NULL
+                REFERENCES backends(id)
+                ON DELETE  CASCADE,
+
+    -- The admin state of the backend during the maintenance:
+    -- This is either 'stopped' or 'draining'.
+    admin_state VARCHAR(20)  NOT NULL DEFAULT 'draining',
+
+    -- The window is 'scheduled', 'active', 'finished'
+    -- or 'canceled'.
+    state       VARCHAR(20)  NOT NULL DEFAULT 'scheduled',
+
+    comment     TEXT         NOT NULL DEFAULT '',
+
+    starts_at   TIMESTAMP    NOT NULL,
+    ends_at     TIMESTAMP    NOT NULL,
+
+    created_at  TIMESTAMP    NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at  TIMESTAMP    NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_maintenance_windows_state
+          ON maintenance_windows (state);
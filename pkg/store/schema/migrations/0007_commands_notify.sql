@@ -0,0 +1,18 @@
+--
+-- Commands Notify
+--
+-- %% Date: 2026-10-17
+-- %% Description: Queued commands are announced by QueueCommand
+--                  through pg_notify, the trigger only cleans up.
+--
+
+CREATE OR REPLACE FUNCTION after_commands_insert() RETURNS TRIGGER AS $$
+BEGIN
+  -- Housekeeping: Remove expired commands.
+  DELETE FROM commands
+   WHERE (deadline + interval '1 minute') 
+         < now() AT TIME ZONE 'utc';
+
+  RETURN NULL;
+END
+$$ LANGUAGE plpgsql;
@@ -0,0 +1,32 @@
+--
+-- Command Retries
+--
+-- %% Date: 2026-10-17
+-- %% Description: Retry failed commands with a backoff and
+--                  keep commands exhausting their retries
+--                  in the dead state.
+--
+
+ALTER TYPE command_state ADD VALUE IF NOT EXISTS 'dead';
+
+ALTER TABLE commands
+  ADD attempts     INTEGER   NOT NULL DEFAULT 0,
+  ADD max_attempts INTEGER   NOT NULL DEFAULT 1,
+  ADD last_error   TEXT      NULL,
+  ADD not_before   TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP;
+
+CREATE INDEX idx_commands_state ON commands (state);
+
+-- Dead commands are kept for inspection and
+-- are not removed when the deadline is reached.
+CREATE OR REPLACE FUNCTION after_commands_insert() RETURNS TRIGGER AS $$
+BEGIN
+  -- Housekeeping: Remove expired commands.
+  DELETE FROM commands
+   WHERE state <> 'dead'
+     AND (deadline + interval '1 minute') 
+         < now() AT TIME ZONE 'utc';
+
+  RETURN NULL;
+END
+$$ LANGUAGE plpgsql;
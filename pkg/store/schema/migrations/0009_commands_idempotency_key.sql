@@ -0,0 +1,15 @@
+--
+-- Commands Idempotency Key
+--
+-- %% Date: 2026-10-17
+-- %% Description: Commands with an idempotency key are queued
+--                  only once while a command with the same key
+--                  is pending.
+--
+
+ALTER TABLE commands
+  ADD idempotency_key TEXT NULL;
+
+CREATE UNIQUE INDEX idx_commands_pending_idempotency_key
+    ON commands (idempotency_key)
+ WHERE state = 'requested';
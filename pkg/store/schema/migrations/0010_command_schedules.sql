@@ -0,0 +1,30 @@
+--
+-- Command Schedules
+--
+-- %% Date: 2026-10-17
+-- %% Description: Queue commands periodically by a cron
+--                  schedule. Pending commands can be canceled.
+--
+
+ALTER TYPE command_state ADD VALUE IF NOT EXISTS 'canceled';
+
+CREATE TABLE command_schedules (
+    id          uuid DEFAULT uuid_generate_v4() PRIMARY KEY,
+
+    -- The command to queue
+    action      VARCHAR(80)  NOT NULL,
+    params      json         NULL,
+
+    -- A cron expression like '0 2 * * *'
+    schedule    VARCHAR(255) NOT NULL,
+    comment     TEXT         NOT NULL DEFAULT '',
+
+    next_run_at TIMESTAMP    NOT NULL,
+    last_run_at TIMESTAMP    NULL,
+
+    created_at  TIMESTAMP    NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at  TIMESTAMP    NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_command_schedules_next_run_at
+    ON command_schedules (next_run_at);
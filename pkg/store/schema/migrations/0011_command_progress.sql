@@ -0,0 +1,39 @@
+--
+-- Command Progress
+--
+-- %% Date: 2026-10-17
+-- %% Description: Running commands report their progress and
+--                  can be asked to cancel. The command row is
+--                  locked while it is processed, so the progress
+--                  is kept in a separate table.
+--
+
+CREATE TABLE command_progress (
+    -- There is no foreign key: The command row is locked
+    -- while the progress is updated.
+    command_id       uuid      PRIMARY KEY,
+
+    progress_result  json      NULL,
+    cancel_requested BOOLEAN   NOT NULL DEFAULT false,
+
+    updated_at       TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE OR REPLACE FUNCTION after_commands_insert() RETURNS TRIGGER AS $$
+BEGIN
+  -- Housekeeping: Remove expired commands.
+  DELETE FROM commands
+   WHERE state <> 'dead'
+     AND (deadline + interval '1 minute') 
+         < now() AT TIME ZONE 'utc';
+
+  -- Remove the progress of commands no longer running.
+  DELETE FROM command_progress
+   WHERE NOT EXISTS (
+         SELECT 1 FROM commands
+          WHERE commands.id = command_progress.command_id
+            AND commands.state = 'requested');
+
+  RETURN NULL;
+END
+$$ LANGUAGE plpgsql;
@@ -0,0 +1,16 @@
+--
+-- Frontends Inactive Since
+--
+-- %% Date: 2026-10-17
+-- %% Description: Track since when a frontend is inactive,
+--                  so inactive frontends and their data can
+--                  be removed after a retention period.
+--
+
+ALTER TABLE frontends
+  ADD inactive_since TIMESTAMP NULL DEFAULT NULL;
+
+-- We do not know better for frontends already inactive
+UPDATE frontends
+   SET inactive_since = updated_at
+ WHERE NOT active;
@@ -0,0 +1,62 @@
+--
+-- Usage Ledger
+--
+-- %% Date: 2026-10-17
+-- %% Description: Keep a record of the usage of each meeting
+--                  for accounting. Meetings are removed when
+--                  they end, the ledger is kept.
+--
+
+CREATE TABLE usage_meetings (
+    id                  uuid DEFAULT uuid_generate_v4() PRIMARY KEY,
+
+    meeting_id          VARCHAR(255) NOT NULL,
+    internal_meeting_id VARCHAR(255) NOT NULL UNIQUE,
+
+    -- The frontend key and the backend host are kept,
+    -- as the frontend or backend may be removed before
+    -- the usage is billed.
+    frontend_id         uuid         NULL
+                        REFERENCES   frontends(id)
+                        ON DELETE    SET NULL,
+    frontend_key        VARCHAR(255) NULL,
+
+    backend_id          uuid         NULL
+                        REFERENCES   backends(id)
+                        ON DELETE    SET NULL,
+    backend_host        TEXT         NOT NULL DEFAULT '',
+
+    started_at          TIMESTAMP    NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    ended_at            TIMESTAMP    NULL,
+
+    peak_attendees      INTEGER      NOT NULL DEFAULT 0,
+    attendee_minutes    FLOAT        NOT NULL DEFAULT 0,
+    video_minutes       FLOAT        NOT NULL DEFAULT 0,
+    recorded            BOOLEAN      NOT NULL DEFAULT false
+);
+
+CREATE INDEX idx_usage_meetings_frontend_started_at
+          ON usage_meetings (frontend_id, started_at);
+CREATE INDEX idx_usage_meetings_started_at
+          ON usage_meetings (started_at);
+
+-- Sessions of attendees and webcams of a running
+-- meeting. When the meeting ends, the durations
+-- are summed up in the ledger and the sessions
+-- are removed.
+CREATE TABLE usage_sessions (
+    usage_id    uuid         NOT NULL
+                REFERENCES   usage_meetings(id)
+                ON DELETE    CASCADE,
+
+    -- The kind is either 'attendee' or 'video'.
+    kind        VARCHAR(20)  NOT NULL,
+    -- The internal user ID or the stream
+    key         VARCHAR(255) NOT NULL,
+
+    started_at  TIMESTAMP    NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    stopped_at  TIMESTAMP    NULL
+);
+
+CREATE INDEX idx_usage_sessions_usage_id
+          ON usage_sessions (usage_id);
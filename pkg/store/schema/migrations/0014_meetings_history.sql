@@ -0,0 +1,36 @@
+--
+-- Meetings History
+--
+-- %% Date: 2026-10-17
+-- %% Description: Archive meetings when they are removed,
+--                  for inspecting past meetings.
+--
+
+CREATE TABLE meetings_history (
+    id                  uuid DEFAULT uuid_generate_v4() PRIMARY KEY,
+
+    meeting_id          VARCHAR(255) NOT NULL,
+    internal_meeting_id VARCHAR(255) NULL,
+    meeting_name        TEXT         NOT NULL DEFAULT '',
+
+    -- There are no foreign keys, as the history
+    -- is kept after a frontend or backend was removed.
+    frontend_id         uuid         NULL,
+    frontend_key        TEXT         NULL,
+    backend_id          uuid         NULL,
+    backend_host        TEXT         NOT NULL DEFAULT '',
+
+    peak_attendees      INTEGER      NOT NULL DEFAULT 0,
+
+    started_at          TIMESTAMP    NOT NULL,
+    ended_at            TIMESTAMP    NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_meetings_history_started_at
+          ON meetings_history (started_at);
+CREATE INDEX idx_meetings_history_ended_at
+          ON meetings_history (ended_at);
+CREATE INDEX idx_meetings_history_frontend_id
+          ON meetings_history (frontend_id);
+CREATE INDEX idx_meetings_history_backend_id
+          ON meetings_history (backend_id);
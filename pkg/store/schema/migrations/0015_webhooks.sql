@@ -0,0 +1,33 @@
+--
+-- Webhooks
+--
+-- %% Date: 2026-10-17
+-- %% Description: Subscriptions for signed HTTP notifications
+--                  about events in the cluster.
+--
+
+CREATE TABLE webhooks (
+    id          uuid DEFAULT uuid_generate_v4() PRIMARY KEY,
+
+    url         TEXT         NOT NULL,
+    secret      VARCHAR(255) NOT NULL,
+
+    -- The subscribed event types. All events are
+    -- delivered if the list is empty.
+    events      TEXT[]       NOT NULL DEFAULT '{}',
+
+    -- Only events of this frontend are delivered.
+    -- Without a frontend, all events are delivered.
+    frontend_id uuid         NULL
+                REFERENCES   frontends(id)
+                ON DELETE    CASCADE,
+
+    active      BOOLEAN      NOT NULL DEFAULT true,
+    comment     TEXT         NOT NULL DEFAULT '',
+
+    created_at  TIMESTAMP    NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at  TIMESTAMP    NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_webhooks_frontend_id
+          ON webhooks (frontend_id);
@@ -0,0 +1,16 @@
+--
+-- Commands Coalesce Due
+--
+-- %% Date: 2026-10-17
+-- %% Description: Commands with an idempotency key are only
+--                  coalesced into pending commands, that are
+--                  not yet started and due. Running commands and
+--                  commands waiting for a retry are still in the
+--                  requested state, so the key is no longer unique.
+--
+
+DROP INDEX idx_commands_pending_idempotency_key;
+
+CREATE INDEX idx_commands_pending_idempotency_key
+    ON commands (idempotency_key)
+ WHERE state = 'requested';
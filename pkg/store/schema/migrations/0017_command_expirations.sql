@@ -0,0 +1,44 @@
+--
+-- Command Expirations
+--
+-- %% Date: 2026-10-17
+-- %% Description: Count commands not processed before their
+--                  deadline, including the commands removed
+--                  by the housekeeping before a worker
+--                  dequeued them.
+--
+
+CREATE TABLE command_expirations (
+    action  VARCHAR(80) PRIMARY KEY,
+    expired BIGINT      NOT NULL DEFAULT 0
+);
+
+CREATE OR REPLACE FUNCTION after_commands_insert() RETURNS TRIGGER AS $$
+BEGIN
+  -- Housekeeping: Remove expired commands. Commands
+  -- which were never processed are counted.
+  WITH removed AS (
+    DELETE FROM commands
+     WHERE state <> 'dead'
+       AND (deadline + interval '1 minute') 
+           < now() AT TIME ZONE 'utc'
+    RETURNING action, state
+  )
+  INSERT INTO command_expirations (action, expired)
+       SELECT action, COUNT(1)
+         FROM removed
+        WHERE state = 'requested'
+     GROUP BY action
+  ON CONFLICT (action) DO UPDATE
+          SET expired = command_expirations.expired + EXCLUDED.expired;
+
+  -- Remove the progress of commands no longer running.
+  DELETE FROM command_progress
+   WHERE NOT EXISTS (
+         SELECT 1 FROM commands
+          WHERE commands.id = command_progress.command_id
+            AND commands.state = 'requested');
+
+  RETURN NULL;
+END
+$$ LANGUAGE plpgsql;
@@ -0,0 +1,11 @@
+--
+-- Meetings Agent Event At
+--
+-- %% Date: 2026-10-17
+-- %% Description: Track when a meeting was last updated by an
+--                  event of the node agent. The updated_at timestamp
+--                  is bumped by every save, including the sync.
+--
+
+ALTER TABLE meetings ADD COLUMN agent_event_at TIMESTAMP NULL;
+
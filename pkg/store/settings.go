@@ -8,9 +8,59 @@ import (
 // for example backend capabilities
 type Tags []string
 
+// StressWeights configure the formula for calculating the
+// stress of a backend. Unset weights fall back to the
+// cluster wide configuration.
+type StressWeights struct {
+	MeetingBaseAttendees *float64 `json:"meeting_base_attendees,omitempty" doc:"Assume that every meeting will eventually have this number of attendees. The number of attendees on the backend is used, when it is bigger."`
+
+	Attendees         *float64 `json:"attendees,omitempty" doc:"Weight of an attendee."`
+	Videos            *float64 `json:"videos,omitempty" doc:"Weight of a video stream."`
+	VoiceParticipants *float64 `json:"voice_participants,omitempty" doc:"Weight of an attendee who joined with audio."`
+	Meetings          *float64 `json:"meetings,omitempty" doc:"Weight of a meeting."`
+	Latency           *float64 `json:"latency,omitempty" doc:"Weight of a millisecond latency, measured when polling the node state."`
+}
+
+// Merge creates a copy of the weights, where all weights
+// set in the update take precedence.
+func (w *StressWeights) Merge(update *StressWeights) *StressWeights {
+	merged := *w
+	if update == nil {
+		return &merged
+	}
+	if update.MeetingBaseAttendees != nil {
+		merged.MeetingBaseAttendees = update.MeetingBaseAttendees
+	}
+	if update.Attendees != nil {
+		merged.Attendees = update.Attendees
+	}
+	if update.Videos != nil {
+		merged.Videos = update.Videos
+	}
+	if update.VoiceParticipants != nil {
+		merged.VoiceParticipants = update.VoiceParticipants
+	}
+	if update.Meetings != nil {
+		merged.Meetings = update.Meetings
+	}
+	if update.Latency != nil {
+		merged.Latency = update.Latency
+	}
+	return &merged
+}
+
 // BackendSettings hold per backend runtime configuration.
 type BackendSettings struct {
 	Tags Tags `json:"tags" doc:"The backend provides these tags. A frontend can require a list of tags. This can be used to dedicate parts of the cluster."`
+
+	MaxMeetings  int `json:"max_meetings" doc:"The backend will not be selected for new meetings, when this number of meetings is reached. Use 0 for no limit."`
+	MaxAttendees int `json:"max_attendees" doc:"The backend will not be selected for new meetings, when this number of attendees is reached. Use 0 for no limit."`
+
+	Zone string `json:"zone" doc:"The data center or region of the backend. Frontends prefer backends in their own zone."`
+
+	Stress *StressWeights `json:"stress,omitempty" doc:"Override the cluster wide weights used for calculating the stress of this backend."`
+
+	DrainWarning string `json:"drain_warning" doc:"This message is posted to the chat of all meetings, when the backend starts draining. Meetings are ended, once they are empty." example:"This server is going into maintenance. Please start a new meeting."`
 }
 
 // DefaultPresentationSettings configure a per frontend
@@ -32,12 +82,45 @@ type RecordingsSettings struct {
 	VisibilityOverride *bbb.RecordingVisibility `json:"visibility_override" doc:"Recordings created by this frontend will have this visibility when imported."`
 }
 
+// RoutingHintsSettings define which routing hints a frontend
+// may pass as meta parameters when creating a meeting.
+type RoutingHintsSettings struct {
+	Allowed              []string `json:"allowed" doc:"Accepted routing hints. Valid hints are expected-attendees and tags."`
+	AllowedTags          Tags     `json:"allowed_tags" doc:"Tags which may be requested with the tags hint. If empty, no tags are allowed."`
+	MaxExpectedAttendees int      `json:"max_expected_attendees" doc:"Upper bound for the expected-attendees hint. Use 0 for no limit."`
+}
+
+// Allows checks if a routing hint is accepted
+func (s *RoutingHintsSettings) Allows(hint string) bool {
+	for _, h := range s.Allowed {
+		if h == hint {
+			return true
+		}
+	}
+	return false
+}
+
+// AllowsTag checks if a tag may be requested. Tags
+// are denied, unless they are in the allow-list.
+func (s *RoutingHintsSettings) AllowsTag(tag string) bool {
+	for _, t := range s.AllowedTags {
+		if t == tag {
+			return true
+		}
+	}
+	return false
+}
+
 // FrontendSettings hold all well known settings for a
 // frontend.
 type FrontendSettings struct {
 	RequiredTags        Tags                         `json:"required_tags" doc:"When selecting a backend for creating a meeting, only consider nodes providing all of the required tags."`
+	PreferredTags       Tags                         `json:"preferred_tags" doc:"When selecting a backend for creating a meeting, prefer nodes providing the most of these tags. Other nodes are used as a fallback."`
+	ForbiddenTags       Tags                         `json:"forbidden_tags" doc:"When selecting a backend for creating a meeting, never consider nodes providing any of these tags."`
 	DefaultPresentation *DefaultPresentationSettings `json:"default_presentation"`
 	AttendeesLimit      *AttendeesLimitSettings      `json:"attendees_limit"`
+	RoutingHints        *RoutingHintsSettings        `json:"routing_hints" doc:"Accept routing hints passed as meta parameters when creating a meeting."`
+	Zone                string                       `json:"zone" doc:"Prefer backends in this zone when creating a meeting. Other zones are only used if no backend is available in the zone."`
 
 	CreateDefaultParams  bbb.Params `json:"create_default_params" doc:"Provide key value params, which will be used as a default when a meeting is created. See the BBB api documentation for which params are valid. The param value must be encoded as string."`
 	CreateOverrideParams bbb.Params `json:"create_override_params" doc:"A key value set of params which will override parameters from the frontend when a meeting is created."`
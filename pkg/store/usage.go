@@ -0,0 +1,266 @@
+package store
+
+import (
+	"context"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/jackc/pgx/v4"
+)
+
+// Kinds of usage sessions
+const (
+	UsageSessionAttendee = "attendee"
+	UsageSessionVideo    = "video"
+)
+
+// A UsageRecord is the accounting ledger entry of
+// a meeting. The record is kept after the meeting
+// was removed.
+type UsageRecord struct {
+	ID                string  `json:"id"`
+	MeetingID         string  `json:"meeting_id" doc:"The BBB meeting ID."`
+	InternalMeetingID string  `json:"internal_meeting_id" doc:"The internal BBB meeting ID."`
+	FrontendID        *string `json:"frontend_id" doc:"The ID of the frontend. This is null if the frontend was removed."`
+	FrontendKey       *string `json:"frontend_key" doc:"The key of the frontend at the start of the meeting."`
+	BackendID         *string `json:"backend_id" doc:"The ID of the backend. This is null if the backend was removed."`
+	BackendHost       string  `json:"backend_host" doc:"The host of the backend running the meeting."`
+
+	StartedAt time.Time  `json:"started_at" doc:"The start of the meeting."`
+	EndedAt   *time.Time `json:"ended_at" doc:"The end of the meeting. This is null while the meeting is running."`
+
+	PeakAttendees   int     `json:"peak_attendees" doc:"The maximum number of attendees at the same time."`
+	AttendeeMinutes float64 `json:"attendee_minutes" doc:"The sum of the minutes each attendee was in the meeting."`
+	VideoMinutes    float64 `json:"video_minutes" doc:"The sum of the minutes each webcam was shared."`
+	Recorded        bool    `json:"recorded" doc:"The meeting was recorded."`
+}
+
+// GetUsageRecords retrieves all usage records
+// matching the query.
+func GetUsageRecords(
+	ctx context.Context,
+	tx pgx.Tx,
+	q sq.SelectBuilder,
+) ([]*UsageRecord, error) {
+	qry, params, _ := q.Columns(
+		"usage_meetings.id",
+		"usage_meetings.meeting_id",
+		"usage_meetings.internal_meeting_id",
+		"usage_meetings.frontend_id",
+		"usage_meetings.frontend_key",
+		"usage_meetings.backend_id",
+		"usage_meetings.backend_host",
+		"usage_meetings.started_at",
+		"usage_meetings.ended_at",
+		"usage_meetings.peak_attendees",
+		"usage_meetings.attendee_minutes",
+		"usage_meetings.video_minutes",
+		"usage_meetings.recorded").
+		From("usage_meetings").
+		OrderBy("usage_meetings.started_at ASC").
+		ToSql()
+	rows, err := tx.Query(ctx, qry, params...)
+	if err != nil {
+		return nil, err
+	}
+	cmd := rows.CommandTag()
+	results := make([]*UsageRecord, 0, cmd.RowsAffected())
+	for rows.Next() {
+		r := &UsageRecord{}
+		if err := rows.Scan(
+			&r.ID,
+			&r.MeetingID,
+			&r.InternalMeetingID,
+			&r.FrontendID,
+			&r.FrontendKey,
+			&r.BackendID,
+			&r.BackendHost,
+			&r.StartedAt,
+			&r.EndedAt,
+			&r.PeakAttendees,
+			&r.AttendeeMinutes,
+			&r.VideoMinutes,
+			&r.Recorded); err != nil {
+			return nil, err
+		}
+		results = append(results, r)
+	}
+	return results, nil
+}
+
+// GetUsageRecordByInternalID retrieves the usage record
+// of a meeting. This may return nil without an error.
+func GetUsageRecordByInternalID(
+	ctx context.Context,
+	tx pgx.Tx,
+	internalID string,
+) (*UsageRecord, error) {
+	records, err := GetUsageRecords(ctx, tx, Q().
+		Where("usage_meetings.internal_meeting_id = ?", internalID))
+	if err != nil {
+		return nil, err
+	}
+	if len(records) == 0 {
+		return nil, nil
+	}
+	return records[0], nil
+}
+
+// OpenUsageRecord starts the accounting of a meeting.
+// If the record already exists, a missing frontend
+// is filled in.
+func OpenUsageRecord(
+	ctx context.Context,
+	tx pgx.Tx,
+	m *MeetingState,
+) error {
+	qry := `
+		INSERT INTO usage_meetings (
+			meeting_id,
+			internal_meeting_id,
+			frontend_id,
+			frontend_key,
+			backend_id,
+			backend_host
+		) VALUES (
+			$1, $2, $3,
+			(SELECT key FROM frontends WHERE id = $3),
+			$4,
+			COALESCE((SELECT host FROM backends WHERE id = $4), '')
+		)
+		ON CONFLICT (internal_meeting_id) DO UPDATE
+		   SET frontend_id  = COALESCE(
+		                        usage_meetings.frontend_id,
+		                        EXCLUDED.frontend_id),
+		       frontend_key = COALESCE(
+		                        usage_meetings.frontend_key,
+		                        EXCLUDED.frontend_key)`
+	_, err := tx.Exec(ctx, qry,
+		m.ID,
+		m.InternalID,
+		m.FrontendID,
+		m.BackendID)
+	return err
+}
+
+// StartUsageSession starts a session of an attendee or
+// webcam, identified by the key, in a running meeting.
+// Starting a session twice has no effect.
+func StartUsageSession(
+	ctx context.Context,
+	tx pgx.Tx,
+	internalMeetingID string,
+	kind string,
+	key string,
+) error {
+	qry := `
+		INSERT INTO usage_sessions (usage_id, kind, key)
+		SELECT id, $2, $3
+		  FROM usage_meetings
+		 WHERE internal_meeting_id = $1
+		   AND ended_at IS NULL
+		   AND NOT EXISTS (
+		         SELECT 1 FROM usage_sessions
+		          WHERE usage_sessions.usage_id = usage_meetings.id
+		            AND usage_sessions.kind = $2
+		            AND usage_sessions.key = $3
+		            AND usage_sessions.stopped_at IS NULL)`
+	if _, err := tx.Exec(ctx, qry, internalMeetingID, kind, key); err != nil {
+		return err
+	}
+	if kind != UsageSessionAttendee {
+		return nil
+	}
+
+	// Keep track of the peak attendees
+	qry = `
+		UPDATE usage_meetings
+		   SET peak_attendees = GREATEST(peak_attendees, (
+		         SELECT count(*) FROM usage_sessions
+		          WHERE usage_sessions.usage_id = usage_meetings.id
+		            AND usage_sessions.kind = $2
+		            AND usage_sessions.stopped_at IS NULL))
+		 WHERE internal_meeting_id = $1`
+	_, err := tx.Exec(ctx, qry, internalMeetingID, kind)
+	return err
+}
+
+// StopUsageSession stops the running session of an
+// attendee or webcam identified by the key.
+func StopUsageSession(
+	ctx context.Context,
+	tx pgx.Tx,
+	internalMeetingID string,
+	kind string,
+	key string,
+) error {
+	qry := `
+		UPDATE usage_sessions
+		   SET stopped_at = CURRENT_TIMESTAMP
+		 WHERE usage_id = (
+		         SELECT id FROM usage_meetings
+		          WHERE internal_meeting_id = $1)
+		   AND kind = $2
+		   AND key = $3
+		   AND stopped_at IS NULL`
+	_, err := tx.Exec(ctx, qry, internalMeetingID, kind, key)
+	return err
+}
+
+// CloseUsageRecord ends the accounting of a meeting.
+// All sessions are stopped and the minutes are summed
+// up in the ledger. Closing a record twice has no effect.
+func CloseUsageRecord(
+	ctx context.Context,
+	tx pgx.Tx,
+	internalMeetingID string,
+	recorded bool,
+) error {
+	qry := `
+		UPDATE usage_meetings
+		   SET ended_at         = CURRENT_TIMESTAMP,
+		       attendee_minutes = attendee_minutes + (
+		         SELECT COALESCE(SUM(EXTRACT(EPOCH FROM
+		                  COALESCE(stopped_at, CURRENT_TIMESTAMP)
+		                    - started_at)), 0) / 60
+		           FROM usage_sessions
+		          WHERE usage_id = usage_meetings.id
+		            AND kind = 'attendee'),
+		       video_minutes    = video_minutes + (
+		         SELECT COALESCE(SUM(EXTRACT(EPOCH FROM
+		                  COALESCE(stopped_at, CURRENT_TIMESTAMP)
+		                    - started_at)), 0) / 60
+		           FROM usage_sessions
+		          WHERE usage_id = usage_meetings.id
+		            AND kind = 'video'),
+		       recorded         = recorded OR $2
+		 WHERE internal_meeting_id = $1
+		   AND ended_at IS NULL`
+	if _, err := tx.Exec(ctx, qry, internalMeetingID, recorded); err != nil {
+		return err
+	}
+
+	// The sessions are accounted for
+	qry = `
+		DELETE FROM usage_sessions
+		 WHERE usage_id = (
+		         SELECT id FROM usage_meetings
+		          WHERE internal_meeting_id = $1)`
+	_, err := tx.Exec(ctx, qry, internalMeetingID)
+	return err
+}
+
+// MarkUsageRecorded flags the usage record of the
+// meeting as recorded.
+func MarkUsageRecorded(
+	ctx context.Context,
+	tx pgx.Tx,
+	internalMeetingID string,
+) error {
+	qry := `
+		UPDATE usage_meetings
+		   SET recorded = true
+		 WHERE internal_meeting_id = $1`
+	_, err := tx.Exec(ctx, qry, internalMeetingID)
+	return err
+}
@@ -0,0 +1,73 @@
+package store
+
+import (
+	"context"
+	"testing"
+)
+
+func TestUsageRecordLifecycle(t *testing.T) {
+	ctx := context.Background()
+	tx := beginTest(ctx, t)
+	defer tx.Rollback(ctx) //nolint
+
+	m, err := meetingStateFactory(ctx, tx, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Save(ctx, tx); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := OpenUsageRecord(ctx, tx, m); err != nil {
+		t.Fatal(err)
+	}
+	// Opening twice is fine
+	if err := OpenUsageRecord(ctx, tx, m); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, user := range []string{"user1", "user2", "user1"} {
+		if err := StartUsageSession(
+			ctx, tx, m.InternalID, UsageSessionAttendee, user,
+		); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := StartUsageSession(
+		ctx, tx, m.InternalID, UsageSessionVideo, "stream1",
+	); err != nil {
+		t.Fatal(err)
+	}
+	if err := StopUsageSession(
+		ctx, tx, m.InternalID, UsageSessionAttendee, "user2",
+	); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := CloseUsageRecord(ctx, tx, m.InternalID, true); err != nil {
+		t.Fatal(err)
+	}
+
+	rec, err := GetUsageRecordByInternalID(ctx, tx, m.InternalID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rec == nil {
+		t.Fatal("expected a usage record")
+	}
+	if rec.PeakAttendees != 2 {
+		t.Error("unexpected peak attendees:", rec.PeakAttendees)
+	}
+	if rec.EndedAt == nil {
+		t.Error("expected ended at to be set")
+	}
+	if !rec.Recorded {
+		t.Error("expected recorded")
+	}
+	if rec.FrontendKey == nil || *rec.FrontendKey != m.frontend.Frontend.Key {
+		t.Error("unexpected frontend key:", rec.FrontendKey)
+	}
+	if rec.BackendHost != m.backend.Backend.Host {
+		t.Error("unexpected backend host:", rec.BackendHost)
+	}
+}
@@ -0,0 +1,261 @@
+package store
+
+import (
+	"context"
+	"net/url"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/jackc/pgx/v4"
+)
+
+// Event types
+const (
+	EventMeetingCreated                = "meeting.created"
+	EventMeetingEnded                  = "meeting.ended"
+	EventAttendeeJoined                = "attendee.joined"
+	EventAttendeeLeft                  = "attendee.left"
+	EventBackendStateChanged           = "backend.state_changed"
+	EventRecordingImported             = "recording.imported"
+	EventFrontendAttendeesLimitReached = "frontend.attendees_limit_reached"
+)
+
+// Event types only published to the events stream.
+// They are too frequent for webhooks.
+const (
+	EventBackendHeartbeat    = "backend.heartbeat"
+	EventCommandStateChanged = "command.state_changed"
+)
+
+// EventTypes are all event types, webhooks
+// can subscribe to.
+var EventTypes = []string{
+	EventMeetingCreated,
+	EventMeetingEnded,
+	EventAttendeeJoined,
+	EventAttendeeLeft,
+	EventBackendStateChanged,
+	EventRecordingImported,
+	EventFrontendAttendeesLimitReached,
+}
+
+// IsEventType checks if the event type is known
+func IsEventType(t string) bool {
+	for _, et := range EventTypes {
+		if et == t {
+			return true
+		}
+	}
+	return false
+}
+
+// A Webhook is a subscription to events in the cluster.
+// The events are posted to the URL, signed with the secret.
+type Webhook struct {
+	ID string `json:"id"`
+
+	URL    string `json:"url" doc:"The events are posted to this URL." example:"https://example.com/b3scale/events"`
+	Secret string `json:"secret" doc:"The shared secret for signing the requests. A secret is generated if empty."`
+
+	Events     []string `json:"events" doc:"The subscribed event types. All events are delivered if empty." example:"meeting.created,meeting.ended"`
+	FrontendID *string  `json:"frontend_id" doc:"Only deliver events of this frontend. All events are delivered if null."`
+
+	Active  bool   `json:"active" doc:"Events are only delivered to active webhooks."`
+	Comment string `json:"comment" doc:"A freeform note about the webhook."`
+
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// InitWebhook initializes a webhook with
+// default values.
+func InitWebhook(init *Webhook) *Webhook {
+	if init.Events == nil {
+		init.Events = []string{}
+	}
+	return init
+}
+
+// GetWebhooks retrieves all webhooks
+// matching the query.
+func GetWebhooks(
+	ctx context.Context,
+	tx pgx.Tx,
+	q sq.SelectBuilder,
+) ([]*Webhook, error) {
+	qry, params, _ := q.Columns(
+		"webhooks.id",
+		"webhooks.url",
+		"webhooks.secret",
+		"webhooks.events",
+		"webhooks.frontend_id",
+		"webhooks.active",
+		"webhooks.comment",
+		"webhooks.created_at",
+		"webhooks.updated_at").
+		From("webhooks").
+		OrderBy("webhooks.created_at ASC").
+		ToSql()
+	rows, err := tx.Query(ctx, qry, params...)
+	if err != nil {
+		return nil, err
+	}
+	cmd := rows.CommandTag()
+	results := make([]*Webhook, 0, cmd.RowsAffected())
+	for rows.Next() {
+		w := &Webhook{}
+		if err := rows.Scan(
+			&w.ID,
+			&w.URL,
+			&w.Secret,
+			&w.Events,
+			&w.FrontendID,
+			&w.Active,
+			&w.Comment,
+			&w.CreatedAt,
+			&w.UpdatedAt); err != nil {
+			return nil, err
+		}
+		results = append(results, w)
+	}
+	return results, nil
+}
+
+// GetWebhook retrieves a single webhook.
+// This may return nil without an error.
+func GetWebhook(
+	ctx context.Context,
+	tx pgx.Tx,
+	q sq.SelectBuilder,
+) (*Webhook, error) {
+	webhooks, err := GetWebhooks(ctx, tx, q)
+	if err != nil {
+		return nil, err
+	}
+	if len(webhooks) == 0 {
+		return nil, nil
+	}
+	return webhooks[0], nil
+}
+
+// GetWebhooksForEvent retrieves all active webhooks
+// subscribed to the event type. If the event is related
+// to a frontend, webhooks of other frontends are skipped.
+func GetWebhooksForEvent(
+	ctx context.Context,
+	tx pgx.Tx,
+	eventType string,
+	frontendID *string,
+) ([]*Webhook, error) {
+	q := Q().
+		Where("webhooks.active").
+		Where("(cardinality(webhooks.events) = 0 OR ? = ANY(webhooks.events))",
+			eventType)
+	if frontendID == nil {
+		q = q.Where("webhooks.frontend_id IS NULL")
+	} else {
+		q = q.Where("(webhooks.frontend_id IS NULL OR webhooks.frontend_id = ?)",
+			*frontendID)
+	}
+	return GetWebhooks(ctx, tx, q)
+}
+
+// Validate checks the URL and the event types
+func (w *Webhook) Validate() ValidationError {
+	err := ValidationError{}
+	if w.URL == "" {
+		err.Add("url", ErrFieldRequired)
+	} else if u, perr := url.Parse(w.URL); perr != nil {
+		err.Add("url", perr.Error())
+	} else if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		err.Add("url", "must be an absolute http or https URL")
+	}
+	if w.Secret == "" {
+		err.Add("secret", ErrFieldRequired)
+	}
+	for _, t := range w.Events {
+		if !IsEventType(t) {
+			err.Add("events", "unknown event type: "+t)
+		}
+	}
+	if len(err) > 0 {
+		return err
+	}
+	return nil
+}
+
+// Save will create or update the webhook
+func (w *Webhook) Save(
+	ctx context.Context,
+	tx pgx.Tx,
+) error {
+	if w.Events == nil {
+		w.Events = []string{}
+	}
+	if w.CreatedAt.IsZero() {
+		return w.insert(ctx, tx)
+	}
+	return w.update(ctx, tx)
+}
+
+// insert creates a new row for the webhook
+func (w *Webhook) insert(
+	ctx context.Context,
+	tx pgx.Tx,
+) error {
+	qry := `
+		INSERT INTO webhooks (
+			url,
+			secret,
+			events,
+			frontend_id,
+			active,
+			comment
+		) VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id, created_at, updated_at`
+	return tx.QueryRow(ctx, qry,
+		w.URL,
+		w.Secret,
+		w.Events,
+		w.FrontendID,
+		w.Active,
+		w.Comment).Scan(&w.ID, &w.CreatedAt, &w.UpdatedAt)
+}
+
+// update the subscription of the webhook
+func (w *Webhook) update(
+	ctx context.Context,
+	tx pgx.Tx,
+) error {
+	w.UpdatedAt = time.Now().UTC()
+	qry := `
+		UPDATE webhooks
+		   SET url         = $2,
+		       secret      = $3,
+		       events      = $4,
+		       frontend_id = $5,
+		       active      = $6,
+		       comment     = $7,
+		       updated_at  = $8
+		 WHERE id = $1`
+	_, err := tx.Exec(ctx, qry,
+		w.ID,
+		w.URL,
+		w.Secret,
+		w.Events,
+		w.FrontendID,
+		w.Active,
+		w.Comment,
+		w.UpdatedAt)
+	return err
+}
+
+// Delete removes the webhook
+func (w *Webhook) Delete(
+	ctx context.Context,
+	tx pgx.Tx,
+) error {
+	qry := `DELETE FROM webhooks WHERE id = $1`
+	_, err := tx.Exec(ctx, qry, w.ID)
+	return err
+}
@@ -0,0 +1,146 @@
+package store
+
+import (
+	"context"
+	"testing"
+)
+
+func TestWebhookValidate(t *testing.T) {
+	w := &Webhook{}
+	err := w.Validate()
+	if err == nil {
+		t.Fatal("expected validation error")
+	}
+	if _, ok := err["url"]; !ok {
+		t.Error("expected url error")
+	}
+
+	w = &Webhook{
+		URL:    "ftp://example.com/events",
+		Secret: "s3cr37",
+	}
+	if err := w.Validate(); err == nil {
+		t.Error("expected invalid url")
+	}
+
+	w.URL = "https://example.com/events"
+	w.Events = []string{EventMeetingCreated, "meeting.exploded"}
+	err = w.Validate()
+	if err == nil {
+		t.Fatal("expected unknown event type")
+	}
+	if _, ok := err["events"]; !ok {
+		t.Error("expected events error")
+	}
+
+	w.Events = []string{EventMeetingCreated}
+	if err := w.Validate(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestWebhookSave(t *testing.T) {
+	ctx := context.Background()
+	tx := beginTest(ctx, t)
+	defer tx.Rollback(ctx) //nolint
+
+	w := InitWebhook(&Webhook{
+		URL:    "https://example.com/events",
+		Secret: "s3cr37",
+		Active: true,
+	})
+	if err := w.Save(ctx, tx); err != nil {
+		t.Fatal(err)
+	}
+	if w.ID == "" {
+		t.Error("expected an ID")
+	}
+
+	w.Events = []string{EventMeetingEnded}
+	if err := w.Save(ctx, tx); err != nil {
+		t.Fatal(err)
+	}
+
+	w, err := GetWebhook(ctx, tx, Q().Where("webhooks.id = ?", w.ID))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(w.Events) != 1 || w.Events[0] != EventMeetingEnded {
+		t.Error("unexpected events:", w.Events)
+	}
+
+	if err := w.Delete(ctx, tx); err != nil {
+		t.Fatal(err)
+	}
+	w, err = GetWebhook(ctx, tx, Q().Where("webhooks.id = ?", w.ID))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if w != nil {
+		t.Error("webhook should be deleted")
+	}
+}
+
+func TestGetWebhooksForEvent(t *testing.T) {
+	ctx := context.Background()
+	tx := beginTest(ctx, t)
+	defer tx.Rollback(ctx) //nolint
+
+	fe := frontendStateFactory()
+	if err := fe.Save(ctx, tx); err != nil {
+		t.Fatal(err)
+	}
+	other := frontendStateFactory()
+	if err := other.Save(ctx, tx); err != nil {
+		t.Fatal(err)
+	}
+
+	all := InitWebhook(&Webhook{
+		URL:    "https://example.com/all",
+		Secret: "s3cr37",
+		Active: true,
+	})
+	ended := InitWebhook(&Webhook{
+		URL:    "https://example.com/ended",
+		Secret: "s3cr37",
+		Events: []string{EventMeetingEnded},
+		Active: true,
+	})
+	otherFrontend := InitWebhook(&Webhook{
+		URL:        "https://example.com/other",
+		Secret:     "s3cr37",
+		FrontendID: &other.ID,
+		Active:     true,
+	})
+	inactive := InitWebhook(&Webhook{
+		URL:    "https://example.com/inactive",
+		Secret: "s3cr37",
+	})
+	for _, w := range []*Webhook{all, ended, otherFrontend, inactive} {
+		if err := w.Save(ctx, tx); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	webhooks, err := GetWebhooksForEvent(
+		ctx, tx, EventMeetingCreated, &fe.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ids := map[string]bool{}
+	for _, w := range webhooks {
+		ids[w.ID] = true
+	}
+	if !ids[all.ID] {
+		t.Error("expected webhook for all events")
+	}
+	if ids[ended.ID] {
+		t.Error("unexpected webhook for other event type")
+	}
+	if ids[otherFrontend.ID] {
+		t.Error("unexpected webhook of other frontend")
+	}
+	if ids[inactive.ID] {
+		t.Error("unexpected inactive webhook")
+	}
+}